@@ -17,12 +17,15 @@ type ToolFunction struct {
 
 type AgentTool struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=built-in;custom
+	// +kubebuilder:validation:Enum=built-in;custom;agent;team
 	Type string `json:"type"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Description of an agent or team tool shown to the model. Defaults to the description of the agent or team.
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Optional
 	Functions []ToolFunction `json:"functions,omitempty"`
 }
 
@@ -46,6 +49,59 @@ type ExecutionEngineRef struct {
 	// Namespace of the ExecutionEngine resource. Defaults to the agent's namespace if not specified
 	Namespace string `json:"namespace,omitempty"`
 }
+
+// ToolExecution controls how the tool calls returned in a single model turn are executed
+type ToolExecution struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=safe;all;none
+	// +kubebuilder:default="safe"
+	// Which tool calls may run concurrently: safe runs tools annotated as read-only or idempotent in parallel
+	// and the rest one at a time, all runs every call in parallel, none runs calls sequentially
+	Parallel string `json:"parallel,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=4
+	// Maximum number of tool calls running at the same time
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+}
+
+// AgentLimits bounds a single execution of the agent. When a limit is reached the execution stops and
+// returns the messages produced so far
+type AgentLimits struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of model calls
+	MaxTurns *int `json:"maxTurns,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tool calls across all turns
+	MaxToolCalls *int `json:"maxToolCalls,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tokens consumed by model calls
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+}
+
+// ContextWindow keeps the messages an agent sends to its model under a token budget
+type ContextWindow struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tokens of the messages sent to the model. Defaults to the maxContextTokens of the model
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=truncate;keep-last;summarize
+	// +kubebuilder:default=truncate
+	// How the messages are fitted under the budget. truncate drops the oldest messages, keep-last keeps the
+	// system prompt and the last keepMessages messages, and summarize replaces the oldest messages with a summary
+	// written by the agent's model
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=10
+	// Number of messages kept by the keep-last strategy
+	KeepMessages int `json:"keepMessages,omitempty"`
+}
+
 type AgentSpec struct {
 	Prompt      string `json:"prompt,omitempty"`
 	Description string `json:"description,omitempty"`
@@ -61,6 +117,16 @@ type AgentSpec struct {
 	// +kubebuilder:validation:Optional
 	// JSON schema for structured output format
 	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ToolExecution configures concurrent execution of tool calls. Defaults to running read-only and idempotent tools in parallel
+	ToolExecution *ToolExecution `json:"toolExecution,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Limits on turns, tool calls and tokens for a single execution. Unset limits are not enforced
+	Limits *AgentLimits `json:"limits,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ContextWindow fits the history sent to the model under a token budget. Without a budget from the agent or
+	// its model the whole history is sent
+	ContextWindow *ContextWindow `json:"contextWindow,omitempty"`
 }
 
 type AgentStatus struct{}
@@ -14,6 +14,14 @@ type ModelConfig struct {
 	Azure *AzureModelConfig `json:"azure,omitempty"`
 	// +kubebuilder:validation:Optional
 	Bedrock *BedrockModelConfig `json:"bedrock,omitempty"`
+	// +kubebuilder:validation:Optional
+	Anthropic *AnthropicModelConfig `json:"anthropic,omitempty"`
+	// +kubebuilder:validation:Optional
+	Gemini *GeminiModelConfig `json:"gemini,omitempty"`
+	// +kubebuilder:validation:Optional
+	Local *LocalModelConfig `json:"local,omitempty"`
+	// +kubebuilder:validation:Optional
+	Pool *PoolModelConfig `json:"pool,omitempty"`
 }
 
 // AzureModelConfig contains Azure OpenAI specific parameters
@@ -61,14 +69,158 @@ type BedrockModelConfig struct {
 	Properties map[string]ValueSource `json:"properties,omitempty"`
 }
 
+// AnthropicModelConfig contains Anthropic Messages API specific parameters
+type AnthropicModelConfig struct {
+	// +kubebuilder:validation:Optional
+	// Base URL of the Anthropic API. Defaults to https://api.anthropic.com
+	BaseURL *ValueSource `json:"baseUrl,omitempty"`
+	// +kubebuilder:validation:Required
+	APIKey ValueSource `json:"apiKey"`
+	// +kubebuilder:validation:Optional
+	// Value of the anthropic-version header. Defaults to 2023-06-01
+	APIVersion *ValueSource `json:"apiVersion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100000
+	MaxTokens *int `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.\d+)?|1(\.0+)?)$
+	Temperature *string `json:"temperature,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// GeminiModelConfig contains Google Gemini and Vertex AI specific parameters
+type GeminiModelConfig struct {
+	// +kubebuilder:validation:Optional
+	// Base URL of the API. Defaults to https://generativelanguage.googleapis.com/v1beta, or to the regional Vertex AI
+	// endpoint when project is set
+	BaseURL *ValueSource `json:"baseUrl,omitempty"`
+	// +kubebuilder:validation:Required
+	// API key of the Gemini API, or an OAuth access token when calling Vertex AI
+	APIKey ValueSource `json:"apiKey"`
+	// +kubebuilder:validation:Optional
+	// Google Cloud project. When set, the model is called through Vertex AI
+	Project *ValueSource `json:"project,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Vertex AI location of the model. Defaults to us-central1
+	Location *ValueSource `json:"location,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100000
+	MaxTokens *int `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.\d+)?|1(\.\d+)?|2(\.0+)?)$
+	Temperature *string `json:"temperature,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// LocalModelConfig contains parameters of a model served by a local Ollama, vLLM or llama.cpp server
+type LocalModelConfig struct {
+	// +kubebuilder:validation:Required
+	// Address of the server, such as http://ollama:11434
+	BaseURL ValueSource `json:"baseUrl"`
+	// +kubebuilder:validation:Optional
+	// API key, for servers that require one
+	APIKey *ValueSource `json:"apiKey,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ollama;vllm;llamacpp
+	// +kubebuilder:default=ollama
+	// Kind of server, which decides how the availability of the model is checked
+	Server string `json:"server,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Timeout of a call to the model. Defaults to 10m, as inference on CPUs can be slow
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +kubebuilder:validation:Optional
+	Properties map[string]ValueSource `json:"properties,omitempty"`
+}
+
+// PoolModelConfig spreads calls across other models that serve the same model, such as deployments in several
+// regions that each have their own quota
+type PoolModelConfig struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Backends []ModelPoolBackend `json:"backends"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=weighted;least-outstanding;remaining-quota
+	// +kubebuilder:default=weighted
+	// How the backend of each call is picked. weighted picks at random in proportion to the weights,
+	// least-outstanding picks the backend with the fewest calls in flight, and remaining-quota picks the backend
+	// with the most tokens left according to the rate limit headers of its last response
+	Strategy string `json:"strategy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// How long a backend that failed with a rate limit or server error is only tried after the healthy ones
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// ModelPoolBackend is a model of a pool
+type ModelPoolBackend struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name of a Model in the namespace of the pool. Pools cannot be backends
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=1
+	// Share of the calls the backend gets with the weighted strategy. Backends with a weight of 0 are kept in
+	// reserve for when the others fail
+	Weight *int `json:"weight,omitempty"`
+}
+
+// ModelPricing is the price of the model's tokens, used to work out the cost of each call. Prices are decimal
+// strings in a single currency of your choice, such as "0.0025" for 0.25 cents per 1K tokens.
+type ModelPricing struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// Price of 1K prompt tokens
+	InputPer1K string `json:"inputPer1K,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// Price of 1K completion tokens
+	OutputPer1K string `json:"outputPer1K,omitempty"`
+}
+
 type ModelSpec struct {
 	// +kubebuilder:validation:Required
 	Model ValueSource `json:"model"`
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=openai;azure;bedrock
+	// +kubebuilder:validation:Enum=openai;azure;bedrock;anthropic;gemini;local;pool
 	Type string `json:"type,omitempty"`
 	// +kubebuilder:validation:Required
 	Config ModelConfig `json:"config"`
+	// +kubebuilder:validation:Optional
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Size of the model's context window in tokens. Agents using the model fit their messages under it
+	MaxContextTokens *int64 `json:"maxContextTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// How calls that fail with a rate limit or server error are retried before moving on to the fallbacks
+	Retry *ModelRetryPolicy `json:"retry,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Models called in order when a call to this model still fails after its retries. The fallbacks of these
+	// models are not followed
+	Fallbacks []AgentModelRef `json:"fallbacks,omitempty"`
+}
+
+// ModelRetryPolicy retries calls with an exponential backoff
+type ModelRetryPolicy struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// +kubebuilder:default=2
+	// Number of times a failed call is retried
+	MaxRetries *int `json:"maxRetries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1s"
+	// Wait before the first retry, doubled for every following retry
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="30s"
+	// Longest wait between two retries
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
 }
 
 type ModelStatus struct {
@@ -77,6 +229,30 @@ type ModelStatus struct {
 	ResolvedAddress string `json:"resolvedAddress,omitempty"`
 	Phase           string `json:"phase,omitempty"`
 	Message         string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether the model is pulled or loaded on its server. Only set for local models
+	Available *bool `json:"available,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Health of the backends of a pool, as seen by the calls made through it
+	Backends []ModelBackendStatus `json:"backends,omitempty"`
+}
+
+// ModelBackendStatus is the health of a backend of a pool
+type ModelBackendStatus struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	// +kubebuilder:validation:Optional
+	// Number of calls in flight
+	OutstandingRequests int64 `json:"outstandingRequests,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Requests left in the current rate limit window, from the headers of the last response
+	RemainingRequests *int64 `json:"remainingRequests,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tokens left in the current rate limit window, from the headers of the last response
+	RemainingTokens *int64 `json:"remainingTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Error of the last failed call, cleared by the next call that succeeds
+	LastError string `json:"lastError,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -3,6 +3,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,13 +32,34 @@ type EvaluatorRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// QueryParameter is a parameter of a query, which can also take the response of another query
+type QueryParameter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name of the parameter (used as template variable)
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// Direct value (mutually exclusive with valueFrom)
+	Value string `json:"value,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Reference to external sources (mutually exclusive with value)
+	ValueFrom *QueryParameterSource `json:"valueFrom,omitempty"`
+}
+
+type QueryParameterSource struct {
+	ValueFromSource `json:",inline"`
+	// +kubebuilder:validation:Optional
+	// Response of another query. The query waits for the referenced query to be done before it runs.
+	QueryRef *QueryRef `json:"queryRef,omitempty"`
+}
+
 type QuerySpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Input string `json:"input"`
 	// +kubebuilder:validation:Optional
 	// Parameters for template processing in the input field
-	Parameters []Parameter `json:"parameters,omitempty"`
+	Parameters []QueryParameter `json:"parameters,omitempty"`
 	// +kubebuilder:validation:Optional
 	Targets []QueryTarget `json:"targets,omitempty"`
 	// +kubebuilder:validation:Optional
@@ -63,11 +85,102 @@ type QuerySpec struct {
 	// +kubebuilder:validation:Optional
 	// When true, indicates intent to cancel the query
 	Cancel bool `json:"cancel,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When true, partial responses are published to status.partialResponses while targets execute
+	Stream bool `json:"stream,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Decisions for tool calls listed in status.pendingApprovals. A decision only applies to a call that is
+	// pending when it is added.
+	Approvals []ToolApproval `json:"approvals,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When true, the full message transcript of each target is kept in a ConfigMap referenced from its response
+	Transcript bool `json:"transcript,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=true
+	// When false, progress is not checkpointed and a query interrupted by a controller restart starts over
+	Checkpoint *bool `json:"checkpoint,omitempty"`
+}
+
+// ToolApproval approves or denies a tool call that is waiting for approval
+type ToolApproval struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ToolCallID string `json:"toolCallId"`
+	// +kubebuilder:validation:Required
+	// Target making the call, as listed in status.pendingApprovals
+	Target QueryTarget `json:"target"`
+	// +kubebuilder:validation:Optional
+	// Agent making the call, as listed in status.pendingApprovals
+	Agent string `json:"agent,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=approve;deny
+	Decision string `json:"decision"`
+	// +kubebuilder:validation:Optional
+	// Reason is passed to the agent when the call is denied
+	Reason string `json:"reason,omitempty"`
 }
 
 type Response struct {
 	Target  QueryTarget `json:"target,omitempty"`
 	Content string      `json:"content,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=done;error
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Why the target failed, set when phase is error
+	Error string `json:"error,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=MaxTurns;MaxToolCalls;MaxTokens
+	// Set when the target stopped early because an agent execution limit was reached
+	TerminationReason string `json:"terminationReason,omitempty"`
+	// +kubebuilder:validation:Optional
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost of the target's model calls
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of messages the target produced
+	MessageCount int `json:"messageCount,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ConfigMap key holding the target's transcript, set when spec.transcript is enabled
+	TranscriptRef *corev1.ConfigMapKeySelector `json:"transcriptRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Notes the team members wrote to the shared scratchpad
+	Scratchpad map[string]string `json:"scratchpad,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Models that answered the target's model calls, which include fallbacks when a model failed over
+	Models []string `json:"models,omitempty"`
+}
+
+type ToolCallProgress struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Arguments string `json:"arguments,omitempty"`
+	// +kubebuilder:validation:Enum=running;completed;error
+	Phase string `json:"phase"`
+}
+
+// PendingApproval is a tool call that will not run until it is approved through spec.approvals
+type PendingApproval struct {
+	ToolCallID  string      `json:"toolCallId"`
+	Target      QueryTarget `json:"target"`
+	Agent       string      `json:"agent,omitempty"`
+	Tool        string      `json:"tool"`
+	Arguments   string      `json:"arguments,omitempty"`
+	RequestedAt metav1.Time `json:"requestedAt"`
+}
+
+// PartialResponse holds the output streamed so far for a target that is still executing
+type PartialResponse struct {
+	Target QueryTarget `json:"target"`
+	// Agent or model currently producing output for the target
+	Source string `json:"source,omitempty"`
+	// Index of the assistant message being streamed; content is reset whenever it changes
+	Message   int                `json:"message"`
+	Content   string             `json:"content,omitempty"`
+	ToolCalls []ToolCallProgress `json:"toolCalls,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -100,13 +213,20 @@ type TokenUsage struct {
 
 type QueryStatus struct {
 	// +kubebuilder:default="pending"
-	// +kubebuilder:validation:Enum=pending;running;evaluating;error;done;canceled
+	// +kubebuilder:validation:Enum=pending;running;awaiting-approval;evaluating;error;done;canceled
 	Phase       string             `json:"phase,omitempty"`
 	Responses   []Response         `json:"responses,omitempty"`
 	Evaluations []EvaluationResult `json:"evaluations,omitempty"`
 	TokenUsage  TokenUsage         `json:"tokenUsage,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Cost of every model call the query made
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Optional
+	PartialResponses []PartialResponse `json:"partialResponses,omitempty"`
+	// +kubebuilder:validation:Optional
+	PendingApprovals []PendingApproval `json:"pendingApprovals,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,103 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QueryTemplate describes the queries a QuerySchedule creates
+type QueryTemplate struct {
+	// +kubebuilder:validation:Optional
+	// Labels added to each query
+	Labels map[string]string `json:"labels,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Annotations added to each query
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// +kubebuilder:validation:Required
+	Spec QuerySpec `json:"spec"`
+}
+
+// QueryScheduleSpec defines the desired state of QuerySchedule.
+type QueryScheduleSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Cron expression in the standard five-field format, or a macro such as @daily
+	Schedule string `json:"schedule"`
+	// +kubebuilder:validation:Optional
+	// IANA time zone the schedule is evaluated in, such as Europe/London. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Allow
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// What to do when a run is due while the query of an earlier run is still running
+	ConcurrencyPolicy string `json:"concurrencyPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When true, no further queries are created
+	Suspend bool `json:"suspend,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// How late a run may start after its scheduled time; runs missed by more are skipped
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=3
+	// +kubebuilder:validation:Minimum=0
+	// Number of completed queries to keep
+	SuccessfulQueriesHistoryLimit *int32 `json:"successfulQueriesHistoryLimit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=1
+	// +kubebuilder:validation:Minimum=0
+	// Number of failed or canceled queries to keep
+	FailedQueriesHistoryLimit *int32 `json:"failedQueriesHistoryLimit,omitempty"`
+	// +kubebuilder:validation:Required
+	QueryTemplate QueryTemplate `json:"queryTemplate"`
+}
+
+// QueryScheduleStatus defines the observed state of QuerySchedule.
+type QueryScheduleStatus struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ready;error
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Queries created by the schedule that have not finished yet
+	Active []string `json:"active,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.schedule`
+// +kubebuilder:printcolumn:name="Suspend",type=boolean,JSONPath=`.spec.suspend`
+// +kubebuilder:printcolumn:name="Last Schedule",type=date,JSONPath=`.status.lastScheduleTime`
+// +kubebuilder:printcolumn:name="Next Schedule",type=string,JSONPath=`.status.nextScheduleTime`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// QuerySchedule creates queries from a template on a cron schedule.
+type QuerySchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QueryScheduleSpec   `json:"spec,omitempty"`
+	Status QueryScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QueryScheduleList contains a list of QuerySchedule.
+type QueryScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QuerySchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&QuerySchedule{}, &QueryScheduleList{})
+}
@@ -14,17 +14,79 @@ type TeamMember struct {
 type TeamSelectorSpec struct {
 	Model          string `json:"model,omitempty"`
 	SelectorPrompt string `json:"selectorPrompt,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=5
+	// Number of times the selector model is asked again when its reply does not name a member. Defaults to 1.
+	MaxRetries *int `json:"maxRetries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=first;next;fail
+	// +kubebuilder:default=first
+	// What happens when no member is selected after the retries. first selects the first member, next selects
+	// the member after the previous one, and fail fails the team.
+	Fallback string `json:"fallback,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Lets the selector model end the team by replying TERMINATE when the task is complete
+	AllowTermination bool `json:"allowTermination,omitempty"`
+}
+
+// TeamGraphEdgeCondition decides whether an edge is followed, based on the last message of the member the edge
+// leaves. Every field that is set must hold.
+type TeamGraphEdgeCondition struct {
+	// +kubebuilder:validation:Optional
+	// Text the last message must contain
+	Contains string `json:"contains,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Regular expression the last message must match
+	Matches string `json:"matches,omitempty"`
+	// +kubebuilder:validation:Optional
+	// jq expression run over the last message parsed as JSON. It holds when the expression returns true.
+	JQ string `json:"jq,omitempty"`
+	// +kubebuilder:validation:Optional
+	// When to follow the edge, in natural language. The team's selector model chooses among the outgoing edges
+	// with a description whose other conditions hold.
+	Description string `json:"description,omitempty"`
 }
 
 type TeamGraphEdge struct {
 	From string `json:"from"`
 	To   string `json:"to"`
+	// +kubebuilder:validation:Optional
+	// Edges without a condition are followed when no conditional edge from the same member holds
+	Condition *TeamGraphEdgeCondition `json:"condition,omitempty"`
 }
 
 type TeamGraphSpec struct {
 	Edges []TeamGraphEdge `json:"edges"`
 }
 
+// TeamParallelSpec configures how the parallel strategy combines the responses of its members
+type TeamParallelSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=concat;aggregator;model;vote
+	// +kubebuilder:default=concat
+	// How the member responses are combined. concat joins them, aggregator hands them to an agent or team, model
+	// asks a model to combine them, and vote returns the response most members gave
+	Aggregation string `json:"aggregation,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Agent or team that combines the responses when aggregation is aggregator
+	Aggregator *TeamMember `json:"aggregator,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Model that combines the responses when aggregation is model. Defaults to the default model.
+	Model string `json:"model,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Instructions for the model that combines the responses
+	Prompt string `json:"prompt,omitempty"`
+}
+
+// TeamPlannerSpec configures the manager of the planner strategy
+type TeamPlannerSpec struct {
+	// +kubebuilder:validation:Required
+	// Agent that breaks the input into subtasks, delegates each to a member and calls terminate with the final
+	// response
+	Manager string `json:"manager"`
+}
+
 type TeamSpec struct {
 	Members     []TeamMember      `json:"members"`
 	Strategy    string            `json:"strategy"`
@@ -32,6 +94,8 @@ type TeamSpec struct {
 	MaxTurns    *int              `json:"maxTurns,omitempty"`
 	Selector    *TeamSelectorSpec `json:"selector,omitempty"`
 	Graph       *TeamGraphSpec    `json:"graph,omitempty"`
+	Parallel    *TeamParallelSpec `json:"parallel,omitempty"`
+	Planner     *TeamPlannerSpec  `json:"planner,omitempty"`
 }
 
 type TeamStatus struct{}
@@ -0,0 +1,108 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// TokenBudgetScope selects the queries whose usage a TokenBudget counts and limits
+type TokenBudgetScope struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=namespace
+	// +kubebuilder:validation:Enum=namespace;serviceAccount;agent
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Name of the service account or agent, required unless the type is namespace
+	Name string `json:"name,omitempty"`
+}
+
+// TokenBudgetSpec defines the desired state of TokenBudget.
+type TokenBudgetSpec struct {
+	// +kubebuilder:validation:Optional
+	Scope TokenBudgetScope `json:"scope,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="24h"
+	// Rolling window over which usage is counted
+	Window *metav1.Duration `json:"window,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// Cost limit, in the currency of the models' pricing
+	MaxCost string `json:"maxCost,omitempty"`
+}
+
+// TokenBudgetConsumer is what one query or workflow has used of a TokenBudget
+type TokenBudgetConsumer struct {
+	// +kubebuilder:validation:Required
+	// UID of the query or workflow
+	UID types.UID `json:"uid"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Query;Workflow
+	Kind string `json:"kind,omitempty"`
+	// +kubebuilder:validation:Optional
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Required
+	// Creation time of the query or workflow, its usage leaves the window counted from then
+	Started metav1.Time `json:"started"`
+	// +kubebuilder:validation:Optional
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	Cost string `json:"cost,omitempty"`
+}
+
+// TokenBudgetStatus defines the observed state of TokenBudget.
+type TokenBudgetStatus struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ready;exceeded;error
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tokens used by the queries in scope within the current window
+	UsedTokens int64 `json:"usedTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost of the queries in scope within the current window
+	UsedCost string `json:"usedCost,omitempty"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Usage of each query and workflow in scope within the window, recorded as each model call completes so that it
+	// counts while they run and after they are deleted
+	Consumers []TokenBudgetConsumer `json:"consumers,omitempty"`
+	// +kubebuilder:validation:Optional
+	LastUpdated *metav1.Time `json:"lastUpdated,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Scope",type=string,JSONPath=`.spec.scope.type`
+// +kubebuilder:printcolumn:name="Used Tokens",type=integer,JSONPath=`.status.usedTokens`
+// +kubebuilder:printcolumn:name="Max Tokens",type=integer,JSONPath=`.spec.maxTokens`
+// +kubebuilder:printcolumn:name="Used Cost",type=string,JSONPath=`.status.usedCost`
+// +kubebuilder:printcolumn:name="Max Cost",type=string,JSONPath=`.spec.maxCost`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// TokenBudget caps the tokens or cost that queries in its namespace may use over a rolling window.
+type TokenBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TokenBudgetSpec   `json:"spec,omitempty"`
+	Status TokenBudgetStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TokenBudgetList contains a list of TokenBudget.
+type TokenBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TokenBudget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TokenBudget{}, &TokenBudgetList{})
+}
@@ -0,0 +1,123 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkflowStep runs one agent, team, model or tool once the steps it depends on are done
+type WorkflowStep struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^[a-zA-Z][a-zA-Z0-9_]*$
+	// Name of the step, used in dependsOn and as {{.steps.<name>}} in input templates
+	Name string `json:"name"`
+	// +kubebuilder:validation:Required
+	Target QueryTarget `json:"target"`
+	// +kubebuilder:validation:Optional
+	// Steps that must be done before this step runs. Steps without dependencies run as soon as the workflow starts.
+	DependsOn []string `json:"dependsOn,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Input template for the step. It can use {{.input}} for the workflow input, {{.steps.<name>}} for the
+	// output of a step it depends on, and the workflow parameters. Defaults to the workflow input for steps
+	// without dependencies, and to the outputs of the steps it depends on otherwise.
+	Input string `json:"input,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=10
+	// Number of times a failed step is retried
+	Retries int32 `json:"retries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Timeout of each attempt of the step (e.g., "30s", "5m"). Defaults to 5m.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// WorkflowSpec defines the desired state of Workflow.
+type WorkflowSpec struct {
+	// +kubebuilder:validation:Required
+	Input string `json:"input"`
+	// +kubebuilder:validation:Optional
+	// Parameters for template processing in the workflow input and step inputs
+	Parameters []Parameter `json:"parameters,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Steps []WorkflowStep `json:"steps"`
+	// +kubebuilder:validation:Optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Timeout for the whole workflow. Steps still running when it expires fail.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +kubebuilder:default="720h"
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+// WorkflowStepStatus reports the execution of a step
+type WorkflowStepStatus struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=pending;running;done;error;skipped
+	Phase string `json:"phase"`
+	// +kubebuilder:validation:Optional
+	Attempts int32 `json:"attempts,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Content of the step's final message, used as the input of the steps that depend on it
+	Content string `json:"content,omitempty"`
+	// +kubebuilder:validation:Optional
+	Error string `json:"error,omitempty"`
+	// +kubebuilder:validation:Optional
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// WorkflowStatus defines the observed state of Workflow.
+type WorkflowStatus struct {
+	// +kubebuilder:default="pending"
+	// +kubebuilder:validation:Enum=pending;running;done;error
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	Steps []WorkflowStepStatus `json:"steps,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Output of the steps no other step depends on
+	Output string `json:"output,omitempty"`
+	// +kubebuilder:validation:Optional
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Duration",type=string,JSONPath=`.status.duration`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Workflow runs a graph of agent, team, model and tool steps, passing the output of each step to the steps that
+// depend on it.
+type Workflow struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   WorkflowSpec   `json:"spec,omitempty"`
+	Status WorkflowStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// WorkflowList contains a list of Workflow.
+type WorkflowList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Workflow `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Workflow{}, &WorkflowList{})
+}
@@ -39,6 +39,36 @@ func (in *Agent) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentLimits) DeepCopyInto(out *AgentLimits) {
+	*out = *in
+	if in.MaxTurns != nil {
+		in, out := &in.MaxTurns, &out.MaxTurns
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxToolCalls != nil {
+		in, out := &in.MaxToolCalls, &out.MaxToolCalls
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentLimits.
+func (in *AgentLimits) DeepCopy() *AgentLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentList) DeepCopyInto(out *AgentList) {
 	*out = *in
@@ -118,6 +148,21 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ToolExecution != nil {
+		in, out := &in.ToolExecution, &out.ToolExecution
+		*out = new(ToolExecution)
+		**out = **in
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(AgentLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContextWindow != nil {
+		in, out := &in.ContextWindow, &out.ContextWindow
+		*out = new(ContextWindow)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -165,6 +210,49 @@ func (in *AgentTool) DeepCopy() *AgentTool {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnthropicModelConfig) DeepCopyInto(out *AnthropicModelConfig) {
+	*out = *in
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.APIVersion != nil {
+		in, out := &in.APIVersion, &out.APIVersion
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int)
+		**out = **in
+	}
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(string)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnthropicModelConfig.
+func (in *AnthropicModelConfig) DeepCopy() *AnthropicModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(AnthropicModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureModelConfig) DeepCopyInto(out *AzureModelConfig) {
 	*out = *in
@@ -389,6 +477,26 @@ func (in *ChildEvaluationStatus) DeepCopy() *ChildEvaluationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContextWindow) DeepCopyInto(out *ContextWindow) {
+	*out = *in
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContextWindow.
+func (in *ContextWindow) DeepCopy() *ContextWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ContextWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectEvaluationConfig) DeepCopyInto(out *DirectEvaluationConfig) {
 	*out = *in
@@ -796,6 +904,54 @@ func (in *HTTPSpec) DeepCopy() *HTTPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeminiModelConfig) DeepCopyInto(out *GeminiModelConfig) {
+	*out = *in
+	if in.BaseURL != nil {
+		in, out := &in.BaseURL, &out.BaseURL
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.Project != nil {
+		in, out := &in.Project, &out.Project
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Location != nil {
+		in, out := &in.Location, &out.Location
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int)
+		**out = **in
+	}
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(string)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeminiModelConfig.
+func (in *GeminiModelConfig) DeepCopy() *GeminiModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(GeminiModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Header) DeepCopyInto(out *Header) {
 	*out = *in
@@ -852,6 +1008,39 @@ func (in *HeaderValueSource) DeepCopy() *HeaderValueSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalModelConfig) DeepCopyInto(out *LocalModelConfig) {
+	*out = *in
+	in.BaseURL.DeepCopyInto(&out.BaseURL)
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalModelConfig.
+func (in *LocalModelConfig) DeepCopy() *LocalModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MCPServer) DeepCopyInto(out *MCPServer) {
 	*out = *in
@@ -1097,7 +1286,7 @@ func (in *Model) DeepCopyInto(out *Model) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
@@ -1118,6 +1307,31 @@ func (in *Model) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelBackendStatus) DeepCopyInto(out *ModelBackendStatus) {
+	*out = *in
+	if in.RemainingRequests != nil {
+		in, out := &in.RemainingRequests, &out.RemainingRequests
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RemainingTokens != nil {
+		in, out := &in.RemainingTokens, &out.RemainingTokens
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelBackendStatus.
+func (in *ModelBackendStatus) DeepCopy() *ModelBackendStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelBackendStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
 	*out = *in
@@ -1136,6 +1350,26 @@ func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
 		*out = new(BedrockModelConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Anthropic != nil {
+		in, out := &in.Anthropic, &out.Anthropic
+		*out = new(AnthropicModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Gemini != nil {
+		in, out := &in.Gemini, &out.Gemini
+		*out = new(GeminiModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pool != nil {
+		in, out := &in.Pool, &out.Pool
+		*out = new(PoolModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
@@ -1180,11 +1414,96 @@ func (in *ModelList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPoolBackend) DeepCopyInto(out *ModelPoolBackend) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPoolBackend.
+func (in *ModelPoolBackend) DeepCopy() *ModelPoolBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPoolBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPricing) DeepCopyInto(out *ModelPricing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPricing.
+func (in *ModelPricing) DeepCopy() *ModelPricing {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelRetryPolicy) DeepCopyInto(out *ModelRetryPolicy) {
+	*out = *in
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int)
+		**out = **in
+	}
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelRetryPolicy.
+func (in *ModelRetryPolicy) DeepCopy() *ModelRetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelRetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 	*out = *in
 	in.Model.DeepCopyInto(&out.Model)
 	in.Config.DeepCopyInto(&out.Config)
+	if in.Pricing != nil {
+		in, out := &in.Pricing, &out.Pricing
+		*out = new(ModelPricing)
+		**out = **in
+	}
+	if in.MaxContextTokens != nil {
+		in, out := &in.MaxContextTokens, &out.MaxContextTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(ModelRetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Fallbacks != nil {
+		in, out := &in.Fallbacks, &out.Fallbacks
+		*out = make([]AgentModelRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
@@ -1200,6 +1519,18 @@ func (in *ModelSpec) DeepCopy() *ModelSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
 	*out = *in
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]ModelBackendStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
@@ -1257,34 +1588,99 @@ func (in *Parameter) DeepCopy() *Parameter {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Query) DeepCopyInto(out *Query) {
+func (in *PartialResponse) DeepCopyInto(out *PartialResponse) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Target = in.Target
+	if in.ToolCalls != nil {
+		in, out := &in.ToolCalls, &out.ToolCalls
+		*out = make([]ToolCallProgress, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Query.
-func (in *Query) DeepCopy() *Query {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PartialResponse.
+func (in *PartialResponse) DeepCopy() *PartialResponse {
 	if in == nil {
 		return nil
 	}
-	out := new(Query)
+	out := new(PartialResponse)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Query) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingApproval) DeepCopyInto(out *PendingApproval) {
+	*out = *in
+	out.Target = in.Target
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingApproval.
+func (in *PendingApproval) DeepCopy() *PendingApproval {
+	if in == nil {
+		return nil
 	}
-	return nil
+	out := new(PendingApproval)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryBasedEvaluationConfig) DeepCopyInto(out *QueryBasedEvaluationConfig) {
+func (in *PoolModelConfig) DeepCopyInto(out *PoolModelConfig) {
+	*out = *in
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]ModelPoolBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Cooldown != nil {
+		in, out := &in.Cooldown, &out.Cooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolModelConfig.
+func (in *PoolModelConfig) DeepCopy() *PoolModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Query) DeepCopyInto(out *Query) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Query.
+func (in *Query) DeepCopy() *Query {
+	if in == nil {
+		return nil
+	}
+	out := new(Query)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Query) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryBasedEvaluationConfig) DeepCopyInto(out *QueryBasedEvaluationConfig) {
 	*out = *in
 	if in.QueryRef != nil {
 		in, out := &in.QueryRef, &out.QueryRef
@@ -1335,6 +1731,47 @@ func (in *QueryList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryParameter) DeepCopyInto(out *QueryParameter) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(QueryParameterSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryParameter.
+func (in *QueryParameter) DeepCopy() *QueryParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryParameterSource) DeepCopyInto(out *QueryParameterSource) {
+	*out = *in
+	in.ValueFromSource.DeepCopyInto(&out.ValueFromSource)
+	if in.QueryRef != nil {
+		in, out := &in.QueryRef, &out.QueryRef
+		*out = new(QueryRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryParameterSource.
+func (in *QueryParameterSource) DeepCopy() *QueryParameterSource {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryParameterSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QueryRef) DeepCopyInto(out *QueryRef) {
 	*out = *in
@@ -1350,6 +1787,128 @@ func (in *QueryRef) DeepCopy() *QueryRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuerySchedule) DeepCopyInto(out *QuerySchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySchedule.
+func (in *QuerySchedule) DeepCopy() *QuerySchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(QuerySchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QuerySchedule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryScheduleList) DeepCopyInto(out *QueryScheduleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QuerySchedule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryScheduleList.
+func (in *QueryScheduleList) DeepCopy() *QueryScheduleList {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryScheduleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryScheduleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryScheduleSpec) DeepCopyInto(out *QueryScheduleSpec) {
+	*out = *in
+	if in.StartingDeadlineSeconds != nil {
+		in, out := &in.StartingDeadlineSeconds, &out.StartingDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SuccessfulQueriesHistoryLimit != nil {
+		in, out := &in.SuccessfulQueriesHistoryLimit, &out.SuccessfulQueriesHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedQueriesHistoryLimit != nil {
+		in, out := &in.FailedQueriesHistoryLimit, &out.FailedQueriesHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	in.QueryTemplate.DeepCopyInto(&out.QueryTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryScheduleSpec.
+func (in *QueryScheduleSpec) DeepCopy() *QueryScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryScheduleStatus) DeepCopyInto(out *QueryScheduleStatus) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryScheduleStatus.
+func (in *QueryScheduleStatus) DeepCopy() *QueryScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *QuerySelector) DeepCopyInto(out *QuerySelector) {
 	*out = *in
@@ -1384,7 +1943,7 @@ func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
 	*out = *in
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
-		*out = make([]Parameter, len(*in))
+		*out = make([]QueryParameter, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1424,6 +1983,16 @@ func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Approvals != nil {
+		in, out := &in.Approvals, &out.Approvals
+		*out = make([]ToolApproval, len(*in))
+		copy(*out, *in)
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySpec.
@@ -1442,7 +2011,9 @@ func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
 	if in.Responses != nil {
 		in, out := &in.Responses, &out.Responses
 		*out = make([]Response, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.Evaluations != nil {
 		in, out := &in.Evaluations, &out.Evaluations
@@ -1457,6 +2028,20 @@ func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.PartialResponses != nil {
+		in, out := &in.PartialResponses, &out.PartialResponses
+		*out = make([]PartialResponse, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingApprovals != nil {
+		in, out := &in.PendingApprovals, &out.PendingApprovals
+		*out = make([]PendingApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatus.
@@ -1484,6 +2069,36 @@ func (in *QueryTarget) DeepCopy() *QueryTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTemplate) DeepCopyInto(out *QueryTemplate) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTemplate.
+func (in *QueryTemplate) DeepCopy() *QueryTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 	*out = *in
@@ -1514,6 +2129,29 @@ func (in *ResourceSelector) DeepCopy() *ResourceSelector {
 func (in *Response) DeepCopyInto(out *Response) {
 	*out = *in
 	out.Target = in.Target
+	out.TokenUsage = in.TokenUsage
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TranscriptRef != nil {
+		in, out := &in.TranscriptRef, &out.TranscriptRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scratchpad != nil {
+		in, out := &in.Scratchpad, &out.Scratchpad
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Models != nil {
+		in, out := &in.Models, &out.Models
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Response.
@@ -1571,6 +2209,11 @@ func (in *Team) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamGraphEdge) DeepCopyInto(out *TeamGraphEdge) {
 	*out = *in
+	if in.Condition != nil {
+		in, out := &in.Condition, &out.Condition
+		*out = new(TeamGraphEdgeCondition)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamGraphEdge.
@@ -1583,13 +2226,30 @@ func (in *TeamGraphEdge) DeepCopy() *TeamGraphEdge {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamGraphEdgeCondition) DeepCopyInto(out *TeamGraphEdgeCondition) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamGraphEdgeCondition.
+func (in *TeamGraphEdgeCondition) DeepCopy() *TeamGraphEdgeCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamGraphEdgeCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamGraphSpec) DeepCopyInto(out *TeamGraphSpec) {
 	*out = *in
 	if in.Edges != nil {
 		in, out := &in.Edges, &out.Edges
 		*out = make([]TeamGraphEdge, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
@@ -1651,43 +2311,93 @@ func (in *TeamMember) DeepCopy() *TeamMember {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TeamSelectorSpec) DeepCopyInto(out *TeamSelectorSpec) {
+func (in *TeamParallelSpec) DeepCopyInto(out *TeamParallelSpec) {
 	*out = *in
+	if in.Aggregator != nil {
+		in, out := &in.Aggregator, &out.Aggregator
+		*out = new(TeamMember)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSelectorSpec.
-func (in *TeamSelectorSpec) DeepCopy() *TeamSelectorSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamParallelSpec.
+func (in *TeamParallelSpec) DeepCopy() *TeamParallelSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(TeamSelectorSpec)
+	out := new(TeamParallelSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
+func (in *TeamPlannerSpec) DeepCopyInto(out *TeamPlannerSpec) {
 	*out = *in
-	if in.Members != nil {
-		in, out := &in.Members, &out.Members
-		*out = make([]TeamMember, len(*in))
-		copy(*out, *in)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamPlannerSpec.
+func (in *TeamPlannerSpec) DeepCopy() *TeamPlannerSpec {
+	if in == nil {
+		return nil
 	}
-	if in.MaxTurns != nil {
-		in, out := &in.MaxTurns, &out.MaxTurns
+	out := new(TeamPlannerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamSelectorSpec) DeepCopyInto(out *TeamSelectorSpec) {
+	*out = *in
+	if in.MaxRetries != nil {
+		in, out := &in.MaxRetries, &out.MaxRetries
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSelectorSpec.
+func (in *TeamSelectorSpec) DeepCopy() *TeamSelectorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamSelectorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]TeamMember, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxTurns != nil {
+		in, out := &in.MaxTurns, &out.MaxTurns
 		*out = new(int)
 		**out = **in
 	}
 	if in.Selector != nil {
 		in, out := &in.Selector, &out.Selector
 		*out = new(TeamSelectorSpec)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Graph != nil {
 		in, out := &in.Graph, &out.Graph
 		*out = new(TeamGraphSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Parallel != nil {
+		in, out := &in.Parallel, &out.Parallel
+		*out = new(TeamParallelSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Planner != nil {
+		in, out := &in.Planner, &out.Planner
+		*out = new(TeamPlannerSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -1715,6 +2425,149 @@ func (in *TeamStatus) DeepCopy() *TeamStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudget) DeepCopyInto(out *TokenBudget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudget.
+func (in *TokenBudget) DeepCopy() *TokenBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TokenBudget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudgetConsumer) DeepCopyInto(out *TokenBudgetConsumer) {
+	*out = *in
+	in.Started.DeepCopyInto(&out.Started)
+	out.TokenUsage = in.TokenUsage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudgetConsumer.
+func (in *TokenBudgetConsumer) DeepCopy() *TokenBudgetConsumer {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudgetConsumer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudgetList) DeepCopyInto(out *TokenBudgetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TokenBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudgetList.
+func (in *TokenBudgetList) DeepCopy() *TokenBudgetList {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudgetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TokenBudgetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudgetScope) DeepCopyInto(out *TokenBudgetScope) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudgetScope.
+func (in *TokenBudgetScope) DeepCopy() *TokenBudgetScope {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudgetScope)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudgetSpec) DeepCopyInto(out *TokenBudgetSpec) {
+	*out = *in
+	out.Scope = in.Scope
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudgetSpec.
+func (in *TokenBudgetSpec) DeepCopy() *TokenBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenBudgetStatus) DeepCopyInto(out *TokenBudgetStatus) {
+	*out = *in
+	if in.Consumers != nil {
+		in, out := &in.Consumers, &out.Consumers
+		*out = make([]TokenBudgetConsumer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastUpdated != nil {
+		in, out := &in.LastUpdated, &out.LastUpdated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenBudgetStatus.
+func (in *TokenBudgetStatus) DeepCopy() *TokenBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TokenUsage) DeepCopyInto(out *TokenUsage) {
 	*out = *in
@@ -1767,6 +2620,52 @@ func (in *ToolAnnotations) DeepCopy() *ToolAnnotations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApproval) DeepCopyInto(out *ToolApproval) {
+	*out = *in
+	out.Target = in.Target
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApproval.
+func (in *ToolApproval) DeepCopy() *ToolApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolCallProgress) DeepCopyInto(out *ToolCallProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolCallProgress.
+func (in *ToolCallProgress) DeepCopy() *ToolCallProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolCallProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolExecution) DeepCopyInto(out *ToolExecution) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolExecution.
+func (in *ToolExecution) DeepCopy() *ToolExecution {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolExecution)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolFunction) DeepCopyInto(out *ToolFunction) {
 	*out = *in
@@ -1888,3 +2787,179 @@ func (in *ValueSource) DeepCopy() *ValueSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Workflow) DeepCopyInto(out *Workflow) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Workflow.
+func (in *Workflow) DeepCopy() *Workflow {
+	if in == nil {
+		return nil
+	}
+	out := new(Workflow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Workflow) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowList) DeepCopyInto(out *WorkflowList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Workflow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowList.
+func (in *WorkflowList) DeepCopy() *WorkflowList {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *WorkflowList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowSpec) DeepCopyInto(out *WorkflowSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]WorkflowStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowSpec.
+func (in *WorkflowSpec) DeepCopy() *WorkflowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStatus) DeepCopyInto(out *WorkflowStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]WorkflowStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.TokenUsage = in.TokenUsage
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStatus.
+func (in *WorkflowStatus) DeepCopy() *WorkflowStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStep) DeepCopyInto(out *WorkflowStep) {
+	*out = *in
+	out.Target = in.Target
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStep.
+func (in *WorkflowStep) DeepCopy() *WorkflowStep {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkflowStepStatus) DeepCopyInto(out *WorkflowStepStatus) {
+	*out = *in
+	out.TokenUsage = in.TokenUsage
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkflowStepStatus.
+func (in *WorkflowStepStatus) DeepCopy() *WorkflowStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkflowStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -236,6 +236,9 @@ func setupControllers(mgr ctrl.Manager) {
 		{"ExecutionEngine", &controller.ExecutionEngineReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("executionengine-controller")}},
 		{"Evaluator", &controller.EvaluatorReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}},
 		{"Evaluation", &controller.EvaluationReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("evaluation-controller")}},
+		{"TokenBudget", &controller.TokenBudgetReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}},
+		{"QuerySchedule", &controller.QueryScheduleReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("queryschedule-controller")}},
+		{"Workflow", &controller.WorkflowReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("workflow-controller")}},
 	}
 
 	for _, reconciler := range controllers {
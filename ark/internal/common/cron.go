@@ -0,0 +1,202 @@
+/* Copyright 2025. McKinsey & Company */
+
+package common
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule is a parsed cron expression in the standard five-field format: minute, hour, day of month, month
+// and day of week. Each field is a bit set of the values it matches.
+type CronSchedule struct {
+	minute, hour, dayOfMonth, month, dayOfWeek uint64
+	// A day matches when both day fields match if either is unrestricted, and when either matches otherwise
+	anyDayOfMonth, anyDayOfWeek bool
+}
+
+type cronField struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronMinute     = cronField{name: "minute", min: 0, max: 59}
+	cronHour       = cronField{name: "hour", min: 0, max: 23}
+	cronDayOfMonth = cronField{name: "day of month", min: 1, max: 31}
+	cronMonth      = cronField{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Both 0 and 7 are Sunday
+	cronDayOfWeek = cronField{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	cronMacros = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// ParseCronSchedule parses a five-field cron expression, or one of the macros such as @daily or @hourly.
+// Fields accept *, single values, ranges, steps and comma separated lists; months and weekdays also accept
+// their three letter names.
+func ParseCronSchedule(expression string) (*CronSchedule, error) {
+	expression = strings.TrimSpace(expression)
+	if macro, ok := cronMacros[strings.ToLower(expression)]; ok {
+		expression = macro
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, found %d", expression, len(fields))
+	}
+
+	schedule := &CronSchedule{}
+	var err error
+	if schedule.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if schedule.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfMonth, err = cronDayOfMonth.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if schedule.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek, err = cronDayOfWeek.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if schedule.dayOfWeek&(1<<7) != 0 {
+		schedule.dayOfWeek |= 1
+	}
+	schedule.anyDayOfMonth = isCronWildcard(fields[2])
+	schedule.anyDayOfWeek = isCronWildcard(fields[4])
+	return schedule, nil
+}
+
+// Next returns the first time after t that matches the schedule, in t's location. It returns the zero time if
+// nothing matches within five years, which only happens for dates such as February 30th.
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	yearLimit := t.Year() + 5
+
+	for t.Year() <= yearLimit {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *CronSchedule) matchesDay(t time.Time) bool {
+	dayOfMonth := s.dayOfMonth&(1<<uint(t.Day())) != 0
+	dayOfWeek := s.dayOfWeek&(1<<uint(t.Weekday())) != 0
+	if s.anyDayOfMonth || s.anyDayOfWeek {
+		return dayOfMonth && dayOfWeek
+	}
+	return dayOfMonth || dayOfWeek
+}
+
+func isCronWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parse returns the bit set of the values matched by a field, such as "*/15", "1-5" or "mon,wed,fri"
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		partBits, err := f.parsePart(part)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q: %w", f.name, field, err)
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+func (f cronField) parsePart(part string) (uint64, error) {
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+	step := 1
+	if hasStep {
+		var err error
+		if step, err = strconv.Atoi(stepPart); err != nil || step <= 0 {
+			return 0, fmt.Errorf("step %q must be a positive number", stepPart)
+		}
+	}
+
+	var start, end int
+	switch {
+	case isCronWildcard(rangePart):
+		start, end = f.min, f.max
+	case strings.Contains(rangePart, "-"):
+		low, high, _ := strings.Cut(rangePart, "-")
+		var err error
+		if start, err = f.value(low); err != nil {
+			return 0, err
+		}
+		if end, err = f.value(high); err != nil {
+			return 0, err
+		}
+		if start > end {
+			return 0, fmt.Errorf("range %q is backwards", rangePart)
+		}
+	default:
+		var err error
+		if start, err = f.value(rangePart); err != nil {
+			return 0, err
+		}
+		// A single value with a step, such as 5/15, runs from the value to the end of the range
+		end = start
+		if hasStep {
+			end = f.max
+		}
+	}
+
+	var bits uint64
+	for value := start; value <= end; value += step {
+		bits |= 1 << uint(value)
+	}
+	return bits, nil
+}
+
+func (f cronField) value(text string) (int, error) {
+	if value, ok := f.names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", text)
+	}
+	if value < f.min || value > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", value, f.min, f.max)
+	}
+	return value, nil
+}
@@ -0,0 +1,71 @@
+/* Copyright 2025. McKinsey & Company */
+
+package common
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	from := time.Date(2025, time.July, 4, 10, 30, 15, 0, time.UTC) // a Friday
+
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.July, 4, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.July, 4, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, time.July, 5, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, time.July, 5, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, time.July, 4, 11, 0, 0, 0, time.UTC)},
+		{"30 9 * * mon-fri", time.Date(2025, time.July, 7, 9, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2025, time.July, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 13 * 5", time.Date(2025, time.July, 4, 12, 0, 0, 0, time.UTC)}, // day of month or day of week
+		{"5,35 10 * * *", time.Date(2025, time.July, 4, 10, 35, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2025, time.July, 31, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			schedule, err := ParseCronSchedule(tt.expression)
+			if err != nil {
+				t.Fatalf("ParseCronSchedule(%q) failed: %v", tt.expression, err)
+			}
+			if got := schedule.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCronScheduleNextInLocation(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	schedule, err := ParseCronSchedule("0 2 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := schedule.Next(time.Date(2025, time.July, 4, 10, 0, 0, 0, time.UTC).In(loc))
+	want := time.Date(2025, time.July, 5, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("Next() = %v, want %v", got, want)
+	}
+}
+
+func TestParseCronScheduleErrors(t *testing.T) {
+	for _, expression := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		if _, err := ParseCronSchedule(expression); err == nil {
+			t.Errorf("ParseCronSchedule(%q) succeeded, want an error", expression)
+		}
+	}
+
+	schedule, err := ParseCronSchedule("0 0 30 2 *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next := schedule.Next(time.Now()); !next.IsZero() {
+		t.Errorf("Next() = %v for February 30th, want the zero time", next)
+	}
+}
@@ -12,6 +12,15 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
 
+// responseObserverKey is the context key of the function that is shown the responses of a request's round trip
+type responseObserverKey struct{}
+
+// WithResponseObserver returns a context whose HTTP requests show their responses to observe, for callers that
+// need response headers a client library does not return, such as rate limit headers. observe must not read the body.
+func WithResponseObserver(ctx context.Context, observe func(*http.Response)) context.Context {
+	return context.WithValue(ctx, responseObserverKey{}, observe)
+}
+
 // LoggingTransport wraps an http.RoundTripper to provide optional HTTP request/response logging
 type LoggingTransport struct {
 	Transport http.RoundTripper
@@ -33,7 +42,11 @@ func NewLoggingTransport(ctx context.Context, transport http.RoundTripper) *Logg
 // Logging is enabled when ENABLE_HTTP_LOGGING environment variable is set to "true"
 func (lt *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 	if os.Getenv("ENABLE_HTTP_LOGGING") != "true" {
-		return lt.Transport.RoundTrip(req)
+		resp, err := lt.Transport.RoundTrip(req)
+		if err == nil {
+			observeResponse(req, resp)
+		}
+		return resp, err
 	}
 
 	logger := logf.FromContext(lt.Context)
@@ -59,10 +72,17 @@ func (lt *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 	}
 
 	logger.Info("HTTP Response", "status", resp.Status, "body", string(responseBody))
+	observeResponse(req, resp)
 
 	return resp, nil
 }
 
+func observeResponse(req *http.Request, resp *http.Response) {
+	if observe, ok := req.Context().Value(responseObserverKey{}).(func(*http.Response)); ok {
+		observe(resp)
+	}
+}
+
 // NewHTTPClientWithLogging creates an HTTP client with logging transport
 func NewHTTPClientWithLogging(ctx context.Context) *http.Client {
 	return &http.Client{
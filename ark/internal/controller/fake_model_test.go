@@ -0,0 +1,153 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// fakeModelReply is what the fake model server answers to one chat completion request. Requests are decoded as
+// the OpenAI API receives them.
+type fakeModelReply func(w http.ResponseWriter, r *http.Request, request fakeModelRequest)
+
+type fakeModelRequest struct {
+	Model    string `json:"model"`
+	Messages []struct {
+		Role    string `json:"role"`
+		Content any    `json:"content"`
+	} `json:"messages"`
+}
+
+// fakeModelServer serves the OpenAI chat completions API, answering each model name with its own reply
+type fakeModelServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	replies  map[string]fakeModelReply
+	requests map[string][]fakeModelRequest
+}
+
+func newFakeModelServer(replies map[string]fakeModelReply) *fakeModelServer {
+	server := &fakeModelServer{replies: replies, requests: map[string][]fakeModelRequest{}}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request fakeModelRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		server.mu.Lock()
+		server.requests[request.Model] = append(server.requests[request.Model], request)
+		reply, ok := server.replies[request.Model]
+		server.mu.Unlock()
+		if !ok {
+			http.Error(w, `{"error": {"message": "unknown model"}}`, http.StatusNotFound)
+			return
+		}
+		reply(w, r, request)
+	}))
+	return server
+}
+
+// calls returns how many requests were made for model
+func (s *fakeModelServer) calls(model string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests[model])
+}
+
+// lastInput returns the content of the last message sent to model
+func (s *fakeModelServer) lastInput(model string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := s.requests[model]
+	if len(requests) == 0 {
+		return ""
+	}
+	messages := requests[len(requests)-1].Messages
+	content, _ := json.Marshal(messages[len(messages)-1].Content)
+	return string(content)
+}
+
+// model returns a Model of the given name that calls the server
+func (s *fakeModelServer) model(name, namespace string) *arkv1alpha1.Model {
+	return &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:  genai.ModelTypeOpenAI,
+			Model: arkv1alpha1.ValueSource{Value: name},
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: s.URL + "/v1"},
+				APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}
+}
+
+// replyContent answers with an assistant message
+func replyContent(content string) fakeModelReply {
+	return func(w http.ResponseWriter, r *http.Request, request fakeModelRequest) {
+		writeCompletion(w, map[string]any{"role": "assistant", "content": content})
+	}
+}
+
+// replyError answers with a client error, which models do not retry
+func replyError(message string) fakeModelReply {
+	return func(w http.ResponseWriter, r *http.Request, request fakeModelRequest) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"error": map[string]any{"message": message}})
+	}
+}
+
+// replyHang answers once the request is cancelled
+func replyHang() fakeModelReply {
+	return func(w http.ResponseWriter, r *http.Request, request fakeModelRequest) {
+		<-r.Context().Done()
+	}
+}
+
+// replySequence answers with each reply in turn, repeating the last one
+func replySequence(replies ...fakeModelReply) fakeModelReply {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request, request fakeModelRequest) {
+		mu.Lock()
+		reply := replies[0]
+		if len(replies) > 1 {
+			replies = replies[1:]
+		}
+		mu.Unlock()
+		reply(w, r, request)
+	}
+}
+
+func writeCompletion(w http.ResponseWriter, message map[string]any) {
+	finishReason := "stop"
+	if _, ok := message["tool_calls"]; ok {
+		finishReason = "tool_calls"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"id":      "chatcmpl-test",
+		"object":  "chat.completion",
+		"model":   "test",
+		"choices": []map[string]any{{"index": 0, "finish_reason": finishReason, "message": message}},
+		"usage":   map[string]any{"prompt_tokens": 10, "completion_tokens": 5, "total_tokens": 15},
+	})
+}
+
+// fakeClientScheme returns the scheme controller tests build fake clients with
+func fakeClientScheme() *runtime.Scheme {
+	testScheme := runtime.NewScheme()
+	_ = scheme.AddToScheme(testScheme)
+	_ = arkv1alpha1.AddToScheme(testScheme)
+	return testScheme
+}
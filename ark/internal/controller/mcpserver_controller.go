@@ -286,6 +286,7 @@ func (r *MCPServerReconciler) buildToolCRD(mcpServer *arkv1alpha1.MCPServer, mcp
 			Type:        "mcp",
 			Description: mcpTool.Description,
 			InputSchema: r.convertInputSchemaToRawExtension(mcpTool.InputSchema),
+			Annotations: r.convertToolAnnotations(mcpTool.Annotations),
 			MCP: &arkv1alpha1.MCPToolRef{
 				MCPServerRef: arkv1alpha1.MCPServerRef{
 					Name:      mcpServer.Name,
@@ -347,6 +348,24 @@ func (r *MCPServerReconciler) convertInputSchemaToRawExtension(schema mcp.ToolIn
 	return &runtime.RawExtension{Raw: bytes}
 }
 
+// convertToolAnnotations copies the hints advertised by the MCP server. Only hints the server sets
+// explicitly are carried over, so an unannotated tool is not treated as destructive.
+func (r *MCPServerReconciler) convertToolAnnotations(annotations mcp.ToolAnnotation) *arkv1alpha1.ToolAnnotations {
+	isSet := func(hint *bool) bool { return hint != nil && *hint }
+
+	converted := &arkv1alpha1.ToolAnnotations{
+		Title:           annotations.Title,
+		ReadOnlyHint:    isSet(annotations.ReadOnlyHint),
+		DestructiveHint: isSet(annotations.DestructiveHint),
+		IdempotentHint:  isSet(annotations.IdempotentHint),
+		OpenWorldHint:   isSet(annotations.OpenWorldHint),
+	}
+	if *converted == (arkv1alpha1.ToolAnnotations{}) {
+		return nil
+	}
+	return converted
+}
+
 func (r *MCPServerReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&arkv1alpha1.MCPServer{}).
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -18,6 +19,9 @@ import (
 	"mckinsey.com/ark/internal/genai"
 )
 
+// defaultPoolStatusInterval is how often the backend health of a ready pool is copied into its status
+const defaultPoolStatusInterval = 30 * time.Second
+
 type ModelReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
@@ -50,7 +54,12 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	}
 
 	switch obj.Status.Phase {
-	case statusReady, statusError:
+	case statusReady:
+		if obj.Spec.Type == genai.ModelTypePool {
+			return r.refreshPoolStatus(ctx, obj)
+		}
+		return ctrl.Result{}, nil
+	case statusError:
 		return ctrl.Result{}, nil
 	case statusRunning:
 		recorder := genai.NewModelRecorder(&obj, r.Recorder)
@@ -61,7 +70,7 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 			"modelName": obj.Spec.Model.Value,
 		})
 
-		if err := r.reconcileModel(ctx, obj); err != nil {
+		if err := r.reconcileModel(ctx, &obj); err != nil {
 			log.Info("model error", "error", err.Error())
 			modelTracker.Fail(err)
 			if err := r.updateStatus(ctx, obj, statusError); err != nil {
@@ -82,12 +91,18 @@ func (r *ModelReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	return ctrl.Result{}, nil
 }
 
-func (r *ModelReconciler) reconcileModel(ctx context.Context, obj arkv1alpha1.Model) error {
-	resolvedModel, err := r.resolveModel(ctx, &obj)
+func (r *ModelReconciler) reconcileModel(ctx context.Context, obj *arkv1alpha1.Model) error {
+	resolvedModel, err := r.resolveModel(ctx, obj)
 	if err != nil {
 		return err
 	}
 
+	if localProvider, ok := resolvedModel.Provider.(*genai.LocalProvider); ok {
+		// Local models are not called, as the first call loads the model and can take minutes on CPUs, holding up
+		// the reconciles of every other model
+		return r.checkLocalModel(ctx, obj, localProvider)
+	}
+
 	validationCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
@@ -163,6 +178,24 @@ func (r *ModelReconciler) resolveModel(ctx context.Context, model *arkv1alpha1.M
 		// Bedrock doesn't use baseURL or apiKey - handled by LoadModel function
 		// Just use LoadModel directly for Bedrock
 		return genai.LoadModel(ctx, r.Client, model.Name, model.Namespace)
+	case "anthropic":
+		// Anthropic resolves its optional baseURL and apiVersion in LoadModel
+		return genai.LoadModel(ctx, r.Client, model.Name, model.Namespace)
+	case "local":
+		localModel, err := genai.LoadModel(ctx, r.Client, model.Name, model.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if localProvider, ok := localModel.Provider.(*genai.LocalProvider); ok {
+			model.Status.ResolvedAddress = localProvider.BaseURL
+		}
+		return localModel, nil
+	case "gemini":
+		// The Gemini endpoint depends on whether Vertex AI is used, which LoadModel works out
+		return genai.LoadModel(ctx, r.Client, model.Name, model.Namespace)
+	case "pool":
+		// The backends of a pool are models of their own, which LoadModel loads
+		return genai.LoadModel(ctx, r.Client, model.Name, model.Namespace)
 	default:
 		return nil, fmt.Errorf("unsupported model type: %s", model.Spec.Type)
 	}
@@ -170,6 +203,50 @@ func (r *ModelReconciler) resolveModel(ctx context.Context, model *arkv1alpha1.M
 	return resolvedModel, nil
 }
 
+// checkLocalModel records whether the model is available on its local server, and fails when it is not
+func (r *ModelReconciler) checkLocalModel(ctx context.Context, model *arkv1alpha1.Model, provider *genai.LocalProvider) error {
+	checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	available, err := provider.CheckAvailability(checkCtx)
+	if err != nil {
+		model.Status.Message = fmt.Sprintf("failed to check model availability: %v", err)
+		return fmt.Errorf("failed to check availability of model %s: %w", provider.Model, err)
+	}
+
+	model.Status.Available = &available
+	if !available {
+		server := provider.Server
+		if server == "" {
+			server = genai.LocalServerOllama
+		}
+		model.Status.Message = fmt.Sprintf("model %s is not available on the %s server", provider.Model, server)
+		return fmt.Errorf("model %s is not available on the %s server at %s", provider.Model, server, provider.BaseURL)
+	}
+	model.Status.Message = ""
+	return nil
+}
+
+// refreshPoolStatus copies the health of the backends of a ready pool into its status, and checks it again later as
+// it changes with the calls made through the pool
+func (r *ModelReconciler) refreshPoolStatus(ctx context.Context, model arkv1alpha1.Model) (ctrl.Result, error) {
+	var backends []string
+	if model.Spec.Config.Pool != nil {
+		for _, backend := range model.Spec.Config.Pool.Backends {
+			backends = append(backends, backend.Name)
+		}
+	}
+
+	statuses := genai.ModelPoolStatus(model.Namespace, model.Name, backends)
+	if !equality.Semantic.DeepEqual(statuses, model.Status.Backends) {
+		model.Status.Backends = statuses
+		if err := r.updateStatus(ctx, model, statusReady); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	return ctrl.Result{RequeueAfter: defaultPoolStatusInterval}, nil
+}
+
 func (r *ModelReconciler) updateStatus(ctx context.Context, obj arkv1alpha1.Model, status string) error {
 	if ctx.Err() != nil {
 		return nil
@@ -4,16 +4,22 @@ package controller
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
 )
 
 var _ = Describe("Model Controller", func() {
@@ -83,3 +89,49 @@ var _ = Describe("Model Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Model Controller local models", func() {
+	It("readies a local model from its availability without calling it", func() {
+		ctx := context.Background()
+		var chatCalls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/api/tags" {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"models": [{"name": "llama3.1:latest", "model": "llama3.1:latest"}]}`))
+				return
+			}
+			// Loading the model would hold up the reconcile
+			chatCalls.Add(1)
+			<-r.Context().Done()
+		}))
+		DeferCleanup(server.Close)
+
+		model := &arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "ollama", Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Type:  genai.ModelTypeLocal,
+				Model: arkv1alpha1.ValueSource{Value: "llama3.1"},
+				Config: arkv1alpha1.ModelConfig{Local: &arkv1alpha1.LocalModelConfig{
+					BaseURL: arkv1alpha1.ValueSource{Value: server.URL},
+				}},
+			},
+			Status: arkv1alpha1.ModelStatus{Phase: statusRunning},
+		}
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Model{}).
+			WithObjects(model).
+			Build()
+		reconciler := &ModelReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: record.NewFakeRecorder(100)}
+
+		key := types.NamespacedName{Name: "ollama", Namespace: "default"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var result arkv1alpha1.Model
+		Expect(fakeClient.Get(ctx, key, &result)).To(Succeed())
+		Expect(result.Status.Phase).To(Equal(statusReady))
+		Expect(result.Status.Available).To(HaveValue(BeTrue()))
+		Expect(chatCalls.Load()).To(BeZero())
+	})
+})
@@ -0,0 +1,273 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const approvalDecisionApprove = "approve"
+
+// queryApprovalGate parks tool calls that need approval. Pending calls are listed in status.pendingApprovals
+// with the query in the awaiting-approval phase, and are released as decisions for them show up in spec.approvals.
+type queryApprovalGate struct {
+	client  client.Client
+	query   types.NamespacedName
+	mu      sync.Mutex
+	pending []arkv1alpha1.PendingApproval
+	waiters map[approvalKey]chan arkv1alpha1.ToolApproval
+	// decided holds the entries of spec.approvals as last seen. Decisions only apply to calls pending when they
+	// are added, so a tool call ID that is reused later does not pick up an old decision.
+	decided map[arkv1alpha1.ToolApproval]bool
+	patched bool
+}
+
+// approvalKey identifies a tool call. Tool call IDs are only unique within the conversation of an agent, so
+// the target and agent making the call are part of the key.
+type approvalKey struct {
+	target     arkv1alpha1.QueryTarget
+	agent      string
+	toolCallID string
+}
+
+func newQueryApprovalGate(k8sClient client.Client, query arkv1alpha1.Query) *queryApprovalGate {
+	return &queryApprovalGate{
+		client:  k8sClient,
+		query:   types.NamespacedName{Name: query.Name, Namespace: query.Namespace},
+		waiters: map[approvalKey]chan arkv1alpha1.ToolApproval{},
+		// Decisions made before this execution were for calls that are no longer pending
+		decided: approvalSet(query.Spec.Approvals),
+	}
+}
+
+func approvalSet(approvals []arkv1alpha1.ToolApproval) map[arkv1alpha1.ToolApproval]bool {
+	set := make(map[arkv1alpha1.ToolApproval]bool, len(approvals))
+	for _, approval := range approvals {
+		set[approval] = true
+	}
+	return set
+}
+
+// forTarget returns the approver a target's execution should use
+func (g *queryApprovalGate) forTarget(target arkv1alpha1.QueryTarget) genai.ToolApprover {
+	return &targetToolApprover{gate: g, target: target}
+}
+
+func (g *queryApprovalGate) request(ctx context.Context, target arkv1alpha1.QueryTarget, request genai.ToolApprovalRequest) (genai.ToolApprovalDecision, error) {
+	key := approvalKey{target: target, agent: request.Agent, toolCallID: request.ToolCallID}
+
+	g.mu.Lock()
+	if _, waiting := g.waiters[key]; waiting {
+		g.mu.Unlock()
+		return genai.ToolApprovalDecision{}, fmt.Errorf("tool call %s is already awaiting approval", request.ToolCallID)
+	}
+	decision := make(chan arkv1alpha1.ToolApproval, 1)
+	g.waiters[key] = decision
+	g.pending = append(g.pending, arkv1alpha1.PendingApproval{
+		ToolCallID:  request.ToolCallID,
+		Target:      target,
+		Agent:       request.Agent,
+		Tool:        request.Tool,
+		Arguments:   request.Arguments,
+		RequestedAt: metav1.Now(),
+	})
+	g.publish(ctx)
+	g.mu.Unlock()
+
+	// Waiting for a person does not count towards the timeout of the query
+	if timeout := targetTimeoutFromContext(ctx); timeout != nil {
+		timeout.pause()
+		defer timeout.resume()
+	}
+
+	select {
+	case approval := <-decision:
+		return toApprovalDecision(approval), nil
+	case <-ctx.Done():
+		// The query is over, so the status is left to the update that ends it
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		delete(g.waiters, key)
+		g.removePending(key)
+		return genai.ToolApprovalDecision{}, ctx.Err()
+	}
+}
+
+// resolve hands the decisions added to spec.approvals since the last call to the tool calls waiting for them.
+// Decisions for calls that are not pending are ignored.
+func (g *queryApprovalGate) resolve(ctx context.Context, approvals []arkv1alpha1.ToolApproval) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	released := false
+	for _, approval := range approvals {
+		if g.decided[approval] {
+			continue
+		}
+		key := approvalKey{target: approval.Target, agent: approval.Agent, toolCallID: approval.ToolCallID}
+		if waiter, ok := g.waiters[key]; ok {
+			waiter <- approval
+			delete(g.waiters, key)
+			g.removePending(key)
+			released = true
+		}
+	}
+	g.decided = approvalSet(approvals)
+
+	if released {
+		g.publish(ctx)
+	}
+}
+
+// finish refreshes the resource version once approvals have touched the query, since both the status
+// patches and the user's spec updates would otherwise make the final status update conflict
+func (g *queryApprovalGate) finish(ctx context.Context, query *arkv1alpha1.Query) {
+	g.mu.Lock()
+	patched := g.patched
+	g.mu.Unlock()
+	if !patched {
+		return
+	}
+
+	var latest arkv1alpha1.Query
+	if err := g.client.Get(ctx, g.query, &latest); err != nil {
+		logf.FromContext(ctx).V(1).Info("failed to refresh query after approvals", "error", err.Error())
+		return
+	}
+	query.ResourceVersion = latest.ResourceVersion
+}
+
+func (g *queryApprovalGate) removePending(key approvalKey) {
+	g.pending = slices.DeleteFunc(g.pending, func(p arkv1alpha1.PendingApproval) bool {
+		return p.Target == key.target && p.Agent == key.agent && p.ToolCallID == key.toolCallID
+	})
+}
+
+// publish writes the pending approvals and the matching phase. Callers must hold g.mu.
+func (g *queryApprovalGate) publish(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	phase := statusRunning
+	var pending []arkv1alpha1.PendingApproval
+	if len(g.pending) > 0 {
+		phase = statusAwaitingApproval
+		pending = g.pending
+	}
+
+	log := logf.FromContext(ctx)
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"phase":            phase,
+			"pendingApprovals": pending,
+		},
+	})
+	if err != nil {
+		log.Error(err, "failed to marshal pending approvals")
+		return
+	}
+
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: g.query.Name, Namespace: g.query.Namespace}}
+	if err := g.client.Status().Patch(ctx, query, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		log.Error(err, "failed to publish pending approvals")
+		return
+	}
+	g.patched = true
+}
+
+func toApprovalDecision(approval arkv1alpha1.ToolApproval) genai.ToolApprovalDecision {
+	return genai.ToolApprovalDecision{
+		Approved: approval.Decision == approvalDecisionApprove,
+		Reason:   approval.Reason,
+	}
+}
+
+type targetToolApprover struct {
+	gate   *queryApprovalGate
+	target arkv1alpha1.QueryTarget
+}
+
+func (a *targetToolApprover) RequestApproval(ctx context.Context, request genai.ToolApprovalRequest) (genai.ToolApprovalDecision, error) {
+	return a.gate.request(ctx, a.target, request)
+}
+
+type targetTimeoutKey struct{}
+
+// targetTimeout is the timeout of a target's execution. It is paused while tool calls wait for approval, and
+// cancels the execution with errTargetTimeout once the time left runs out.
+type targetTimeout struct {
+	mu        sync.Mutex
+	cancel    context.CancelCauseFunc
+	remaining time.Duration
+	started   time.Time
+	timer     *time.Timer
+	paused    int
+	stopped   bool
+}
+
+var errTargetTimeout = errors.New("query timed out")
+
+// withTargetTimeout returns a context that is cancelled once timeout has passed outside of approval waits
+func withTargetTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	t := &targetTimeout{cancel: cancel, remaining: timeout}
+	t.start()
+	return context.WithValue(ctx, targetTimeoutKey{}, t), func() {
+		t.mu.Lock()
+		t.stopped = true
+		t.timer.Stop()
+		t.mu.Unlock()
+		cancel(context.Canceled)
+	}
+}
+
+func targetTimeoutFromContext(ctx context.Context) *targetTimeout {
+	t, _ := ctx.Value(targetTimeoutKey{}).(*targetTimeout)
+	return t
+}
+
+// start runs the timer for the time left. Callers must hold t.mu or own t exclusively.
+func (t *targetTimeout) start() {
+	t.started = time.Now()
+	t.timer = time.AfterFunc(t.remaining, func() {
+		t.cancel(errTargetTimeout)
+	})
+}
+
+// pause stops the timer while at least one tool call waits for approval
+func (t *targetTimeout) pause() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused++
+	if t.paused > 1 {
+		return
+	}
+	if t.timer.Stop() {
+		t.remaining -= time.Since(t.started)
+	} else {
+		t.remaining = 0
+	}
+}
+
+func (t *targetTimeout) resume() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.paused--
+	if t.paused == 0 && !t.stopped && t.remaining > 0 {
+		t.start()
+	}
+}
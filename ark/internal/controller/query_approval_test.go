@@ -0,0 +1,154 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var _ = Describe("Query approvals", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		query      *arkv1alpha1.Query
+	)
+
+	weather := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+	deleteForecast := genai.ToolApprovalRequest{ToolCallID: "call-1", Agent: "default/weather", Tool: "delete_forecast", Arguments: `{"city": "Paris"}`}
+
+	// decide returns a decision for the tool call of deleteForecast
+	decide := func(decision, reason string) arkv1alpha1.ToolApproval {
+		return arkv1alpha1.ToolApproval{ToolCallID: "call-1", Target: weather, Agent: "default/weather", Decision: decision, Reason: reason}
+	}
+
+	// requestApproval asks for a decision in the background, as an agent executing a tool call would
+	requestApproval := func(ctx context.Context, gate *queryApprovalGate) (<-chan genai.ToolApprovalDecision, <-chan error) {
+		decisions, errs := make(chan genai.ToolApprovalDecision, 1), make(chan error, 1)
+		go func() {
+			defer GinkgoRecover()
+			decision, err := gate.forTarget(weather).RequestApproval(ctx, deleteForecast)
+			decisions <- decision
+			errs <- err
+		}()
+		return decisions, errs
+	}
+
+	status := func() arkv1alpha1.QueryStatus {
+		var latest arkv1alpha1.Query
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: query.Name, Namespace: query.Namespace}, &latest)).To(Succeed())
+		return latest.Status
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "default"},
+			Spec:       arkv1alpha1.QuerySpec{Input: "clean up the forecasts", Targets: []arkv1alpha1.QueryTarget{weather}},
+			Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(query).
+			Build()
+	})
+
+	It("holds a tool call in the awaiting-approval phase until it is approved", func() {
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, errs := requestApproval(ctx, gate)
+
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+		pending := status().PendingApprovals
+		Expect(pending).To(HaveLen(1))
+		Expect(pending[0].ToolCallID).To(Equal("call-1"))
+		Expect(pending[0].Target).To(Equal(weather))
+		Expect(pending[0].Tool).To(Equal("delete_forecast"))
+		Consistently(decisions, 50*time.Millisecond).ShouldNot(Receive())
+
+		gate.resolve(ctx, []arkv1alpha1.ToolApproval{decide(approvalDecisionApprove, "")})
+
+		Eventually(decisions).Should(Receive(Equal(genai.ToolApprovalDecision{Approved: true})))
+		Expect(<-errs).NotTo(HaveOccurred())
+		Expect(status().Phase).To(Equal(statusRunning))
+		Expect(status().PendingApprovals).To(BeEmpty())
+	})
+
+	It("passes the reason of a denial to the agent", func() {
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, errs := requestApproval(ctx, gate)
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+
+		gate.resolve(ctx, []arkv1alpha1.ToolApproval{decide("deny", "forecasts are kept for a year")})
+
+		Eventually(decisions).Should(Receive(Equal(genai.ToolApprovalDecision{Approved: false, Reason: "forecasts are kept for a year"})))
+		Expect(<-errs).NotTo(HaveOccurred())
+		Expect(status().PendingApprovals).To(BeEmpty())
+	})
+
+	It("ignores decisions made before the tool call was pending", func() {
+		query.Spec.Approvals = []arkv1alpha1.ToolApproval{decide(approvalDecisionApprove, "")}
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, _ := requestApproval(ctx, gate)
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+
+		gate.resolve(ctx, query.Spec.Approvals)
+
+		Consistently(decisions, 50*time.Millisecond).ShouldNot(Receive(), "a reused tool call ID does not pick up an old decision")
+		gate.resolve(ctx, append(query.Spec.Approvals, decide("deny", "")))
+		Eventually(decisions).Should(Receive(Equal(genai.ToolApprovalDecision{Approved: false})))
+	})
+
+	It("only releases the call of the target and agent the decision names", func() {
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, _ := requestApproval(ctx, gate)
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+
+		otherAgent := decide(approvalDecisionApprove, "")
+		otherAgent.Agent = "default/planner"
+		otherTarget := decide(approvalDecisionApprove, "")
+		otherTarget.Target = arkv1alpha1.QueryTarget{Type: "team", Name: "forecasters"}
+		gate.resolve(ctx, []arkv1alpha1.ToolApproval{otherAgent, otherTarget})
+
+		Consistently(decisions, 50*time.Millisecond).ShouldNot(Receive())
+		Expect(status().PendingApprovals).To(HaveLen(1))
+	})
+
+	It("does not count the wait for approval towards the timeout of the target", func() {
+		targetCtx, cancel := withTargetTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, _ := requestApproval(targetCtx, gate)
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+
+		Consistently(targetCtx.Done(), 300*time.Millisecond).ShouldNot(BeClosed())
+		gate.resolve(ctx, []arkv1alpha1.ToolApproval{decide(approvalDecisionApprove, "")})
+		Eventually(decisions).Should(Receive())
+
+		Eventually(targetCtx.Done()).Should(BeClosed(), "the timeout runs again once the call is decided")
+		Expect(context.Cause(targetCtx)).To(MatchError(errTargetTimeout))
+	})
+
+	It("stops waiting without touching the status once the query is over", func() {
+		targetCtx, cancel := context.WithCancel(ctx)
+		gate := newQueryApprovalGate(fakeClient, *query)
+		decisions, errs := requestApproval(targetCtx, gate)
+		Eventually(func() string { return status().Phase }).Should(Equal(statusAwaitingApproval))
+
+		cancel()
+
+		Eventually(errs).Should(Receive(MatchError(context.Canceled)))
+		Expect(<-decisions).To(Equal(genai.ToolApprovalDecision{}))
+		Expect(status().Phase).To(Equal(statusAwaitingApproval), "the update that ends the query sets the phase")
+	})
+})
@@ -0,0 +1,146 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// queryBudgetGuard enforces the TokenBudgets of a query's namespace while it runs. The usage of each model call is
+// recorded in the status of the budgets that cover its target, where the webhook and every controller replica see
+// it, and the query is aborted as soon as a budget reaches its limit.
+type queryBudgetGuard struct {
+	client   client.Client
+	query    arkv1alpha1.Query
+	consumer arkv1alpha1.TokenBudgetConsumer
+	abort    context.CancelCauseFunc
+	mu       sync.Mutex
+	budgets  []*guardedBudget
+}
+
+type guardedBudget struct {
+	budget arkv1alpha1.TokenBudget
+	// unrecorded is the usage of finished queries and workflows the budget has no record of
+	unrecorded genai.TokenUsage
+	// pending is usage that could not be recorded yet, it is added to the next write
+	pending genai.TokenUsage
+}
+
+// budgetConsumer identifies a query or workflow in the status of the budgets it uses
+func budgetConsumer(kind string, obj metav1.Object) arkv1alpha1.TokenBudgetConsumer {
+	return arkv1alpha1.TokenBudgetConsumer{
+		UID:     obj.GetUID(),
+		Kind:    kind,
+		Name:    obj.GetName(),
+		Started: obj.GetCreationTimestamp(),
+	}
+}
+
+func loadQueryBudgetGuard(ctx context.Context, k8sClient client.Client, query arkv1alpha1.Query, consumer arkv1alpha1.TokenBudgetConsumer, abort context.CancelCauseFunc) (*queryBudgetGuard, error) {
+	budgets, err := genai.LoadTokenBudgets(ctx, k8sClient, query.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	guard := &queryBudgetGuard{client: k8sClient, query: query, consumer: consumer, abort: abort}
+	now := time.Now()
+	for i := range budgets {
+		unrecorded, err := genai.UnrecordedTokenBudgetUsage(ctx, k8sClient, &budgets[i], now)
+		if err != nil {
+			return nil, err
+		}
+		guard.budgets = append(guard.budgets, &guardedBudget{budget: budgets[i], unrecorded: unrecorded})
+	}
+	return guard, nil
+}
+
+// check returns an error if a budget that covers target has already been used up. The budgets are read again, as
+// queries running elsewhere may have used them since.
+func (g *queryBudgetGuard) check(ctx context.Context, target arkv1alpha1.QueryTarget) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	for _, guarded := range g.budgets {
+		if !genai.TokenBudgetCoversTarget(&guarded.budget, &g.query, target) {
+			continue
+		}
+		var latest arkv1alpha1.TokenBudget
+		if err := g.client.Get(ctx, client.ObjectKeyFromObject(&guarded.budget), &latest); err == nil {
+			guarded.budget = latest
+		}
+		if err := genai.CheckTokenBudget(&guarded.budget, guarded.usage(now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record adds usage of target to the budgets that cover it and aborts the query when one of them runs out. Usage
+// that cannot be written is kept and written with the next model call.
+func (g *queryBudgetGuard) record(ctx context.Context, target arkv1alpha1.QueryTarget, usage genai.TokenUsage) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// The usage was spent even when the query is being canceled, so it is still recorded
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now()
+	for _, guarded := range g.budgets {
+		if !genai.TokenBudgetCoversTarget(&guarded.budget, &g.query, target) {
+			continue
+		}
+		guarded.pending.Add(usage)
+		key := types.NamespacedName{Name: guarded.budget.Name, Namespace: guarded.budget.Namespace}
+		budget, err := genai.RecordTokenBudgetUsage(ctx, g.client, key, g.consumer, guarded.pending)
+		if errors.IsNotFound(err) {
+			// The budget was deleted, so there is nothing left to enforce
+			guarded.pending = genai.TokenUsage{}
+			continue
+		}
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "failed to record token budget usage", "tokenbudget", key.Name)
+		} else {
+			guarded.budget, guarded.pending = *budget, genai.TokenUsage{}
+		}
+		if err := genai.CheckTokenBudget(&guarded.budget, guarded.usage(now)); err != nil {
+			g.abort(err)
+		}
+	}
+}
+
+func (b *guardedBudget) usage(now time.Time) genai.TokenUsage {
+	usage := b.unrecorded
+	usage.Add(genai.RecordedTokenBudgetUsage(&b.budget, now))
+	usage.Add(b.pending)
+	return usage
+}
+
+// forTarget returns an emitter that passes events on to parent and counts the token usage they carry
+func (g *queryBudgetGuard) forTarget(target arkv1alpha1.QueryTarget, parent genai.EventEmitter) genai.EventEmitter {
+	return &budgetEmitter{guard: g, target: target, parent: parent}
+}
+
+type budgetEmitter struct {
+	guard  *queryBudgetGuard
+	target arkv1alpha1.QueryTarget
+	parent genai.EventEmitter
+}
+
+func (e *budgetEmitter) EmitEvent(ctx context.Context, eventType string, data genai.EventData) {
+	e.parent.EmitEvent(ctx, eventType, data)
+
+	if opEvent, ok := data.(genai.OperationEvent); ok && opEvent.TokenUsage.TotalTokens > 0 {
+		e.guard.record(ctx, e.target, opEvent.TokenUsage)
+	}
+}
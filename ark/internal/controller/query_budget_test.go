@@ -0,0 +1,112 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+type discardEmitter struct{}
+
+func (discardEmitter) EmitEvent(ctx context.Context, eventType string, data genai.EventData) {}
+
+var _ = Describe("Query budget guard", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+	)
+
+	maxTokens := int64(100)
+	weather := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+
+	newQuery := func(name string) arkv1alpha1.Query {
+		return arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), CreationTimestamp: metav1.Now()},
+			Spec:       arkv1alpha1.QuerySpec{Targets: []arkv1alpha1.QueryTarget{weather}},
+		}
+	}
+	modelCall := func(tokens int64) genai.OperationEvent {
+		return genai.OperationEvent{TokenUsage: genai.TokenUsage{TotalTokens: tokens}}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithStatusSubresource(&arkv1alpha1.TokenBudget{}).
+			WithObjects(&arkv1alpha1.TokenBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+				Spec:       arkv1alpha1.TokenBudgetSpec{MaxTokens: &maxTokens},
+			}).
+			Build()
+	})
+
+	It("aborts the query once its usage reaches the budget", func() {
+		query := newQuery("greedy")
+		execCtx, abort := context.WithCancelCause(ctx)
+		defer abort(nil)
+
+		guard, err := loadQueryBudgetGuard(ctx, fakeClient, query, budgetConsumer("Query", &query), abort)
+		Expect(err).NotTo(HaveOccurred())
+		emitter := guard.forTarget(weather, discardEmitter{})
+
+		emitter.EmitEvent(ctx, "LLMCall", modelCall(60))
+		Expect(execCtx.Err()).NotTo(HaveOccurred())
+
+		emitter.EmitEvent(ctx, "LLMCall", modelCall(40))
+		Expect(execCtx.Err()).To(HaveOccurred())
+		Expect(genai.IsTokenBudgetExceeded(context.Cause(execCtx))).To(BeTrue())
+	})
+
+	It("shares the budget between queries running at the same time", func() {
+		first, second := newQuery("first"), newQuery("second")
+		firstCtx, abortFirst := context.WithCancelCause(ctx)
+		defer abortFirst(nil)
+		secondCtx, abortSecond := context.WithCancelCause(ctx)
+		defer abortSecond(nil)
+
+		firstGuard, err := loadQueryBudgetGuard(ctx, fakeClient, first, budgetConsumer("Query", &first), abortFirst)
+		Expect(err).NotTo(HaveOccurred())
+		secondGuard, err := loadQueryBudgetGuard(ctx, fakeClient, second, budgetConsumer("Query", &second), abortSecond)
+		Expect(err).NotTo(HaveOccurred())
+
+		firstGuard.forTarget(weather, discardEmitter{}).EmitEvent(ctx, "LLMCall", modelCall(70))
+		Expect(firstCtx.Err()).NotTo(HaveOccurred())
+
+		secondGuard.forTarget(weather, discardEmitter{}).EmitEvent(ctx, "LLMCall", modelCall(30))
+		Expect(genai.IsTokenBudgetExceeded(context.Cause(secondCtx))).To(BeTrue())
+		Expect(firstGuard.check(ctx, weather)).To(HaveOccurred(), "the first query cannot start another target either")
+	})
+
+	It("records the usage of each query in the budget status", func() {
+		query := newQuery("recorded")
+		_, abort := context.WithCancelCause(ctx)
+		defer abort(nil)
+
+		guard, err := loadQueryBudgetGuard(ctx, fakeClient, query, budgetConsumer("Query", &query), abort)
+		Expect(err).NotTo(HaveOccurred())
+		emitter := guard.forTarget(weather, discardEmitter{})
+		emitter.EmitEvent(ctx, "LLMCall", modelCall(20))
+		emitter.EmitEvent(ctx, "LLMCall", modelCall(15))
+
+		var budget arkv1alpha1.TokenBudget
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "daily", Namespace: "default"}, &budget)).To(Succeed())
+		Expect(budget.Status.Consumers).To(HaveLen(1))
+		Expect(budget.Status.Consumers[0].UID).To(Equal(query.UID))
+		Expect(budget.Status.Consumers[0].Kind).To(Equal("Query"))
+		Expect(budget.Status.Consumers[0].TokenUsage.TotalTokens).To(Equal(int64(35)))
+	})
+})
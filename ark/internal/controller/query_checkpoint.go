@@ -0,0 +1,371 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const checkpointQueryLabel = "ark.mckinsey.com/checkpoint-query"
+
+// maxCheckpointSize keeps the checkpoint ConfigMap well under the 1MiB limit of Kubernetes objects
+const maxCheckpointSize = 768 * 1024
+
+// targetCheckpoint is the durable record of one target's execution
+type targetCheckpoint struct {
+	Target            arkv1alpha1.QueryTarget                  `json:"target"`
+	Completed         bool                                     `json:"completed,omitempty"`
+	TerminationReason string                                   `json:"terminationReason,omitempty"`
+	TokenUsage        genai.TokenUsage                         `json:"tokenUsage,omitempty"`
+	Duration          metav1.Duration                          `json:"duration,omitempty"`
+	Messages          []openai.ChatCompletionMessageParamUnion `json:"messages,omitempty"`
+	ToolCalls         []checkpointToolCall                     `json:"toolCalls,omitempty"`
+	Scratchpad        map[string]string                        `json:"scratchpad,omitempty"`
+	Models            []string                                 `json:"models,omitempty"`
+	// Truncated is set when the messages were left out to keep the checkpoint under its size limit
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// checkpointToolCall is written before the tool call runs
+type checkpointToolCall struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	SideEffects bool   `json:"sideEffects,omitempty"`
+}
+
+// queryCheckpoint keeps the progress of a running query in a ConfigMap owned by the query, one key per target.
+// A controller that picks up a query left in the running phase uses it to reuse completed targets, resume agents
+// from their last turn, and refuse to repeat tool calls with side effects that may already have happened.
+// Checkpoints are best effort: a failed write is reported with a CheckpointFailed event and the query carries on.
+type queryCheckpoint struct {
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
+	query    arkv1alpha1.Query
+	mu       sync.Mutex
+	targets  map[string]*targetCheckpoint
+	exists   bool
+	disabled bool
+	failed   bool
+}
+
+func checkpointName(query arkv1alpha1.Query) types.NamespacedName {
+	return types.NamespacedName{Name: query.Name + "-checkpoint", Namespace: query.Namespace}
+}
+
+// targetKey is the ConfigMap key under which per-target data of a query is stored
+func targetKey(target arkv1alpha1.QueryTarget) string {
+	return target.Type + "." + target.Name
+}
+
+// loadQueryCheckpoint reads the checkpoint left by an earlier execution of the query, if there is one. Queries with
+// spec.checkpoint set to false are neither checkpointed nor resumed.
+func loadQueryCheckpoint(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, recorder record.EventRecorder, query arkv1alpha1.Query) (*queryCheckpoint, error) {
+	checkpoint := &queryCheckpoint{
+		client:   k8sClient,
+		scheme:   scheme,
+		recorder: recorder,
+		query:    query,
+		targets:  map[string]*targetCheckpoint{},
+		disabled: query.Spec.Checkpoint != nil && !*query.Spec.Checkpoint,
+	}
+	if checkpoint.disabled {
+		return checkpoint, nil
+	}
+
+	var configMap corev1.ConfigMap
+	if err := k8sClient.Get(ctx, checkpointName(query), &configMap); err != nil {
+		if errors.IsNotFound(err) {
+			return checkpoint, nil
+		}
+		return nil, fmt.Errorf("failed to get query checkpoint: %w", err)
+	}
+	if configMap.Labels[checkpointQueryLabel] != query.Name || !metav1.IsControlledBy(&configMap, &query) {
+		return nil, fmt.Errorf("configmap %s exists and does not hold the checkpoint of this query", configMap.Name)
+	}
+
+	checkpoint.exists = true
+	for key, data := range configMap.Data {
+		var target targetCheckpoint
+		if err := json.Unmarshal([]byte(data), &target); err != nil {
+			return nil, fmt.Errorf("failed to parse checkpoint of %s: %w", key, err)
+		}
+		checkpoint.targets[key] = &target
+	}
+	return checkpoint, nil
+}
+
+// resumed reports whether an earlier execution left progress behind
+func (c *queryCheckpoint) resumed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.targets) > 0
+}
+
+// completedResult returns the stored result of a target that finished before the interruption
+func (c *queryCheckpoint) completedResult(target arkv1alpha1.QueryTarget) (targetResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	record, ok := c.targets[targetKey(target)]
+	if !ok || !record.Completed || record.Truncated {
+		return targetResult{}, false
+	}
+
+	result := targetResult{
+		messages:   fromOpenAIMessages(record.Messages),
+		target:     target,
+		tokenUsage: record.TokenUsage,
+		duration:   record.Duration.Duration,
+		scratchpad: record.Scratchpad,
+		models:     record.Models,
+	}
+	if record.TerminationReason != "" {
+		result.err = &genai.ExecutionLimitReached{Agent: target.Name, Reason: record.TerminationReason}
+	}
+	return result, true
+}
+
+// resumeProgress decides how an unfinished target continues. Agents pick up from their checkpointed messages, any
+// other target, and any target whose messages were truncated, starts over. Either way it is an error if a tool call
+// with side effects was started and its result was not recorded, because running the target again could repeat it.
+func (c *queryCheckpoint) resumeProgress(target arkv1alpha1.QueryTarget) ([]genai.Message, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	record, ok := c.targets[targetKey(target)]
+	if !ok {
+		return nil, nil
+	}
+
+	var progress []genai.Message
+	unfinished := map[string]bool{}
+	resumable := target.Type == "agent" && !record.Truncated
+	if resumable {
+		progress = fromOpenAIMessages(record.Messages)
+		for _, toolCall := range genai.PendingToolCalls(progress) {
+			unfinished[toolCall.ID] = true
+		}
+	}
+
+	for _, toolCall := range record.ToolCalls {
+		if !toolCall.SideEffects {
+			continue
+		}
+		if !resumable || unfinished[toolCall.ID] {
+			return nil, fmt.Errorf("%s %s was interrupted after starting tool call %s (%s), which may have side effects and is not repeated",
+				target.Type, target.Name, toolCall.ID, toolCall.Name)
+		}
+	}
+	return progress, nil
+}
+
+// forTarget returns the checkpointer a target's execution should use, or nil when checkpointing is disabled
+func (c *queryCheckpoint) forTarget(target arkv1alpha1.QueryTarget) genai.Checkpointer {
+	if c.disabled {
+		return nil
+	}
+	return &targetCheckpointer{checkpoint: c, target: target}
+}
+
+// complete records the result of a target so it is reused if the query is resumed
+func (c *queryCheckpoint) complete(ctx context.Context, result targetResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	record := c.record(result.target)
+	record.Completed = true
+	record.TerminationReason = genai.TerminationReason(result.err)
+	record.TokenUsage = result.tokenUsage
+	record.Duration = metav1.Duration{Duration: result.duration}
+	record.Messages = toOpenAIMessages(result.messages)
+	record.Scratchpad = result.scratchpad
+	record.Models = result.models
+	return c.save(ctx)
+}
+
+// delete removes the checkpoint once the query no longer needs to be resumed
+func (c *queryCheckpoint) delete(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.exists {
+		return
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: checkpointName(c.query).Name, Namespace: c.query.Namespace}}
+	if err := c.client.Delete(ctx, configMap); client.IgnoreNotFound(err) != nil {
+		logf.FromContext(ctx).Error(err, "failed to delete query checkpoint")
+		return
+	}
+	c.exists = false
+}
+
+// record returns the checkpoint of a target, creating it if needed. Callers must hold c.mu.
+func (c *queryCheckpoint) record(target arkv1alpha1.QueryTarget) *targetCheckpoint {
+	key := targetKey(target)
+	record, ok := c.targets[key]
+	if !ok {
+		record = &targetCheckpoint{Target: target}
+		c.targets[key] = record
+	}
+	return record
+}
+
+// save writes every target's checkpoint to the ConfigMap. A failed write is reported once with an event. Callers
+// must hold c.mu.
+func (c *queryCheckpoint) save(ctx context.Context) error {
+	if c.disabled {
+		return nil
+	}
+	err := c.write(ctx)
+	if err != nil && !c.failed {
+		c.failed = true
+		c.recorder.Event(&c.query, corev1.EventTypeWarning, "CheckpointFailed",
+			fmt.Sprintf("query continues without an up to date checkpoint, so it may not resume after a restart: %v", err))
+	}
+	return err
+}
+
+// encode returns the ConfigMap data of the checkpoint. When it is over maxCheckpointSize, the messages of the
+// largest targets are left out until it fits, and those targets start over if the query is resumed.
+func (c *queryCheckpoint) encode() (map[string]string, error) {
+	data := make(map[string]string, len(c.targets))
+	size := 0
+	for key, record := range c.targets {
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal checkpoint of %s: %w", key, err)
+		}
+		data[key] = string(encoded)
+		size += len(encoded)
+	}
+
+	keys := slices.SortedFunc(maps.Keys(data), func(a, b string) int {
+		return cmp.Compare(len(data[b]), len(data[a]))
+	})
+	for _, key := range keys {
+		if size <= maxCheckpointSize {
+			break
+		}
+		truncated := *c.targets[key]
+		truncated.Messages = nil
+		truncated.Truncated = true
+		encoded, err := json.Marshal(truncated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal checkpoint of %s: %w", key, err)
+		}
+		size += len(encoded) - len(data[key])
+		data[key] = string(encoded)
+	}
+	return data, nil
+}
+
+func (c *queryCheckpoint) write(ctx context.Context) error {
+	data, err := c.encode()
+	if err != nil {
+		return err
+	}
+
+	name := checkpointName(c.query)
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name.Name,
+			Namespace: name.Namespace,
+			Labels:    map[string]string{checkpointQueryLabel: c.query.Name},
+		},
+		Data: data,
+	}
+
+	if !c.exists {
+		if err := controllerutil.SetControllerReference(&c.query, configMap, c.scheme); err != nil {
+			return fmt.Errorf("failed to set owner of query checkpoint: %w", err)
+		}
+		if err := c.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create query checkpoint: %w", err)
+		}
+		c.exists = true
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{"data": data})
+	if err != nil {
+		return err
+	}
+	if err := c.client.Patch(ctx, configMap, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to update query checkpoint: %w", err)
+	}
+	return nil
+}
+
+func toOpenAIMessages(messages []genai.Message) []openai.ChatCompletionMessageParamUnion {
+	if messages == nil {
+		return nil
+	}
+	converted := make([]openai.ChatCompletionMessageParamUnion, len(messages))
+	for i, message := range messages {
+		converted[i] = openai.ChatCompletionMessageParamUnion(message)
+	}
+	return converted
+}
+
+func fromOpenAIMessages(messages []openai.ChatCompletionMessageParamUnion) []genai.Message {
+	if messages == nil {
+		return nil
+	}
+	converted := make([]genai.Message, len(messages))
+	for i, message := range messages {
+		converted[i] = genai.Message(message)
+	}
+	return converted
+}
+
+type targetCheckpointer struct {
+	checkpoint *queryCheckpoint
+	target     arkv1alpha1.QueryTarget
+}
+
+// SaveProgress only keeps the progress of an agent target's own agent. Teams and nested agents cannot be resumed
+// from the middle and are restarted instead.
+func (t *targetCheckpointer) SaveProgress(ctx context.Context, agent string, messages []genai.Message) error {
+	if t.target.Type != "agent" || t.target.Name != agent {
+		return nil
+	}
+
+	t.checkpoint.mu.Lock()
+	defer t.checkpoint.mu.Unlock()
+	t.checkpoint.record(t.target).Messages = toOpenAIMessages(messages)
+	return t.checkpoint.save(ctx)
+}
+
+func (t *targetCheckpointer) ToolCallStarted(ctx context.Context, toolCall genai.ToolCall, sideEffects bool) error {
+	t.checkpoint.mu.Lock()
+	defer t.checkpoint.mu.Unlock()
+
+	record := t.checkpoint.record(t.target)
+	record.ToolCalls = append(record.ToolCalls, checkpointToolCall{
+		ID:          toolCall.ID,
+		Name:        toolCall.Function.Name,
+		SideEffects: sideEffects,
+	})
+	return t.checkpoint.save(ctx)
+}
@@ -0,0 +1,137 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var _ = Describe("Query checkpoints", func() {
+	var (
+		ctx        context.Context
+		server     *fakeModelServer
+		fakeClient client.Client
+		recorder   *record.FakeRecorder
+		reconciler *QueryReconciler
+	)
+
+	weather := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+	echo := arkv1alpha1.QueryTarget{Type: "model", Name: "echo"}
+
+	// interrupt creates a running query together with the checkpoint an interrupted controller left behind
+	interrupt := func(query *arkv1alpha1.Query, targets ...targetCheckpoint) {
+		status := query.Status
+		Expect(fakeClient.Create(ctx, query)).To(Succeed())
+		query.Status = status
+		Expect(fakeClient.Status().Update(ctx, query)).To(Succeed())
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      checkpointName(*query).Name,
+				Namespace: query.Namespace,
+				Labels:    map[string]string{checkpointQueryLabel: query.Name},
+			},
+			Data: map[string]string{},
+		}
+		for _, target := range targets {
+			data, err := json.Marshal(target)
+			Expect(err).NotTo(HaveOccurred())
+			configMap.Data[targetKey(target.Target)] = string(data)
+		}
+		Expect(controllerutil.SetControllerReference(query, configMap, fakeClient.Scheme())).To(Succeed())
+		Expect(fakeClient.Create(ctx, configMap)).To(Succeed())
+	}
+
+	response := func(query *arkv1alpha1.Query, target arkv1alpha1.QueryTarget) arkv1alpha1.Response {
+		for _, response := range query.Status.Responses {
+			if response.Target == target {
+				return response
+			}
+		}
+		Fail("no response for " + targetKey(target))
+		return arkv1alpha1.Response{}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(os.Setenv("SKIP_IMPERSONATION", "true")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "SKIP_IMPERSONATION")
+
+		server = newFakeModelServer(map[string]fakeModelReply{"echo": replyContent("echoed")})
+		DeferCleanup(server.Close)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(server.model("echo", "default")).
+			Build()
+		recorder = record.NewFakeRecorder(1000)
+		reconciler = &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: recorder}
+	})
+
+	It("refuses to resume a target interrupted during a tool call with side effects", func() {
+		query := newRunningQuery("cleanup", weather)
+		interrupt(query, targetCheckpoint{
+			Target: weather,
+			Messages: toOpenAIMessages([]genai.Message{
+				genai.NewUserMessage("clean up the forecasts"),
+				genai.Message(openai.ChatCompletionMessage{
+					Role: "assistant",
+					ToolCalls: []openai.ChatCompletionMessageToolCall{{
+						ID:       "call-1",
+						Type:     "function",
+						Function: openai.ChatCompletionMessageToolCallFunction{Name: "delete_forecast", Arguments: `{"city": "Paris"}`},
+					}},
+				}.ToParam()),
+			}),
+			ToolCalls: []checkpointToolCall{{ID: "call-1", Name: "delete_forecast", SideEffects: true}},
+		})
+
+		query = runQuery(ctx, reconciler, query)
+
+		Expect(query.Status.Phase).To(Equal(statusError))
+		refused := response(query, weather)
+		Expect(refused.Phase).To(Equal(statusError))
+		Expect(refused.Error).To(ContainSubstring("starting tool call call-1 (delete_forecast), which may have side effects and is not repeated"))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("ResumeRefused")))
+
+		var checkpoint corev1.ConfigMap
+		err := fakeClient.Get(ctx, checkpointName(*query), &checkpoint)
+		Expect(errors.IsNotFound(err)).To(BeTrue(), "the checkpoint of a finished query is deleted")
+	})
+
+	It("reuses the result of a target that completed before the interruption", func() {
+		query := newRunningQuery("forecast", echo)
+		interrupt(query, targetCheckpoint{
+			Target:     echo,
+			Completed:  true,
+			TokenUsage: genai.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			Messages:   toOpenAIMessages([]genai.Message{genai.NewUserMessage("what is the forecast"), genai.NewAssistantMessage("sunny")}),
+		})
+
+		query = runQuery(ctx, reconciler, query)
+
+		Expect(query.Status.Phase).To(Equal(statusDone))
+		Expect(server.calls("echo")).To(BeZero(), "the completed target is not run again")
+		reused := response(query, echo)
+		Expect(reused.Phase).To(Equal(statusDone))
+		Expect(reused.Content).To(Equal("sunny"))
+		Expect(reused.TokenUsage.TotalTokens).To(Equal(int64(15)))
+	})
+})
@@ -5,8 +5,10 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +22,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
@@ -28,9 +31,13 @@ import (
 )
 
 type targetResult struct {
-	messages []genai.Message
-	err      error
-	target   arkv1alpha1.QueryTarget
+	messages   []genai.Message
+	err        error
+	target     arkv1alpha1.QueryTarget
+	tokenUsage genai.TokenUsage
+	duration   time.Duration
+	scratchpad map[string]string
+	models     []string
 }
 
 type QueryReconciler struct {
@@ -38,6 +45,7 @@ type QueryReconciler struct {
 	Scheme     *runtime.Scheme
 	Recorder   record.EventRecorder
 	operations sync.Map
+	approvals  sync.Map
 }
 
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;create;update;patch;delete
@@ -48,6 +56,7 @@ type QueryReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluators,verbs=get;list
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,resourceNames=default,verbs=impersonate
 
 func (r *QueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -106,6 +115,7 @@ func (r *QueryReconciler) handleQueryExecution(ctx context.Context, req ctrl.Req
 
 	if obj.Spec.Cancel && obj.Status.Phase != statusCanceled {
 		r.cleanupExistingOperation(req.NamespacedName)
+		obj.Status.PendingApprovals = nil
 		if err := r.updateStatus(ctx, &obj, statusCanceled); err != nil {
 			return ctrl.Result{
 				RequeueAfter: time.Until(expiry),
@@ -121,9 +131,12 @@ func (r *QueryReconciler) handleQueryExecution(ctx context.Context, req ctrl.Req
 		}, nil
 	case statusEvaluating:
 		return r.handleEvaluationPhase(ctx, req, obj)
-	case statusRunning:
+	case statusRunning, statusAwaitingApproval:
 		return r.handleRunningPhase(ctx, req, obj)
 	default:
+		if waiting, err := r.waitForUpstreamQueries(ctx, &obj); waiting {
+			return ctrl.Result{}, err
+		}
 		if err := r.updateStatus(ctx, &obj, statusRunning); err != nil {
 			return ctrl.Result{
 				RequeueAfter: time.Until(expiry),
@@ -148,7 +161,13 @@ func (r *QueryReconciler) handleRunningPhase(ctx context.Context, req ctrl.Reque
 
 	if _, exists := r.operations.Load(req.NamespacedName); exists {
 		log.Info("Exists")
-		return ctrl.Result{}, nil
+		if gate, ok := r.approvals.Load(req.NamespacedName); ok {
+			gate.(*queryApprovalGate).resolve(ctx, obj.Spec.Approvals)
+		}
+		// Approval waits are not bound by the timeout, so the TTL has to end a query nobody decides on
+		return ctrl.Result{
+			RequeueAfter: time.Until(obj.CreationTimestamp.Add(obj.Spec.TTL.Duration)),
+		}, nil
 	}
 
 	opCtx, cancel := context.WithCancel(ctx)
@@ -189,23 +208,66 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 		return
 	}
 
-	responses, err := r.reconcileQueue(opCtx, obj, impersonatedClient, memory, tokenCollector)
+	checkpoint, err := loadQueryCheckpoint(opCtx, r.Client, r.Scheme, r.Recorder, obj)
 	if err != nil {
 		queryTracker.Fail(err)
 		_ = r.updateStatus(opCtx, &obj, statusError)
 		return
 	}
+	if checkpoint.resumed() {
+		log.Info("resuming query from checkpoint", "query", obj.Name)
+		r.Recorder.Event(&obj, "Normal", "QueryResumed", "resuming execution from the checkpoint of an interrupted run")
+	}
+	defer func() {
+		// An interrupted execution keeps its checkpoint so the next controller can pick it up
+		if opCtx.Err() == nil {
+			checkpoint.delete(opCtx)
+		}
+	}()
 
-	queryTracker.Complete("resolved")
-	obj.Status.Responses = responses
+	var stream *queryStreamWriter
+	if obj.Spec.Stream {
+		stream = newQueryStreamWriter(r.Client, obj)
+		stream.start(opCtx)
+	}
+
+	approvals := newQueryApprovalGate(r.Client, obj)
+	r.approvals.Store(namespacedName, approvals)
+	defer r.approvals.Delete(namespacedName)
 
+	// The budget guard cancels execCtx, with the exceeded budget as the cause, once a TokenBudget runs out
+	execCtx, abort := context.WithCancelCause(opCtx)
+	defer abort(nil)
+	budgets, err := loadQueryBudgetGuard(opCtx, r.Client, obj, budgetConsumer("Query", &obj), abort)
+	if err != nil {
+		queryTracker.Fail(err)
+		_ = r.updateStatus(opCtx, &obj, statusError)
+		return
+	}
+
+	responses, err := r.reconcileQueue(execCtx, obj, impersonatedClient, memory, tokenCollector, stream, approvals, checkpoint, budgets)
+	if stream != nil {
+		stream.stop(opCtx, &obj)
+	}
+	approvals.finish(opCtx, &obj)
+	if cause := context.Cause(execCtx); genai.IsTokenBudgetExceeded(cause) {
+		r.Recorder.Event(&obj, "Warning", "TokenBudgetExceeded", cause.Error())
+	}
+
+	// Responses are kept when some targets failed, so the ones that succeeded are not lost
+	obj.Status.Responses = responses
 	tokenSummary := tokenCollector.GetTokenSummary()
-	obj.Status.TokenUsage = arkv1alpha1.TokenUsage{
-		PromptTokens:     tokenSummary.PromptTokens,
-		CompletionTokens: tokenSummary.CompletionTokens,
-		TotalTokens:      tokenSummary.TotalTokens,
+	obj.Status.TokenUsage = toTokenUsage(tokenSummary)
+	obj.Status.Cost = genai.FormatCost(tokenSummary.Cost)
+
+	if err != nil {
+		queryTracker.Fail(err)
+		_ = r.updateStatus(opCtx, &obj, statusError)
+		return
 	}
 
+	queryTracker.Complete("resolved")
+
 	evaluators, evalErr := r.resolveEvaluators(opCtx, obj, impersonatedClient)
 	if evalErr != nil {
 		log.Error(evalErr, "Failed to resolve evaluators")
@@ -372,7 +434,7 @@ func (r *QueryReconciler) resolveEvaluatorSelector(ctx context.Context, selector
 	return evaluators, nil
 }
 
-func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]arkv1alpha1.Response, error) {
+func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector, stream *queryStreamWriter, approvals *queryApprovalGate, checkpoint *queryCheckpoint, budgets *queryBudgetGuard) ([]arkv1alpha1.Response, error) {
 	targets, err := r.resolveTargets(ctx, query, impersonatedClient)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve targets: %w", err)
@@ -383,30 +445,125 @@ func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.
 	var wg sync.WaitGroup
 
 	for _, target := range targets {
+		targetCtx := genai.WithToolApprover(ctx, approvals.forTarget(target))
+		if checkpointer := checkpoint.forTarget(target); checkpointer != nil {
+			targetCtx = genai.WithCheckpointer(targetCtx, checkpointer)
+		}
+		if stream != nil {
+			targetCtx = genai.WithStreamPublisher(targetCtx, stream.addTarget(target))
+		}
+
 		wg.Add(1)
-		go func(target arkv1alpha1.QueryTarget) {
+		go func(ctx context.Context, target arkv1alpha1.QueryTarget) {
 			defer wg.Done()
-			responses, err := r.executeTarget(ctx, query, target, impersonatedClient, memory, tokenCollector)
-			resultChan <- targetResult{responses, err, target}
-		}(target)
+			if result, completed := checkpoint.completedResult(target); completed {
+				resultChan <- result
+				return
+			}
+
+			progress, err := checkpoint.resumeProgress(target)
+			if err != nil {
+				r.Recorder.Event(&query, "Warning", "ResumeRefused", err.Error())
+				resultChan <- targetResult{err: err, target: target}
+				return
+			}
+
+			if err := budgets.check(ctx, target); err != nil {
+				resultChan <- targetResult{err: err, target: target}
+				return
+			}
+
+			// Each target collects its own token usage, which still adds up to the query total
+			targetCollector := genai.NewTokenUsageCollector(budgets.forTarget(target, tokenCollector))
+			scratchpad := genai.NewScratchpad()
+			start := time.Now()
+			messages, err := r.executeTarget(genai.WithScratchpad(ctx, scratchpad), query, target, impersonatedClient, memory, targetCollector, progress)
+			if cause := context.Cause(ctx); err != nil && genai.IsTokenBudgetExceeded(cause) {
+				err = cause
+			}
+			result := targetResult{
+				messages:   messages,
+				err:        err,
+				target:     target,
+				tokenUsage: targetCollector.GetTokenSummary(),
+				duration:   time.Since(start),
+				scratchpad: scratchpad.Snapshot(),
+				models:     targetCollector.GetModels(),
+			}
+			recordTargetUsage(query, target, result.tokenUsage)
+
+			if !isTargetFailure(err) {
+				if checkpointErr := checkpoint.complete(ctx, result); checkpointErr != nil {
+					logf.FromContext(ctx).Error(checkpointErr, "failed to checkpoint completed target", "target", target.Name)
+				}
+			}
+			resultChan <- result
+		}(targetCtx, target)
 	}
 
 	wg.Wait()
 	close(resultChan)
 
+	var failures []string
+	transcripts := map[string][]genai.Message{}
 	for result := range resultChan {
-		if result.err != nil {
-			return nil, result.err
+		if isTargetFailure(result.err) {
+			failures = append(failures, fmt.Sprintf("%s %s: %v", result.target.Type, result.target.Name, result.err))
+		} else if result.messages == nil {
+			// Skip targets that were delegated to external execution engines
+			continue
 		}
-		// Skip targets that were delegated to external execution engines (messages == nil)
 		if result.messages != nil {
-			allResponses = append(allResponses, arkv1alpha1.Response{Target: result.target, Content: makeResponse(result.messages)})
+			transcripts[targetKey(result.target)] = result.messages
 		}
+		allResponses = append(allResponses, makeTargetResponse(result))
+	}
+
+	if query.Spec.Transcript && len(transcripts) > 0 {
+		r.saveTranscripts(ctx, query, transcripts, allResponses)
 	}
 
+	if len(failures) > 0 {
+		return allResponses, fmt.Errorf("%d of %d targets failed: %s", len(failures), len(targets), strings.Join(failures, "; "))
+	}
 	return allResponses, nil
 }
 
+// isTargetFailure reports whether err failed the target. Reaching an execution limit still produces a response.
+func isTargetFailure(err error) bool {
+	return err != nil && !genai.IsExecutionLimitReached(err)
+}
+
+func makeTargetResponse(result targetResult) arkv1alpha1.Response {
+	response := arkv1alpha1.Response{
+		Target:            result.target,
+		Phase:             statusDone,
+		TerminationReason: genai.TerminationReason(result.err),
+		TokenUsage:        toTokenUsage(result.tokenUsage),
+		Cost:              genai.FormatCost(result.tokenUsage.Cost),
+		Duration:          &metav1.Duration{Duration: result.duration},
+		MessageCount:      len(result.messages),
+		Scratchpad:        result.scratchpad,
+		Models:            result.models,
+	}
+
+	if isTargetFailure(result.err) {
+		response.Phase = statusError
+		response.Error = result.err.Error()
+	} else if len(result.messages) > 0 {
+		response.Content = makeResponse(result.messages)
+	}
+	return response
+}
+
+func toTokenUsage(usage genai.TokenUsage) arkv1alpha1.TokenUsage {
+	return arkv1alpha1.TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
+}
+
 func makeResponse(messages []genai.Message) string {
 	lastMessage := messages[len(messages)-1]
 	switch {
@@ -448,7 +605,7 @@ func (r *QueryReconciler) finalize(ctx context.Context, query *arkv1alpha1.Query
 	}
 }
 
-func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector, progress []genai.Message) ([]genai.Message, error) {
 	// Create trace based on target type with input/output at trace level
 	tracer := telemetry.NewTraceContext()
 	ctx, span := tracer.StartSpan(ctx, fmt.Sprintf("query.%s", target.Type),
@@ -464,7 +621,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 	if query.Spec.Timeout != nil {
 		timeout = query.Spec.Timeout.Duration
 	}
-	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	execCtx, cancel := withTargetTimeout(ctx, timeout)
 	defer cancel()
 
 	var messages []genai.Message
@@ -472,7 +629,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 
 	switch target.Type {
 	case "agent":
-		messages, err = r.executeAgent(execCtx, query, target.Name, impersonatedClient, memory, tokenCollector)
+		messages, err = r.executeAgent(execCtx, query, target.Name, impersonatedClient, memory, tokenCollector, progress)
 	case "team":
 		messages, err = r.executeTeam(execCtx, query, target.Name, impersonatedClient, memory, tokenCollector)
 	case "model":
@@ -482,10 +639,16 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 	default:
 		panic(fmt.Errorf("unknown query target type:%s", target.Type))
 	}
+	if err != nil && errors.Is(context.Cause(execCtx), errTargetTimeout) {
+		err = fmt.Errorf("%w after %s: %w", errTargetTimeout, timeout, err)
+	}
 
 	metadata := map[string]string{"targetType": target.Type, "targetName": target.Name}
+	if reason := genai.TerminationReason(err); reason != "" {
+		metadata["terminationReason"] = reason
+	}
 
-	if err != nil {
+	if err != nil && !genai.IsExecutionLimitReached(err) {
 		telemetry.RecordError(span, err)
 		event := genai.ExecutionEvent{
 			BaseEvent: genai.BaseEvent{Name: target.Name, Metadata: metadata},
@@ -509,7 +672,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 	return messages, err
 }
 
-func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Query, agentName string, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Query, agentName string, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector, progress []genai.Message) ([]genai.Message, error) {
 	var agentCRD arkv1alpha1.Agent
 	agentKey := types.NamespacedName{Name: agentName, Namespace: query.Namespace}
 
@@ -538,9 +701,16 @@ func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Qu
 
 	userMessage := genai.NewUserMessage(resolvedInput)
 
-	responseMessages, err := agent.Execute(ctx, userMessage, messages)
-	if err != nil {
-		return nil, err
+	var responseMessages []genai.Message
+	if len(progress) > 0 {
+		log.Info("resuming agent from checkpoint", "agent", agentCRD.Name, "messages", len(progress))
+		responseMessages, err = agent.Resume(ctx, userMessage, messages, progress)
+	} else {
+		responseMessages, err = agent.Execute(ctx, userMessage, messages)
+	}
+	if err != nil && !genai.IsExecutionLimitReached(err) {
+		// The messages produced before the failure are still reported in the transcript
+		return responseMessages, err
 	}
 
 	// Save new messages to memory (user message + response messages)
@@ -549,7 +719,8 @@ func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Qu
 		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
 	}
 
-	return responseMessages, nil
+	// A reached limit is passed on with the partial messages so it can be reported on the response
+	return responseMessages, err
 }
 
 func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Query, teamName string, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
@@ -579,15 +750,15 @@ func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Que
 	userMessage := genai.NewUserMessage(resolvedInput)
 
 	responseMessages, err := team.Execute(ctx, userMessage, messages)
-	if err != nil {
-		return nil, err
+	if err != nil && !genai.IsExecutionLimitReached(err) {
+		return responseMessages, err
 	}
 
 	if err := memory.AddMessages(ctx, responseMessages); err != nil {
 		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
 	}
 
-	return responseMessages, nil
+	return responseMessages, err
 }
 
 func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Query, modelName string, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
@@ -626,20 +797,18 @@ func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Qu
 		"type":  "direct",
 	})
 
-	// Call model directly with chat completion
-	completion, err := model.ChatCompletion(ctx, allMessages, nil)
+	// Call model directly with chat completion, streaming chunks when the query asks for it
+	onFailover := func(from, to *genai.Model, err error) {
+		genai.NewExecutionRecorder(tokenCollector).ModelFailover(ctx, modelName, from.Name, to.Name, err)
+	}
+	completion, usedModel, err := model.ChatCompletionWithFailover(ctx, allMessages, nil, genai.NewStreamChunkHandler(ctx, modelName), onFailover)
 	if err != nil {
 		modelTracker.Fail(err)
 		return nil, fmt.Errorf("model chat completion failed: %w", err)
 	}
 
-	// Extract and track token usage
-	tokenUsage := genai.TokenUsage{
-		PromptTokens:     completion.Usage.PromptTokens,
-		CompletionTokens: completion.Usage.CompletionTokens,
-		TotalTokens:      completion.Usage.TotalTokens,
-	}
-	modelTracker.CompleteWithTokens("", tokenUsage)
+	// Extract and track token usage, priced for the model that answered
+	modelTracker.CompleteWithModel("", usedModel.TokenUsage(completion.Usage), usedModel.Name)
 
 	if len(completion.Choices) == 0 {
 		return nil, fmt.Errorf("model returned no completion choices")
@@ -699,6 +868,8 @@ func (r *QueryReconciler) executeTool(ctx context.Context, query arkv1alpha1.Que
 	}
 	toolRegistry.RegisterTool(toolDefinition, executor)
 
+	genai.RecordToolCallStarted(ctx, toolCall, !toolRegistry.IsParallelSafe(toolName))
+
 	// Execute the tool using the same ExecuteTool method agents use
 	result, err := toolRegistry.ExecuteTool(ctx, toolCall)
 	if err != nil {
@@ -818,8 +989,13 @@ func (r *QueryReconciler) executeEvaluation(ctx context.Context, obj arkv1alpha1
 }
 
 func (r *QueryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &arkv1alpha1.Query{}, queryParameterRefsField, indexQueryParameterRefs); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&arkv1alpha1.Query{}).
+		Watches(&arkv1alpha1.Query{}, handler.EnqueueRequestsFromMapFunc(r.findQueriesWaitingFor)).
 		Named("query").
 		Complete(r)
 }
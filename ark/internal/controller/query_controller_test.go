@@ -4,11 +4,16 @@ package controller
 
 import (
 	"context"
+	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -76,3 +81,110 @@ var _ = Describe("Query Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Query targets", func() {
+	var (
+		ctx        context.Context
+		server     *fakeModelServer
+		fakeClient client.Client
+		reconciler *QueryReconciler
+	)
+
+	echo := arkv1alpha1.QueryTarget{Type: "model", Name: "echo"}
+	fail := arkv1alpha1.QueryTarget{Type: "model", Name: "fail"}
+
+	run := func(query *arkv1alpha1.Query) *arkv1alpha1.Query {
+		status := query.Status
+		Expect(fakeClient.Create(ctx, query)).To(Succeed())
+		query.Status = status
+		Expect(fakeClient.Status().Update(ctx, query)).To(Succeed())
+		return runQuery(ctx, reconciler, query)
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(os.Setenv("SKIP_IMPERSONATION", "true")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "SKIP_IMPERSONATION")
+
+		server = newFakeModelServer(map[string]fakeModelReply{
+			"echo": replyContent("echoed"),
+			"fail": replyError("invalid request"),
+		})
+		DeferCleanup(server.Close)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(server.model("echo", "default"), server.model("fail", "default")).
+			Build()
+		reconciler = &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: record.NewFakeRecorder(1000)}
+	})
+
+	It("reports the phase of every target and keeps the responses of the ones that succeeded", func() {
+		query := run(newRunningQuery("forecast", echo, fail))
+
+		Expect(query.Status.Phase).To(Equal(statusError))
+		Expect(query.Status.Responses).To(HaveLen(2))
+		for _, response := range query.Status.Responses {
+			switch response.Target {
+			case echo:
+				Expect(response.Phase).To(Equal(statusDone))
+				Expect(response.Content).To(Equal("echoed"))
+				Expect(response.Error).To(BeEmpty())
+				Expect(response.TokenUsage.TotalTokens).To(Equal(int64(15)))
+			case fail:
+				Expect(response.Phase).To(Equal(statusError))
+				Expect(response.Content).To(BeEmpty())
+				Expect(response.Error).To(ContainSubstring("invalid request"))
+			default:
+				Fail("unexpected response for " + targetKey(response.Target))
+			}
+		}
+	})
+
+	It("completes the query when every target succeeds", func() {
+		query := run(newRunningQuery("echo-only", echo))
+
+		Expect(query.Status.Phase).To(Equal(statusDone))
+		Expect(query.Status.Responses).To(ConsistOf(HaveField("Phase", statusDone)))
+		Expect(query.Status.TokenUsage.TotalTokens).To(Equal(int64(15)))
+	})
+})
+
+// newRunningQuery returns a query that has been admitted and is ready to run its targets
+func newRunningQuery(name string, targets ...arkv1alpha1.QueryTarget) *arkv1alpha1.Query {
+	return &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			UID:               types.UID("uid-" + name),
+			CreationTimestamp: metav1.Now(),
+			Finalizers:        []string{finalizer},
+		},
+		Spec: arkv1alpha1.QuerySpec{
+			Input:   "what is the forecast",
+			Targets: targets,
+			TTL:     &metav1.Duration{Duration: time.Hour},
+		},
+		Status: arkv1alpha1.QueryStatus{Phase: statusRunning},
+	}
+}
+
+// runQuery reconciles a running query and waits for the execution it starts to finish, returning the query as
+// that execution left it
+func runQuery(ctx context.Context, reconciler *QueryReconciler, query *arkv1alpha1.Query) *arkv1alpha1.Query {
+	key := types.NamespacedName{Name: query.Name, Namespace: query.Namespace}
+	_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+	Expect(err).NotTo(HaveOccurred())
+
+	var result arkv1alpha1.Query
+	Eventually(func() string {
+		Expect(reconciler.Get(ctx, key, &result)).To(Succeed())
+		return result.Status.Phase
+	}, 10*time.Second).Should(BeElementOf(statusDone, statusError))
+	Eventually(func() bool {
+		_, running := reconciler.operations.Load(key)
+		return running
+	}).Should(BeFalse())
+	return &result
+}
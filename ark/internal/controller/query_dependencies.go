@@ -0,0 +1,123 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// waitForUpstreamQueries keeps a query pending until every query whose response it uses as a parameter is done.
+// It returns true while the query has to wait, or when it failed because an upstream query failed. The upstream
+// queries are read as the query's service account, the same way their responses are.
+func (r *QueryReconciler) waitForUpstreamQueries(ctx context.Context, query *arkv1alpha1.Query) (bool, error) {
+	log := logf.FromContext(ctx)
+
+	refs := genai.QueryParameterRefs(query.Namespace, query.Spec.Parameters)
+	if len(refs) == 0 {
+		return false, nil
+	}
+	impersonatedClient, err := r.getClientForQuery(*query)
+	if err != nil {
+		return true, err
+	}
+
+	for _, ref := range refs {
+		var upstream arkv1alpha1.Query
+		if err := impersonatedClient.Get(ctx, ref, &upstream); err != nil {
+			if errors.IsForbidden(err) {
+				message := fmt.Sprintf("query %s used as a parameter cannot be read: %v", ref.Name, err)
+				r.Recorder.Event(query, "Warning", "UpstreamQueryFailed", message)
+				return true, r.updateStatus(ctx, query, statusError)
+			}
+			if !errors.IsNotFound(err) {
+				return true, err
+			}
+			return true, r.markWaiting(ctx, query, fmt.Sprintf("waiting for query %s to be created", ref.Name))
+		}
+
+		switch upstream.Status.Phase {
+		case statusDone:
+			continue
+		case statusError, statusCanceled:
+			message := fmt.Sprintf("query %s used as a parameter ended in phase %s", ref.Name, upstream.Status.Phase)
+			log.Info("upstream query failed", "query", query.Name, "upstream", ref.Name, "phase", upstream.Status.Phase)
+			r.Recorder.Event(query, "Warning", "UpstreamQueryFailed", message)
+			return true, r.updateStatus(ctx, query, statusError)
+		case "", statusPending:
+			// The webhook rejects cycles, but queries created while it was unavailable could still form one
+			cycle, err := genai.QueryParameterCycle(ctx, impersonatedClient, query)
+			if err != nil {
+				return true, err
+			}
+			if cycle != nil {
+				message := fmt.Sprintf("queries used as parameters wait for this query: %s", genai.FormatQueryPath(query.Namespace, cycle))
+				r.Recorder.Event(query, "Warning", "UpstreamQueryCycle", message)
+				return true, r.updateStatus(ctx, query, statusError)
+			}
+			return true, r.markWaiting(ctx, query, fmt.Sprintf("waiting for query %s to be done", ref.Name))
+		default:
+			return true, r.markWaiting(ctx, query, fmt.Sprintf("waiting for query %s to be done", ref.Name))
+		}
+	}
+	return false, nil
+}
+
+func (r *QueryReconciler) markWaiting(ctx context.Context, query *arkv1alpha1.Query, message string) error {
+	if query.Status.Phase == statusPending {
+		return nil
+	}
+	r.Recorder.Event(query, "Normal", "WaitingForQuery", message)
+	return r.updateStatus(ctx, query, statusPending)
+}
+
+// queryParameterRefsField indexes queries by the queries whose responses they use as parameters
+const queryParameterRefsField = ".spec.parameters.valueFrom.queryRef"
+
+// indexQueryParameterRefs returns the queries a query uses as parameters, as values of queryParameterRefsField
+func indexQueryParameterRefs(obj client.Object) []string {
+	query := obj.(*arkv1alpha1.Query)
+	var refs []string
+	for _, ref := range genai.QueryParameterRefs(query.Namespace, query.Spec.Parameters) {
+		refs = append(refs, ref.String())
+	}
+	return refs
+}
+
+// findQueriesWaitingFor maps a finished query to the pending queries that use its response as a parameter. Queries
+// may reference queries in other namespaces, so the index is looked up across namespaces.
+func (r *QueryReconciler) findQueriesWaitingFor(ctx context.Context, obj client.Object) []reconcile.Request {
+	upstream := obj.(*arkv1alpha1.Query)
+	switch upstream.Status.Phase {
+	case statusDone, statusError, statusCanceled:
+	default:
+		return nil
+	}
+
+	upstreamName := types.NamespacedName{Name: upstream.Name, Namespace: upstream.Namespace}
+	var queries arkv1alpha1.QueryList
+	if err := r.List(ctx, &queries, client.MatchingFields{queryParameterRefsField: upstreamName.String()}); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list queries waiting for query", "query", upstreamName)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, query := range queries.Items {
+		if query.Status.Phase != "" && query.Status.Phase != statusPending {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: query.Name, Namespace: query.Namespace},
+		})
+	}
+	return requests
+}
@@ -0,0 +1,107 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query dependencies", func() {
+	BeforeEach(func() {
+		Expect(os.Setenv("SKIP_IMPERSONATION", "true")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "SKIP_IMPERSONATION")
+	})
+
+	newQuery := func(namespace, name, phase string, refs ...arkv1alpha1.QueryRef) *arkv1alpha1.Query {
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Status:     arkv1alpha1.QueryStatus{Phase: phase},
+		}
+		for _, ref := range refs {
+			query.Spec.Parameters = append(query.Spec.Parameters, arkv1alpha1.QueryParameter{
+				Name:      ref.Name,
+				ValueFrom: &arkv1alpha1.QueryParameterSource{QueryRef: &ref},
+			})
+		}
+		return query
+	}
+
+	It("wakes the pending queries that use a finished query as a parameter", func() {
+		research := newQuery("default", "research", statusDone)
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithIndex(&arkv1alpha1.Query{}, queryParameterRefsField, indexQueryParameterRefs).
+			WithObjects(
+				research,
+				newQuery("default", "summary", statusPending, arkv1alpha1.QueryRef{Name: "research"}),
+				newQuery("reports", "report", "", arkv1alpha1.QueryRef{Name: "research", Namespace: "default"}),
+				newQuery("default", "started", statusRunning, arkv1alpha1.QueryRef{Name: "research"}),
+				newQuery("default", "unrelated", statusPending, arkv1alpha1.QueryRef{Name: "other"}),
+				newQuery("reports", "same-name", statusPending, arkv1alpha1.QueryRef{Name: "research"}),
+			).
+			Build()
+		reconciler := &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: record.NewFakeRecorder(100)}
+
+		Expect(reconciler.findQueriesWaitingFor(context.Background(), research)).To(ConsistOf(
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "summary", Namespace: "default"}},
+			reconcile.Request{NamespacedName: types.NamespacedName{Name: "report", Namespace: "reports"}},
+		))
+	})
+
+	It("fails queries whose parameters wait for each other", func() {
+		summary := newQuery("default", "summary", statusPending, arkv1alpha1.QueryRef{Name: "research"})
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(summary, newQuery("default", "research", statusPending, arkv1alpha1.QueryRef{Name: "summary"})).
+			Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: recorder}
+
+		waiting, err := reconciler.waitForUpstreamQueries(context.Background(), summary)
+		Expect(waiting).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Status.Phase).To(Equal(statusError))
+		Expect(recorder.Events).To(Receive(ContainSubstring("summary -> research -> summary")))
+	})
+
+	It("fails a query whose service account cannot read the query it uses as a parameter", func() {
+		summary := newQuery("default", "summary", statusPending, arkv1alpha1.QueryRef{Name: "research", Namespace: "private"})
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(summary, newQuery("private", "research", statusRunning)).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if key.Namespace == "private" {
+						return errors.NewForbidden(schema.GroupResource{Group: "ark.mckinsey.com", Resource: "queries"}, key.Name, nil)
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			}).
+			Build()
+		recorder := record.NewFakeRecorder(100)
+		reconciler := &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: recorder}
+
+		waiting, err := reconciler.waitForUpstreamQueries(context.Background(), summary)
+		Expect(waiting).To(BeTrue())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summary.Status.Phase).To(Equal(statusError), "the phase of the unreadable query is not revealed")
+		Expect(recorder.Events).To(Receive(ContainSubstring("cannot be read")))
+	})
+})
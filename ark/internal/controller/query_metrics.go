@@ -0,0 +1,43 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var (
+	queryTargetTokens = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ark_query_target_tokens_total",
+			Help: "Tokens used by query targets, by namespace, target and token type",
+		},
+		[]string{"namespace", "target_type", "target_name", "token_type"},
+	)
+	queryTargetCost = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ark_query_target_cost_total",
+			Help: "Cost of the model calls made by query targets, in the currency of the models' pricing",
+		},
+		[]string{"namespace", "target_type", "target_name"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(queryTargetTokens, queryTargetCost)
+}
+
+// recordTargetUsage adds the tokens and cost of a target execution to the controller metrics
+func recordTargetUsage(query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, usage genai.TokenUsage) {
+	if usage.TotalTokens > 0 {
+		queryTargetTokens.WithLabelValues(query.Namespace, target.Type, target.Name, "prompt").Add(float64(usage.PromptTokens))
+		queryTargetTokens.WithLabelValues(query.Namespace, target.Type, target.Name, "completion").Add(float64(usage.CompletionTokens))
+	}
+	if usage.Cost > 0 {
+		queryTargetCost.WithLabelValues(query.Namespace, target.Type, target.Name).Add(usage.Cost)
+	}
+}
@@ -0,0 +1,159 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// streamFlushInterval bounds how often partial responses are written to the query status
+const streamFlushInterval = 250 * time.Millisecond
+
+// queryStreamWriter collects partial output from executing targets and periodically
+// merge-patches it into status.partialResponses, where clients watching the query pick it up
+type queryStreamWriter struct {
+	client          client.Client
+	query           types.NamespacedName
+	mu              sync.Mutex
+	responses       []arkv1alpha1.PartialResponse
+	dirty           bool
+	resourceVersion string
+	done            chan struct{}
+	stopped         chan struct{}
+}
+
+func newQueryStreamWriter(k8sClient client.Client, query arkv1alpha1.Query) *queryStreamWriter {
+	return &queryStreamWriter{
+		client:  k8sClient,
+		query:   types.NamespacedName{Name: query.Name, Namespace: query.Namespace},
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// addTarget registers a target and returns the publisher its execution should stream to
+func (w *queryStreamWriter) addTarget(target arkv1alpha1.QueryTarget) genai.StreamPublisher {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.responses = append(w.responses, arkv1alpha1.PartialResponse{Target: target})
+	return &targetStreamPublisher{writer: w, index: len(w.responses) - 1}
+}
+
+func (w *queryStreamWriter) start(ctx context.Context) {
+	go func() {
+		defer close(w.stopped)
+
+		ticker := time.NewTicker(streamFlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.flush(ctx)
+			case <-w.done:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// stop ends the flush loop and writes the output streamed since the last flush, so clients see the whole
+// response. The status patches bump the resource version, so it is carried over to query to keep the final
+// status update from conflicting with our own writes.
+func (w *queryStreamWriter) stop(ctx context.Context, query *arkv1alpha1.Query) {
+	close(w.done)
+	<-w.stopped
+	w.flush(ctx)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.resourceVersion != "" {
+		query.ResourceVersion = w.resourceVersion
+	}
+}
+
+func (w *queryStreamWriter) flush(ctx context.Context) {
+	w.mu.Lock()
+	if !w.dirty {
+		w.mu.Unlock()
+		return
+	}
+	patch, err := json.Marshal(map[string]any{
+		"status": map[string]any{
+			"partialResponses": w.responses,
+		},
+	})
+	w.dirty = false
+	w.mu.Unlock()
+
+	log := logf.FromContext(ctx)
+	if err != nil {
+		log.Error(err, "failed to marshal partial responses")
+		return
+	}
+
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: w.query.Name, Namespace: w.query.Namespace}}
+	if err := w.client.Status().Patch(ctx, query, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		log.V(1).Info("failed to publish partial responses", "error", err.Error())
+		return
+	}
+
+	w.mu.Lock()
+	w.resourceVersion = query.ResourceVersion
+	w.mu.Unlock()
+}
+
+type targetStreamPublisher struct {
+	writer *queryStreamWriter
+	index  int
+}
+
+func (p *targetStreamPublisher) Publish(chunk genai.StreamChunk) {
+	p.writer.mu.Lock()
+	defer p.writer.mu.Unlock()
+
+	response := &p.writer.responses[p.index]
+	if chunk.MessageStart {
+		response.Message++
+		response.Source = chunk.Source
+		response.Content = ""
+		response.ToolCalls = nil
+	}
+	response.Content += chunk.Content
+
+	if chunk.ToolCall != nil {
+		progress := arkv1alpha1.ToolCallProgress{
+			ID:        chunk.ToolCall.ID,
+			Name:      chunk.ToolCall.Name,
+			Arguments: chunk.ToolCall.Arguments,
+			Phase:     chunk.ToolCall.Phase,
+		}
+		updated := false
+		for i := range response.ToolCalls {
+			if response.ToolCalls[i].ID == progress.ID {
+				response.ToolCalls[i] = progress
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			response.ToolCalls = append(response.ToolCalls, progress)
+		}
+	}
+
+	p.writer.dirty = true
+}
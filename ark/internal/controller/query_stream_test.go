@@ -0,0 +1,39 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var _ = Describe("Query stream writer", func() {
+	It("writes the output streamed since the last flush when it stops", func() {
+		ctx := context.Background()
+		query := newRunningQuery("forecast")
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(query).
+			Build()
+
+		stream := newQueryStreamWriter(fakeClient, *query)
+		stream.start(ctx)
+		publisher := stream.addTarget(arkv1alpha1.QueryTarget{Type: "model", Name: "echo"})
+		publisher.Publish(genai.StreamChunk{MessageStart: true, Content: "sunny "})
+		publisher.Publish(genai.StreamChunk{Content: "all day"})
+		stream.stop(ctx, query)
+
+		var result arkv1alpha1.Query
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: query.Name, Namespace: query.Namespace}, &result)).To(Succeed())
+		Expect(result.Status.PartialResponses).To(ConsistOf(HaveField("Content", "sunny all day")))
+		Expect(query.ResourceVersion).To(Equal(result.ResourceVersion), "the final status update does not conflict")
+	})
+})
@@ -0,0 +1,64 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const transcriptQueryLabel = "ark.mckinsey.com/transcript-query"
+
+func transcriptName(query arkv1alpha1.Query) string {
+	return query.Name + "-transcript"
+}
+
+// saveTranscripts keeps the messages of every target in a ConfigMap owned by the query, one key per target, and
+// points each response at its key. Failing to save is reported as an event rather than failing the query.
+func (r *QueryReconciler) saveTranscripts(ctx context.Context, query arkv1alpha1.Query, transcripts map[string][]genai.Message, responses []arkv1alpha1.Response) {
+	log := logf.FromContext(ctx)
+
+	data := make(map[string]string, len(transcripts))
+	for key, messages := range transcripts {
+		encoded, err := json.Marshal(toOpenAIMessages(messages))
+		if err != nil {
+			log.Error(err, "failed to marshal transcript", "target", key)
+			continue
+		}
+		data[key] = string(encoded)
+	}
+
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: transcriptName(query), Namespace: query.Namespace}}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.ResourceVersion != "" && configMap.Labels[transcriptQueryLabel] != query.Name {
+			return fmt.Errorf("configmap %s exists and does not hold the transcript of this query", configMap.Name)
+		}
+		configMap.Labels = map[string]string{transcriptQueryLabel: query.Name}
+		configMap.Data = data
+		return controllerutil.SetControllerReference(&query, configMap, r.Scheme)
+	})
+	if err != nil {
+		log.Error(err, "failed to save query transcript")
+		r.Recorder.Event(&query, "Warning", "TranscriptFailed", err.Error())
+		return
+	}
+
+	for i := range responses {
+		key := targetKey(responses[i].Target)
+		if _, saved := data[key]; saved {
+			responses[i].TranscriptRef = &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMap.Name},
+				Key:                  key,
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query transcripts", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		recorder   *record.FakeRecorder
+		reconciler *QueryReconciler
+	)
+
+	echo := arkv1alpha1.QueryTarget{Type: "model", Name: "echo"}
+
+	run := func(query *arkv1alpha1.Query) *arkv1alpha1.Query {
+		query.Spec.Transcript = true
+		status := query.Status
+		Expect(fakeClient.Create(ctx, query)).To(Succeed())
+		query.Status = status
+		Expect(fakeClient.Status().Update(ctx, query)).To(Succeed())
+		return runQuery(ctx, reconciler, query)
+	}
+
+	transcript := func(name string) *corev1.ConfigMap {
+		var configMap corev1.ConfigMap
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, &configMap)).To(Succeed())
+		return &configMap
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(os.Setenv("SKIP_IMPERSONATION", "true")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "SKIP_IMPERSONATION")
+
+		server := newFakeModelServer(map[string]fakeModelReply{"echo": replyContent("echoed")})
+		DeferCleanup(server.Close)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Query{}).
+			WithObjects(server.model("echo", "default")).
+			Build()
+		recorder = record.NewFakeRecorder(1000)
+		reconciler = &QueryReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: recorder}
+	})
+
+	It("keeps the messages of each target in a ConfigMap owned by the query", func() {
+		query := run(newRunningQuery("forecast", echo))
+
+		Expect(query.Status.Phase).To(Equal(statusDone))
+		configMap := transcript("forecast-transcript")
+		Expect(configMap.Labels).To(HaveKeyWithValue(transcriptQueryLabel, "forecast"))
+		Expect(metav1.IsControlledBy(configMap, query)).To(BeTrue(), "the transcript is deleted with the query")
+
+		var messages []openai.ChatCompletionMessageParamUnion
+		Expect(json.Unmarshal([]byte(configMap.Data["model.echo"]), &messages)).To(Succeed())
+		Expect(messages).NotTo(BeEmpty())
+		Expect(messages[len(messages)-1].OfAssistant.Content.OfString.Value).To(Equal("echoed"))
+
+		Expect(query.Status.Responses).To(HaveLen(1))
+		Expect(query.Status.Responses[0].TranscriptRef).To(Equal(&corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "forecast-transcript"},
+			Key:                  "model.echo",
+		}))
+	})
+
+	It("does not overwrite a ConfigMap that holds something else", func() {
+		Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast-transcript", Namespace: "default"},
+			Data:       map[string]string{"settings": "keep me"},
+		})).To(Succeed())
+
+		query := run(newRunningQuery("forecast", echo))
+
+		Expect(query.Status.Phase).To(Equal(statusDone), "a transcript that cannot be saved does not fail the query")
+		Expect(query.Status.Responses).To(ConsistOf(HaveField("TranscriptRef", BeNil())))
+		Expect(transcript("forecast-transcript").Data).To(Equal(map[string]string{"settings": "keep me"}))
+		Eventually(recorder.Events).Should(Receive(ContainSubstring("TranscriptFailed")))
+	})
+})
@@ -0,0 +1,302 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	queryScheduleLabel         = "ark.mckinsey.com/query-schedule"
+	queryScheduledAtAnnotation = "ark.mckinsey.com/scheduled-at"
+
+	concurrencyPolicyForbid  = "Forbid"
+	concurrencyPolicyReplace = "Replace"
+
+	defaultSuccessfulQueriesHistoryLimit = 3
+	defaultFailedQueriesHistoryLimit     = 1
+)
+
+// QueryScheduleReconciler creates queries from a QuerySchedule's template on its cron schedule. The queries
+// are cleaned up by the history limits of the schedule, and by their own TTL in the QueryReconciler.
+type QueryScheduleReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queryschedules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queryschedules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queryschedules/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;create;update;patch;delete
+
+func (r *QueryScheduleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var schedule arkv1alpha1.QuerySchedule
+	if err := r.Get(ctx, req.NamespacedName, &schedule); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("QuerySchedule deleted", "queryschedule", req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch QuerySchedule")
+		return ctrl.Result{}, err
+	}
+
+	active, err := r.reconcileHistory(ctx, &schedule)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	cron, loc, err := parseQuerySchedule(&schedule)
+	if err != nil {
+		schedule.Status.NextScheduleTime = nil
+		return ctrl.Result{}, r.updateStatus(ctx, schedule, statusError, err.Error())
+	}
+
+	if schedule.Spec.Suspend {
+		schedule.Status.NextScheduleTime = nil
+		return ctrl.Result{}, r.updateStatus(ctx, schedule, statusReady, "schedule is suspended")
+	}
+
+	now := time.Now().In(loc)
+	scheduledTime, next := mostRecentScheduleTime(&schedule, cron, now)
+	if next.IsZero() {
+		schedule.Status.NextScheduleTime = nil
+		return ctrl.Result{}, r.updateStatus(ctx, schedule, statusError, fmt.Sprintf("schedule %q never runs", schedule.Spec.Schedule))
+	}
+	schedule.Status.NextScheduleTime = &metav1.Time{Time: next}
+	result := ctrl.Result{RequeueAfter: next.Sub(now)}
+
+	if scheduledTime.IsZero() {
+		return result, r.updateStatus(ctx, schedule, statusReady, "")
+	}
+
+	if len(active) > 0 {
+		switch schedule.Spec.ConcurrencyPolicy {
+		case concurrencyPolicyForbid:
+			log.Info("skipping scheduled query, previous query is still running", "queryschedule", schedule.Name, "active", schedule.Status.Active)
+			r.Recorder.Event(&schedule, "Normal", "QuerySkipped", fmt.Sprintf("Skipped scheduled time %s, previous query is still running", scheduledTime.Format(time.RFC3339)))
+			schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+			return result, r.updateStatus(ctx, schedule, statusReady, "")
+		case concurrencyPolicyReplace:
+			for i := range active {
+				if err := r.Delete(ctx, &active[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+					log.Error(err, "unable to delete active query", "query", active[i].Name)
+					return ctrl.Result{}, err
+				}
+				r.Recorder.Event(&schedule, "Normal", "QueryReplaced", fmt.Sprintf("Deleted active query %s", active[i].Name))
+			}
+			schedule.Status.Active = nil
+		}
+	}
+
+	query, err := r.queryForSchedule(&schedule, scheduledTime)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, query); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error(err, "unable to create scheduled query", "queryschedule", schedule.Name, "query", query.Name)
+		r.Recorder.Event(&schedule, "Warning", "QueryCreationFailed", fmt.Sprintf("Failed to create query %s: %v", query.Name, err))
+		schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+		return result, r.updateStatus(ctx, schedule, statusError, fmt.Sprintf("failed to create query %s: %v", query.Name, err))
+	}
+
+	log.Info("created scheduled query", "queryschedule", schedule.Name, "query", query.Name, "scheduledTime", scheduledTime)
+	r.Recorder.Event(&schedule, "Normal", "QueryCreated", fmt.Sprintf("Created query %s", query.Name))
+	schedule.Status.Active = appendIfMissing(schedule.Status.Active, query.Name)
+	schedule.Status.LastScheduleTime = &metav1.Time{Time: scheduledTime}
+	return result, r.updateStatus(ctx, schedule, statusReady, "")
+}
+
+// reconcileHistory records the schedule's running queries in its status, and deletes finished queries beyond
+// the history limits, oldest first. It returns the running queries.
+func (r *QueryScheduleReconciler) reconcileHistory(ctx context.Context, schedule *arkv1alpha1.QuerySchedule) ([]arkv1alpha1.Query, error) {
+	var queries arkv1alpha1.QueryList
+	if err := r.List(ctx, &queries, client.InNamespace(schedule.Namespace), client.MatchingLabels{queryScheduleLabel: schedule.Name}); err != nil {
+		logf.FromContext(ctx).Error(err, "unable to list scheduled queries", "queryschedule", schedule.Name)
+		return nil, err
+	}
+
+	var active, succeeded, failed []arkv1alpha1.Query
+	for _, query := range queries.Items {
+		if !metav1.IsControlledBy(&query, schedule) {
+			continue
+		}
+		switch query.Status.Phase {
+		case statusDone:
+			succeeded = append(succeeded, query)
+		case statusError, statusCanceled:
+			failed = append(failed, query)
+		default:
+			if query.DeletionTimestamp.IsZero() {
+				active = append(active, query)
+			}
+		}
+	}
+
+	schedule.Status.Active = nil
+	for _, query := range active {
+		schedule.Status.Active = append(schedule.Status.Active, query.Name)
+	}
+	for _, query := range succeeded {
+		if scheduledAt := queryScheduledAt(&query); !scheduledAt.IsZero() &&
+			(schedule.Status.LastSuccessfulTime == nil || scheduledAt.After(schedule.Status.LastSuccessfulTime.Time)) {
+			schedule.Status.LastSuccessfulTime = &metav1.Time{Time: scheduledAt}
+		}
+	}
+
+	successfulLimit := historyLimit(schedule.Spec.SuccessfulQueriesHistoryLimit, defaultSuccessfulQueriesHistoryLimit)
+	if err := r.deleteOldestQueries(ctx, succeeded, successfulLimit); err != nil {
+		return nil, err
+	}
+	failedLimit := historyLimit(schedule.Spec.FailedQueriesHistoryLimit, defaultFailedQueriesHistoryLimit)
+	if err := r.deleteOldestQueries(ctx, failed, failedLimit); err != nil {
+		return nil, err
+	}
+	return active, nil
+}
+
+func (r *QueryScheduleReconciler) deleteOldestQueries(ctx context.Context, queries []arkv1alpha1.Query, keep int) error {
+	if len(queries) <= keep {
+		return nil
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].CreationTimestamp.Before(&queries[j].CreationTimestamp)
+	})
+	for i := range queries[:len(queries)-keep] {
+		if !queries[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Delete(ctx, &queries[i], client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			logf.FromContext(ctx).Error(err, "unable to delete old scheduled query", "query", queries[i].Name)
+			return err
+		}
+	}
+	return nil
+}
+
+// queryForSchedule builds the query for a scheduled time. The name is derived from the scheduled time, so a
+// run that is reconciled twice never creates a second query.
+func (r *QueryScheduleReconciler) queryForSchedule(schedule *arkv1alpha1.QuerySchedule, scheduledTime time.Time) (*arkv1alpha1.Query, error) {
+	template := schedule.Spec.QueryTemplate
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%d", schedule.Name, scheduledTime.Unix()/60),
+			Namespace:   schedule.Namespace,
+			Labels:      make(map[string]string, len(template.Labels)+1),
+			Annotations: make(map[string]string, len(template.Annotations)+1),
+		},
+		Spec: *template.Spec.DeepCopy(),
+	}
+	for key, value := range template.Labels {
+		query.Labels[key] = value
+	}
+	query.Labels[queryScheduleLabel] = schedule.Name
+	for key, value := range template.Annotations {
+		query.Annotations[key] = value
+	}
+	query.Annotations[queryScheduledAtAnnotation] = scheduledTime.UTC().Format(time.RFC3339)
+
+	if err := controllerutil.SetControllerReference(schedule, query, r.Scheme); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+func (r *QueryScheduleReconciler) updateStatus(ctx context.Context, schedule arkv1alpha1.QuerySchedule, phase, message string) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	schedule.Status.Phase = phase
+	schedule.Status.Message = message
+	err := r.Status().Update(ctx, &schedule)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to update QuerySchedule status", "status", phase)
+	}
+	return err
+}
+
+func parseQuerySchedule(schedule *arkv1alpha1.QuerySchedule) (*common.CronSchedule, *time.Location, error) {
+	cron, err := common.ParseCronSchedule(schedule.Spec.Schedule)
+	if err != nil {
+		return nil, nil, err
+	}
+	loc, err := time.LoadLocation(schedule.Spec.TimeZone)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid time zone %q: %w", schedule.Spec.TimeZone, err)
+	}
+	return cron, loc, nil
+}
+
+// mostRecentScheduleTime returns the latest scheduled time that has passed without a query being created for
+// it, or the zero time if there is none, along with the next scheduled time after now. Runs missed while the
+// controller was down are not caught up on, only the most recent one is started, and only if it is within the
+// starting deadline.
+func mostRecentScheduleTime(schedule *arkv1alpha1.QuerySchedule, cron *common.CronSchedule, now time.Time) (time.Time, time.Time) {
+	earliest := schedule.CreationTimestamp.Time
+	if schedule.Status.LastScheduleTime != nil {
+		earliest = schedule.Status.LastScheduleTime.Time
+	}
+	if deadline := schedule.Spec.StartingDeadlineSeconds; deadline != nil {
+		if windowStart := now.Add(-time.Duration(*deadline) * time.Second); windowStart.After(earliest) {
+			earliest = windowStart
+		}
+	}
+
+	var mostRecent time.Time
+	next := cron.Next(earliest.In(now.Location()))
+	for !next.IsZero() && !next.After(now) {
+		mostRecent = next
+		next = cron.Next(next)
+	}
+	return mostRecent, next
+}
+
+func queryScheduledAt(query *arkv1alpha1.Query) time.Time {
+	scheduledAt, err := time.Parse(time.RFC3339, query.Annotations[queryScheduledAtAnnotation])
+	if err != nil {
+		return time.Time{}
+	}
+	return scheduledAt
+}
+
+func historyLimit(limit *int32, defaultLimit int) int {
+	if limit == nil {
+		return defaultLimit
+	}
+	return int(*limit)
+}
+
+func appendIfMissing(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *QueryScheduleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.QuerySchedule{}).
+		Owns(&arkv1alpha1.Query{}).
+		Named("queryschedule").
+		Complete(r)
+}
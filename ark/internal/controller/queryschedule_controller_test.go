@@ -0,0 +1,170 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("QuerySchedule Controller", func() {
+	var (
+		ctx        context.Context
+		schedule   *arkv1alpha1.QuerySchedule
+		recorder   *record.FakeRecorder
+		fakeClient client.Client
+	)
+
+	// scheduledQuery returns a query the schedule created age ago, in the given phase
+	scheduledQuery := func(name, phase string, age time.Duration) *arkv1alpha1.Query {
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         schedule.Namespace,
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+				Labels:            map[string]string{queryScheduleLabel: schedule.Name},
+			},
+			Spec:   schedule.Spec.QueryTemplate.Spec,
+			Status: arkv1alpha1.QueryStatus{Phase: phase},
+		}
+		Expect(controllerutil.SetControllerReference(schedule, query, fakeClientScheme())).To(Succeed())
+		return query
+	}
+
+	reconcileSchedule := func(objects ...client.Object) *arkv1alpha1.QuerySchedule {
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.QuerySchedule{}, &arkv1alpha1.Query{}).
+			WithObjects(append(objects, schedule)...).
+			Build()
+		reconciler := &QueryScheduleReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: recorder}
+
+		key := types.NamespacedName{Name: schedule.Name, Namespace: schedule.Namespace}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var result arkv1alpha1.QuerySchedule
+		Expect(fakeClient.Get(ctx, key, &result)).To(Succeed())
+		return &result
+	}
+
+	queryNames := func() []string {
+		var queries arkv1alpha1.QueryList
+		Expect(fakeClient.List(ctx, &queries, client.InNamespace(schedule.Namespace))).To(Succeed())
+		var names []string
+		for _, query := range queries.Items {
+			names = append(names, query.Name)
+		}
+		return names
+	}
+
+	// dueQuery matches the name of a query created for a scheduled time rather than one made up by a test
+	dueQuery := MatchRegexp(`^report-\d+$`)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(100)
+		schedule = &arkv1alpha1.QuerySchedule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "report",
+				Namespace:         "default",
+				UID:               types.UID("uid-report"),
+				CreationTimestamp: metav1.NewTime(time.Now().Add(-150 * time.Second)),
+			},
+			Spec: arkv1alpha1.QueryScheduleSpec{
+				Schedule: "* * * * *",
+				QueryTemplate: arkv1alpha1.QueryTemplate{Spec: arkv1alpha1.QuerySpec{
+					Input:   "summarise the day",
+					Targets: []arkv1alpha1.QueryTarget{{Type: "agent", Name: "reporter"}},
+				}},
+			},
+		}
+	})
+
+	Context("when a run is due while an earlier query is still running", func() {
+		It("starts another query with the Allow policy", func() {
+			result := reconcileSchedule(scheduledQuery("report-earlier", statusRunning, time.Minute))
+
+			Expect(queryNames()).To(ConsistOf("report-earlier", dueQuery))
+			Expect(result.Status.Active).To(ConsistOf("report-earlier", dueQuery))
+		})
+
+		It("skips the run with the Forbid policy", func() {
+			schedule.Spec.ConcurrencyPolicy = concurrencyPolicyForbid
+
+			result := reconcileSchedule(scheduledQuery("report-earlier", statusRunning, time.Minute))
+
+			Expect(queryNames()).To(ConsistOf("report-earlier"))
+			Expect(result.Status.Active).To(ConsistOf("report-earlier"))
+			Expect(result.Status.LastScheduleTime).NotTo(BeNil(), "the skipped run is not tried again")
+			Expect(recorder.Events).To(Receive(ContainSubstring("QuerySkipped")))
+		})
+
+		It("deletes the running query and starts a new one with the Replace policy", func() {
+			schedule.Spec.ConcurrencyPolicy = concurrencyPolicyReplace
+
+			result := reconcileSchedule(scheduledQuery("report-earlier", statusRunning, time.Minute))
+
+			Expect(queryNames()).To(ConsistOf(dueQuery))
+			Expect(result.Status.Active).To(ConsistOf(dueQuery))
+			Expect(recorder.Events).To(Receive(ContainSubstring("QueryReplaced")))
+		})
+	})
+
+	Context("when finished queries exceed the history limits", func() {
+		BeforeEach(func() {
+			schedule.Spec.Suspend = true
+		})
+
+		It("keeps the most recent queries up to the default limits", func() {
+			reconcileSchedule(
+				scheduledQuery("done-1", statusDone, 5*time.Minute),
+				scheduledQuery("done-2", statusDone, 4*time.Minute),
+				scheduledQuery("done-3", statusDone, 3*time.Minute),
+				scheduledQuery("done-4", statusDone, 2*time.Minute),
+				scheduledQuery("failed-1", statusError, 5*time.Minute),
+				scheduledQuery("canceled-1", statusCanceled, 4*time.Minute),
+				scheduledQuery("running-1", statusRunning, 6*time.Minute),
+			)
+
+			Expect(queryNames()).To(ConsistOf("done-2", "done-3", "done-4", "canceled-1", "running-1"))
+		})
+
+		It("applies the limits of the schedule, including zero", func() {
+			successfulLimit, failedLimit := int32(1), int32(0)
+			schedule.Spec.SuccessfulQueriesHistoryLimit = &successfulLimit
+			schedule.Spec.FailedQueriesHistoryLimit = &failedLimit
+
+			reconcileSchedule(
+				scheduledQuery("done-1", statusDone, 3*time.Minute),
+				scheduledQuery("done-2", statusDone, 2*time.Minute),
+				scheduledQuery("failed-1", statusError, time.Minute),
+			)
+
+			Expect(queryNames()).To(ConsistOf("done-2"))
+		})
+
+		It("leaves queries the schedule does not own", func() {
+			failedLimit := int32(0)
+			schedule.Spec.FailedQueriesHistoryLimit = &failedLimit
+			foreign := scheduledQuery("failed-elsewhere", statusError, time.Minute)
+			foreign.OwnerReferences = nil
+
+			reconcileSchedule(foreign, scheduledQuery("failed-1", statusError, time.Minute))
+
+			Expect(queryNames()).To(ConsistOf("failed-elsewhere"))
+		})
+	})
+})
@@ -0,0 +1,108 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const (
+	statusExceeded = "exceeded"
+
+	// tokenBudgetRefreshInterval is how often the usage reported in a TokenBudget's status is recomputed
+	tokenBudgetRefreshInterval = time.Minute
+)
+
+// TokenBudgetReconciler reports the usage of a TokenBudget. The budget itself is enforced by the query webhook
+// when queries are created and by the query controller while they run.
+type TokenBudgetReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tokenbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tokenbudgets/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tokenbudgets/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch
+
+func (r *TokenBudgetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var budget arkv1alpha1.TokenBudget
+	if err := r.Get(ctx, req.NamespacedName, &budget); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("TokenBudget deleted", "tokenbudget", req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch TokenBudget")
+		return ctrl.Result{}, err
+	}
+
+	if err := genai.ValidateTokenBudget(&budget); err != nil {
+		return ctrl.Result{}, r.updateStatus(ctx, budget, arkv1alpha1.TokenBudgetStatus{Phase: statusError, Message: err.Error(), Consumers: budget.Status.Consumers})
+	}
+
+	now := time.Now()
+	usage, err := genai.TokenBudgetUsage(ctx, r.Client, &budget, now)
+	if err != nil {
+		log.Error(err, "failed to compute token budget usage", "tokenbudget", budget.Name)
+		return ctrl.Result{}, err
+	}
+
+	status := arkv1alpha1.TokenBudgetStatus{
+		Phase:      statusReady,
+		UsedTokens: usage.TotalTokens,
+		UsedCost:   genai.FormatCost(usage.Cost),
+		// Consumers that left the window are dropped, the others are kept as the queries recorded them
+		Consumers: genai.ActiveTokenBudgetConsumers(&budget, now),
+	}
+	if err := genai.CheckTokenBudget(&budget, usage); err != nil {
+		status.Phase, status.Message = statusExceeded, err.Error()
+	}
+	if err := r.updateStatus(ctx, budget, status); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Usage drops out of the rolling window over time, so the status is refreshed periodically
+	return ctrl.Result{RequeueAfter: tokenBudgetRefreshInterval}, nil
+}
+
+// updateStatus writes the status only when it changed, since every write triggers another reconcile
+func (r *TokenBudgetReconciler) updateStatus(ctx context.Context, budget arkv1alpha1.TokenBudget, status arkv1alpha1.TokenBudgetStatus) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	status.LastUpdated = budget.Status.LastUpdated
+	if equality.Semantic.DeepEqual(status, budget.Status) {
+		return nil
+	}
+	status.LastUpdated = &metav1.Time{Time: time.Now()}
+	budget.Status = status
+	err := r.Status().Update(ctx, &budget)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to update TokenBudget status", "status", status.Phase)
+	}
+	return err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *TokenBudgetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.TokenBudget{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("tokenbudget").
+		Complete(r)
+}
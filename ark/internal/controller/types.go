@@ -3,13 +3,14 @@
 package controller
 
 const (
-	statusPending    = "pending"
-	statusRunning    = "running"
-	statusEvaluating = "evaluating"
-	statusDone       = "done"
-	statusError      = "error"
-	statusCanceled   = "canceled"
-	statusReady      = "ready"
+	statusPending          = "pending"
+	statusRunning          = "running"
+	statusAwaitingApproval = "awaiting-approval"
+	statusEvaluating       = "evaluating"
+	statusDone             = "done"
+	statusError            = "error"
+	statusCanceled         = "canceled"
+	statusReady            = "ready"
 
 	finalizer = "ark.mckinsey.com/finalizer"
 )
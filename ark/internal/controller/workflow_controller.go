@@ -0,0 +1,418 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const statusSkipped = "skipped"
+
+// workflowRetryBackoff is the delay before a failed step is retried, multiplied by the attempt number
+var workflowRetryBackoff = 5 * time.Second
+
+// WorkflowReconciler runs the steps of a Workflow as soon as the steps they depend on are done. Each step runs
+// its target through the same execution path as a query target. Step results are written to the status as they
+// complete, so a restarted controller only runs the steps that had not finished.
+type WorkflowReconciler struct {
+	client.Client
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	operations sync.Map
+}
+
+type workflowStepResult struct {
+	step     arkv1alpha1.WorkflowStep
+	result   targetResult
+	attempts int32
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=workflows,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=workflows/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=workflows/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents,verbs=get;list
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=teams,verbs=get;list
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tools,verbs=get;list
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;list;watch;patch
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,resourceNames=default,verbs=impersonate
+
+func (r *WorkflowReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var workflow arkv1alpha1.Workflow
+	if err := r.Get(ctx, req.NamespacedName, &workflow); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Workflow deleted", "workflow", req.Name)
+			r.cancelOperation(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch Workflow")
+		return ctrl.Result{}, err
+	}
+
+	var expiry time.Time
+	if workflow.Spec.TTL != nil {
+		expiry = workflow.CreationTimestamp.Add(workflow.Spec.TTL.Duration)
+		if time.Now().After(expiry) {
+			// TTL expired: delete the object
+			r.cancelOperation(req.NamespacedName)
+			if err := r.Delete(ctx, &workflow); client.IgnoreNotFound(err) != nil {
+				log.Error(err, "unable to delete object")
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{}, nil
+		}
+	}
+
+	switch workflow.Status.Phase {
+	case statusDone, statusError:
+		if expiry.IsZero() {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{RequeueAfter: time.Until(expiry)}, nil
+	}
+
+	if _, exists := r.operations.Load(req.NamespacedName); exists {
+		return ctrl.Result{}, nil
+	}
+
+	if err := genai.ValidateWorkflowSteps(workflow.Spec.Steps); err != nil {
+		workflow.Status.Message = err.Error()
+		return ctrl.Result{}, r.updateStatus(ctx, &workflow, statusError)
+	}
+
+	resetWorkflowSteps(&workflow)
+	workflow.Status.Message = ""
+	if err := r.updateStatus(ctx, &workflow, statusRunning); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	opCtx, cancel := context.WithCancel(ctx)
+	r.operations.Store(req.NamespacedName, cancel)
+	go r.executeWorkflow(opCtx, workflow, req.NamespacedName)
+	return ctrl.Result{}, nil
+}
+
+// resetWorkflowSteps adds a pending status for every step that has none, and sets steps that were running when
+// the controller stopped back to pending so they run again
+func resetWorkflowSteps(workflow *arkv1alpha1.Workflow) {
+	existing := make(map[string]arkv1alpha1.WorkflowStepStatus, len(workflow.Status.Steps))
+	for _, status := range workflow.Status.Steps {
+		existing[status.Name] = status
+	}
+
+	steps := make([]arkv1alpha1.WorkflowStepStatus, 0, len(workflow.Spec.Steps))
+	for _, step := range workflow.Spec.Steps {
+		status, ok := existing[step.Name]
+		if !ok || status.Phase == statusRunning {
+			status = arkv1alpha1.WorkflowStepStatus{Name: step.Name, Phase: statusPending, Attempts: status.Attempts}
+		}
+		steps = append(steps, status)
+	}
+	workflow.Status.Steps = steps
+}
+
+func (r *WorkflowReconciler) executeWorkflow(ctx context.Context, workflow arkv1alpha1.Workflow, namespacedName types.NamespacedName) {
+	log := logf.FromContext(ctx)
+
+	defer func() {
+		if r := recover(); r != nil {
+			log.Error(fmt.Errorf("workflow execution goroutine panic: %v", r), "Workflow execution goroutine panicked")
+		}
+		r.operations.Delete(namespacedName)
+	}()
+
+	if workflow.Spec.Timeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, workflow.Spec.Timeout.Duration)
+		defer cancel()
+	}
+
+	targets := r.targetExecutor()
+	workflowQuery := workflowStepQuery(workflow, arkv1alpha1.WorkflowStep{}, "")
+	impersonatedClient, err := targets.getClientForQuery(workflowQuery)
+	if err != nil {
+		workflow.Status.Message = err.Error()
+		_ = r.updateStatus(ctx, &workflow, statusError)
+		return
+	}
+
+	// Steps count towards the TokenBudgets of the namespace like query targets, and a budget that runs out aborts
+	// the workflow. Steps that finished before a restart were recorded in the budgets as they ran.
+	ctx, abort := context.WithCancelCause(ctx)
+	defer abort(nil)
+	budgets, err := loadQueryBudgetGuard(ctx, r.Client, workflowQuery, budgetConsumer("Workflow", &workflow), abort)
+	if err != nil {
+		workflow.Status.Message = err.Error()
+		_ = r.updateStatus(ctx, &workflow, statusError)
+		return
+	}
+
+	// Steps emit their events on a copy, as the status of workflow keeps changing while they run
+	recorder := genai.NewWorkflowRecorder(workflow.DeepCopy(), r.Recorder)
+	memory := genai.NewNoopMemory()
+	// Tool calls that need approval are denied, as nobody can approve them from a workflow
+	ctx = genai.WithToolApprover(ctx, workflowToolApprover{})
+
+	statuses := make(map[string]*arkv1alpha1.WorkflowStepStatus, len(workflow.Status.Steps))
+	for i := range workflow.Status.Steps {
+		statuses[workflow.Status.Steps[i].Name] = &workflow.Status.Steps[i]
+	}
+
+	results := make(chan workflowStepResult)
+	running := 0
+	for {
+		// A step that is skipped or fails to start settles the steps that depend on it, which may come earlier in
+		// the spec, so the steps are scanned until none changes
+		for settled := false; !settled; {
+			settled = true
+			for _, step := range workflow.Spec.Steps {
+				status := statuses[step.Name]
+				if status.Phase != statusPending || ctx.Err() != nil {
+					continue
+				}
+				ready, skip := workflowStepReady(step, statuses)
+				if skip {
+					status.Phase = statusSkipped
+					settled = false
+					continue
+				}
+				if !ready {
+					continue
+				}
+
+				input, err := genai.ResolveWorkflowStepInput(ctx, impersonatedClient, &workflow, step, workflowOutputs(statuses))
+				if err != nil {
+					status.Phase = statusError
+					status.Error = err.Error()
+					settled = false
+					continue
+				}
+
+				status.Phase = statusRunning
+				status.StartTime = &metav1.Time{Time: time.Now()}
+				running++
+				go func(query arkv1alpha1.Query, step arkv1alpha1.WorkflowStep, previousAttempts int32) {
+					results <- r.executeStep(ctx, targets, query, step, impersonatedClient, memory, budgets, recorder, previousAttempts)
+				}(workflowStepQuery(workflow, step, input), step, status.Attempts)
+			}
+		}
+
+		if err := r.updateStatus(ctx, &workflow, statusRunning); err != nil && ctx.Err() == nil {
+			log.Error(err, "failed to record workflow progress", "workflow", workflow.Name)
+		}
+		if running == 0 {
+			break
+		}
+
+		completed := <-results
+		running--
+		status := statuses[completed.step.Name]
+		response := makeTargetResponse(completed.result)
+		status.Phase = response.Phase
+		status.Attempts = completed.attempts
+		status.Content = response.Content
+		status.Error = response.Error
+		status.TokenUsage = response.TokenUsage
+		status.Cost = response.Cost
+		status.CompletionTime = &metav1.Time{Time: time.Now()}
+		if status.Phase == statusError {
+			r.Recorder.Event(&workflow, "Warning", "StepFailed", fmt.Sprintf("step %s failed: %s", completed.step.Name, status.Error))
+		}
+	}
+
+	r.completeWorkflow(ctx, &workflow, statuses)
+}
+
+// workflowStepReady reports whether all dependencies of a step are done, and whether the step has to be skipped
+// because one of them did not succeed
+func workflowStepReady(step arkv1alpha1.WorkflowStep, statuses map[string]*arkv1alpha1.WorkflowStepStatus) (bool, bool) {
+	ready := true
+	for _, dependency := range step.DependsOn {
+		switch statuses[dependency].Phase {
+		case statusDone:
+		case statusError, statusSkipped:
+			return false, true
+		default:
+			ready = false
+		}
+	}
+	return ready, false
+}
+
+func workflowOutputs(statuses map[string]*arkv1alpha1.WorkflowStepStatus) map[string]string {
+	outputs := make(map[string]string, len(statuses))
+	for name, status := range statuses {
+		if status.Phase == statusDone {
+			outputs[name] = status.Content
+		}
+	}
+	return outputs
+}
+
+// executeStep runs the target of a step, retrying failed attempts up to the step's retry limit
+func (r *WorkflowReconciler) executeStep(ctx context.Context, targets *QueryReconciler, query arkv1alpha1.Query, step arkv1alpha1.WorkflowStep, impersonatedClient client.Client, memory genai.MemoryInterface, budgets *queryBudgetGuard, recorder genai.EventEmitter, previousAttempts int32) workflowStepResult {
+	tokenCollector := genai.NewTokenUsageCollector(budgets.forTarget(step.Target, recorder))
+	attempts := previousAttempts
+
+	var result targetResult
+	for retry := int32(0); retry <= step.Retries; retry++ {
+		if retry > 0 {
+			select {
+			case <-ctx.Done():
+				return workflowStepResult{step: step, result: result, attempts: attempts}
+			case <-time.After(time.Duration(retry) * workflowRetryBackoff):
+			}
+		}
+
+		if err := budgets.check(ctx, step.Target); err != nil {
+			result = targetResult{err: err, target: step.Target, tokenUsage: tokenCollector.GetTokenSummary()}
+			break
+		}
+
+		attempts++
+		start := time.Now()
+		messages, err := targets.executeTarget(ctx, query, step.Target, impersonatedClient, memory, tokenCollector, nil)
+		if cause := context.Cause(ctx); err != nil && genai.IsTokenBudgetExceeded(cause) {
+			err = cause
+		}
+		result = targetResult{
+			messages:   messages,
+			err:        err,
+			target:     step.Target,
+			tokenUsage: tokenCollector.GetTokenSummary(),
+			duration:   time.Since(start),
+		}
+		if !isTargetFailure(err) || ctx.Err() != nil {
+			break
+		}
+		logf.FromContext(ctx).Info("workflow step failed", "query", query.Name, "step", step.Name, "attempt", attempts, "error", err.Error())
+	}
+	return workflowStepResult{step: step, result: result, attempts: attempts}
+}
+
+func (r *WorkflowReconciler) completeWorkflow(ctx context.Context, workflow *arkv1alpha1.Workflow, statuses map[string]*arkv1alpha1.WorkflowStepStatus) {
+	var usage genai.TokenUsage
+	var failed []string
+	for _, status := range workflow.Status.Steps {
+		usage.Add(genai.FromStatusTokenUsage(status.TokenUsage, status.Cost))
+		if status.Phase != statusDone {
+			failed = append(failed, fmt.Sprintf("%s %s", status.Name, status.Phase))
+		}
+	}
+	workflow.Status.TokenUsage = toTokenUsage(usage)
+	workflow.Status.Cost = genai.FormatCost(usage.Cost)
+	workflow.Status.Duration = &metav1.Duration{Duration: time.Since(workflow.CreationTimestamp.Time)}
+
+	var outputs []string
+	for _, name := range genai.WorkflowOutputSteps(workflow.Spec.Steps) {
+		if status := statuses[name]; status.Phase == statusDone {
+			outputs = append(outputs, status.Content)
+		}
+	}
+	workflow.Status.Output = strings.Join(outputs, "\n\n")
+
+	phase := statusDone
+	switch cause := context.Cause(ctx); {
+	case genai.IsTokenBudgetExceeded(cause):
+		phase = statusError
+		workflow.Status.Message = cause.Error()
+		r.Recorder.Event(workflow, "Warning", "TokenBudgetExceeded", cause.Error())
+		ctx = context.WithoutCancel(ctx)
+	case ctx.Err() == context.Canceled:
+		// The workflow was deleted or the controller is stopping, in which case the next controller resumes it
+		return
+	case ctx.Err() == context.DeadlineExceeded:
+		phase = statusError
+		workflow.Status.Message = "workflow timed out"
+		// The operation context is done, the final status is written with a fresh one
+		ctx = context.WithoutCancel(ctx)
+	case len(failed) > 0:
+		phase = statusError
+		workflow.Status.Message = fmt.Sprintf("%d of %d steps did not succeed: %s", len(failed), len(workflow.Spec.Steps), strings.Join(failed, ", "))
+	}
+	_ = r.updateStatus(ctx, workflow, phase)
+}
+
+// workflowStepQuery describes a step as a query, so it can run through the query controller's target execution
+func workflowStepQuery(workflow arkv1alpha1.Workflow, step arkv1alpha1.WorkflowStep, input string) arkv1alpha1.Query {
+	return arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              fmt.Sprintf("%s-%s", workflow.Name, step.Name),
+			Namespace:         workflow.Namespace,
+			UID:               workflow.UID,
+			CreationTimestamp: workflow.CreationTimestamp,
+		},
+		Spec: arkv1alpha1.QuerySpec{
+			Input:          input,
+			Targets:        []arkv1alpha1.QueryTarget{step.Target},
+			ServiceAccount: workflow.Spec.ServiceAccount,
+			Timeout:        step.Timeout,
+		},
+	}
+}
+
+func (r *WorkflowReconciler) targetExecutor() *QueryReconciler {
+	return &QueryReconciler{Client: r.Client, Scheme: r.Scheme, Recorder: r.Recorder}
+}
+
+func (r *WorkflowReconciler) cancelOperation(namespacedName types.NamespacedName) {
+	if cancel, exists := r.operations.LoadAndDelete(namespacedName); exists {
+		cancel.(context.CancelFunc)()
+	}
+}
+
+// updateStatus writes the status, retrying once on a conflict since the spec or metadata may have changed while
+// the workflow was running. The update is made on a copy, so the step statuses of workflow stay in place.
+func (r *WorkflowReconciler) updateStatus(ctx context.Context, workflow *arkv1alpha1.Workflow, phase string) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	workflow.Status.Phase = phase
+	update := workflow.DeepCopy()
+	err := r.Status().Update(ctx, update)
+	if errors.IsConflict(err) {
+		if err = r.Get(ctx, client.ObjectKeyFromObject(workflow), update); err == nil {
+			update.Status = *workflow.Status.DeepCopy()
+			err = r.Status().Update(ctx, update)
+		}
+	}
+	if err == nil {
+		workflow.ResourceVersion = update.ResourceVersion
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to update workflow status", "status", phase)
+	}
+	return err
+}
+
+type workflowToolApprover struct{}
+
+func (workflowToolApprover) RequestApproval(ctx context.Context, request genai.ToolApprovalRequest) (genai.ToolApprovalDecision, error) {
+	return genai.ToolApprovalDecision{Approved: false, Reason: "tools that need approval cannot run in a workflow"}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *WorkflowReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.Workflow{}).
+		Named("workflow").
+		Complete(r)
+}
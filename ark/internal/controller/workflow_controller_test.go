@@ -0,0 +1,157 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Workflow Controller", func() {
+	var (
+		ctx        context.Context
+		server     *fakeModelServer
+		fakeClient client.Client
+		reconciler *WorkflowReconciler
+	)
+
+	modelStep := func(name, model string, dependsOn ...string) arkv1alpha1.WorkflowStep {
+		return arkv1alpha1.WorkflowStep{
+			Name:      name,
+			Target:    arkv1alpha1.QueryTarget{Type: "model", Name: model},
+			DependsOn: dependsOn,
+		}
+	}
+
+	// run executes the workflow to the end, as the goroutine started by Reconcile would
+	run := func(workflow *arkv1alpha1.Workflow) *arkv1alpha1.Workflow {
+		status := workflow.Status
+		Expect(fakeClient.Create(ctx, workflow)).To(Succeed())
+		workflow.Status = status
+		Expect(fakeClient.Status().Update(ctx, workflow)).To(Succeed())
+		key := types.NamespacedName{Name: workflow.Name, Namespace: workflow.Namespace}
+		Expect(fakeClient.Get(ctx, key, workflow)).To(Succeed())
+		resetWorkflowSteps(workflow)
+		reconciler.executeWorkflow(ctx, *workflow, key)
+
+		var result arkv1alpha1.Workflow
+		Expect(fakeClient.Get(ctx, key, &result)).To(Succeed())
+		return &result
+	}
+
+	stepStatus := func(workflow *arkv1alpha1.Workflow, name string) arkv1alpha1.WorkflowStepStatus {
+		for _, status := range workflow.Status.Steps {
+			if status.Name == name {
+				return status
+			}
+		}
+		Fail("no status for step " + name)
+		return arkv1alpha1.WorkflowStepStatus{}
+	}
+
+	newWorkflow := func(name string, steps ...arkv1alpha1.WorkflowStep) *arkv1alpha1.Workflow {
+		return &arkv1alpha1.Workflow{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       arkv1alpha1.WorkflowSpec{Input: "plan a launch", Steps: steps},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		Expect(os.Setenv("SKIP_IMPERSONATION", "true")).To(Succeed())
+		DeferCleanup(os.Unsetenv, "SKIP_IMPERSONATION")
+		backoff := workflowRetryBackoff
+		workflowRetryBackoff = 10 * time.Millisecond
+		DeferCleanup(func() { workflowRetryBackoff = backoff })
+
+		server = newFakeModelServer(map[string]fakeModelReply{
+			"echo":  replyContent("echoed"),
+			"fail":  replyError("invalid request"),
+			"hang":  replyHang(),
+			"flaky": replySequence(replyError("invalid request"), replyContent("recovered")),
+		})
+		DeferCleanup(server.Close)
+
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(fakeClientScheme()).
+			WithStatusSubresource(&arkv1alpha1.Workflow{}).
+			WithObjects(
+				server.model("echo", "default"),
+				server.model("fail", "default"),
+				server.model("hang", "default"),
+				server.model("flaky", "default"),
+			).
+			Build()
+		reconciler = &WorkflowReconciler{Client: fakeClient, Scheme: fakeClient.Scheme(), Recorder: record.NewFakeRecorder(100)}
+	})
+
+	It("retries a failed step up to its retry limit", func() {
+		step := modelStep("draft", "flaky")
+		step.Retries = 2
+
+		workflow := run(newWorkflow("retry", step))
+
+		Expect(workflow.Status.Phase).To(Equal(statusDone))
+		status := stepStatus(workflow, "draft")
+		Expect(status.Phase).To(Equal(statusDone))
+		Expect(status.Attempts).To(Equal(int32(2)))
+		Expect(status.Content).To(Equal("recovered"))
+		Expect(workflow.Status.Output).To(Equal("recovered"))
+	})
+
+	It("fails a step whose attempt runs past its timeout", func() {
+		step := modelStep("draft", "hang")
+		step.Timeout = &metav1.Duration{Duration: 50 * time.Millisecond}
+
+		workflow := run(newWorkflow("timeout", step))
+
+		Expect(workflow.Status.Phase).To(Equal(statusError))
+		status := stepStatus(workflow, "draft")
+		Expect(status.Phase).To(Equal(statusError))
+		Expect(status.Error).To(ContainSubstring("timed out"))
+	})
+
+	It("skips every step that depends on a failed step, wherever it is in the spec", func() {
+		workflow := run(newWorkflow("skip",
+			modelStep("publish", "echo", "review"),
+			modelStep("review", "echo", "draft"),
+			modelStep("draft", "fail"),
+		))
+
+		Expect(workflow.Status.Phase).To(Equal(statusError))
+		Expect(stepStatus(workflow, "draft").Phase).To(Equal(statusError))
+		Expect(stepStatus(workflow, "review").Phase).To(Equal(statusSkipped))
+		Expect(stepStatus(workflow, "publish").Phase).To(Equal(statusSkipped))
+		Expect(server.calls("echo")).To(BeZero())
+		Expect(workflow.Status.Message).To(ContainSubstring("3 of 3 steps did not succeed"))
+	})
+
+	It("resumes a restarted workflow from the steps that had not finished", func() {
+		workflow := newWorkflow("resume", modelStep("draft", "fail"), modelStep("review", "echo", "draft"))
+		workflow.Status.Steps = []arkv1alpha1.WorkflowStepStatus{
+			{Name: "draft", Phase: statusDone, Attempts: 1, Content: "the draft", TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 15}},
+			{Name: "review", Phase: statusRunning, Attempts: 1},
+		}
+
+		workflow = run(workflow)
+
+		Expect(workflow.Status.Phase).To(Equal(statusDone))
+		Expect(server.calls("fail")).To(BeZero(), "the finished step is not run again")
+		Expect(server.lastInput("echo")).To(ContainSubstring("the draft"))
+		review := stepStatus(workflow, "review")
+		Expect(review.Phase).To(Equal(statusDone))
+		Expect(review.Attempts).To(Equal(int32(2)))
+		Expect(workflow.Status.TokenUsage.TotalTokens).To(Equal(int64(30)))
+	})
+})
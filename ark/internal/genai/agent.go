@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
@@ -27,6 +28,9 @@ type Agent struct {
 	ExecutionEngine *arkv1alpha1.ExecutionEngineRef
 	Annotations     map[string]string
 	OutputSchema    *runtime.RawExtension
+	ToolExecution   *arkv1alpha1.ToolExecution
+	Limits          *arkv1alpha1.AgentLimits
+	ContextWindow   *arkv1alpha1.ContextWindow
 	client          client.Client
 }
 
@@ -37,6 +41,17 @@ func (a *Agent) FullName() string {
 
 // Execute executes the agent with optional event emission for tool calls
 func (a *Agent) Execute(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	return a.execute(ctx, userInput, history, nil)
+}
+
+// Resume continues an execution that was interrupted after producing progress, the messages checkpointed for
+// userInput. Tool calls left unanswered in progress are run again, so callers must only resume when that is safe.
+// Agents using an execution engine do not checkpoint and start over.
+func (a *Agent) Resume(ctx context.Context, userInput Message, history, progress []Message) ([]Message, error) {
+	return a.execute(ctx, userInput, history, progress)
+}
+
+func (a *Agent) execute(ctx context.Context, userInput Message, history, progress []Message) ([]Message, error) {
 	if a.Model == nil {
 		return nil, fmt.Errorf("agent %s has no model configured", a.FullName())
 	}
@@ -53,9 +68,9 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 		"agentName": a.FullName(),
 		"namespace": a.Namespace,
 	})
-	defer agentTracker.Complete("")
 
 	if a.ExecutionEngine != nil {
+		defer agentTracker.Complete("")
 		// Check if this is the reserved 'a2a' execution engine
 		if a.ExecutionEngine.Name == "a2a" {
 			return a.executeWithA2AExecutionEngine(ctx, userInput)
@@ -63,7 +78,13 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 		return a.executeWithExecutionEngine(ctx, userInput, history)
 	}
 
-	return a.executeLocally(ctx, userInput, history)
+	messages, err := a.executeLocally(ctx, userInput, history, progress)
+	if IsExecutionLimitReached(err) {
+		agentTracker.CompleteWithTerminationReason(TerminationReason(err), err.Error())
+	} else {
+		agentTracker.Complete("")
+	}
+	return messages, err
 }
 
 func (a *Agent) executeWithExecutionEngine(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
@@ -113,18 +134,17 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 	// Truncate schema name to 64 chars for OpenAI API compatibility - name is purely an identifier
 	a.Model.SchemaName = fmt.Sprintf("%.64s", fmt.Sprintf("namespace-%s-agent-%s", a.Namespace, a.Name))
 
-	response, err := a.Model.ChatCompletion(ctx, agentMessages, tools)
+	onFailover := func(from, to *Model, err error) {
+		NewExecutionRecorder(a.Recorder).ModelFailover(ctx, a.FullName(), from.Name, to.Name, err)
+	}
+	response, usedModel, err := a.Model.ChatCompletionWithFailover(ctx, agentMessages, tools, NewStreamChunkHandler(ctx, a.Name), onFailover)
 	if err != nil {
 		llmTracker.Fail(err)
 		return nil, fmt.Errorf("agent %s execution failed: %w", a.FullName(), err)
 	}
 
-	tokenUsage := TokenUsage{
-		PromptTokens:     response.Usage.PromptTokens,
-		CompletionTokens: response.Usage.CompletionTokens,
-		TotalTokens:      response.Usage.TotalTokens,
-	}
-	llmTracker.CompleteWithTokens("", tokenUsage)
+	// The model that answered may be a fallback, with its own pricing
+	llmTracker.CompleteWithModel("", usedModel.TokenUsage(response.Usage), usedModel.Name)
 
 	if len(response.Choices) == 0 {
 		return nil, fmt.Errorf("agent %s received empty response", a.FullName())
@@ -160,18 +180,38 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 		"toolType":   a.Tools.GetToolType(toolCall.Function.Name),
 	})
 
+	if a.Tools.RequiresApproval(toolCall.Function.Name) {
+		decision, err := a.requestToolApproval(ctx, toolCall)
+		if err != nil {
+			toolTracker.Fail(err)
+			return ToolMessage(err.Error(), toolCall.ID), err
+		}
+		if !decision.Approved {
+			toolTracker.CompleteWithMetadata("", map[string]string{"denied": "true", "reason": decision.Reason})
+			return deniedToolMessage(toolCall, decision.Reason), nil
+		}
+	}
+
+	a.recordToolCallStarted(ctx, toolCall)
+
+	publishToolCallProgress(ctx, a.Name, toolCall, ToolCallPhaseRunning)
+
 	result, err := a.Tools.ExecuteTool(ctx, ToolCall(toolCall))
 	toolMessage := ToolMessage(result.Content, result.ID)
 
 	if err != nil {
-		if IsTerminateTeam(err) {
+		if IsTerminateTeam(err) || IsTeamHandoff(err) {
 			toolTracker.CompleteWithTermination(err.Error())
+			publishToolCallProgress(ctx, a.Name, toolCall, ToolCallPhaseCompleted)
 		} else {
 			toolTracker.Fail(err)
+			publishToolCallProgress(ctx, a.Name, toolCall, ToolCallPhaseError)
 		}
 		return toolMessage, err
 	}
 
+	publishToolCallProgress(ctx, a.Name, toolCall, ToolCallPhaseCompleted)
+
 	toolTracker.CompleteWithMetadata(result.Content, map[string]string{
 		"resultLength": fmt.Sprintf("%d", len(result.Content)),
 		"hasError":     "false",
@@ -180,25 +220,104 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 	return toolMessage, nil
 }
 
+type toolCallResult struct {
+	message Message
+	err     error
+}
+
+// executeToolCalls runs the tool calls of one model turn. Calls are grouped into batches that run
+// concurrently, and tool messages are appended in the order the model issued the calls.
 func (a *Agent) executeToolCalls(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, agentMessages, newMessages *[]Message) error {
-	for _, tc := range toolCalls {
+	results := make([]toolCallResult, len(toolCalls))
+
+	for _, batch := range a.planToolCallBatches(toolCalls) {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
-		toolMessage, err := a.executeToolCall(ctx, tc)
-		*agentMessages = append(*agentMessages, toolMessage)
-		*newMessages = append(*newMessages, toolMessage)
+		a.executeToolCallBatch(ctx, toolCalls, batch, results)
 
-		if err != nil {
-			return err
+		for _, i := range batch {
+			*agentMessages = append(*agentMessages, results[i].message)
+			*newMessages = append(*newMessages, results[i].message)
+
+			if results[i].err != nil {
+				return results[i].err
+			}
 		}
 	}
 	return nil
 }
 
+func (a *Agent) executeToolCallBatch(ctx context.Context, toolCalls []openai.ChatCompletionMessageToolCall, batch []int, results []toolCallResult) {
+	if len(batch) == 1 {
+		message, err := a.executeToolCall(ctx, toolCalls[batch[0]])
+		results[batch[0]] = toolCallResult{message, err}
+		return
+	}
+
+	semaphore := make(chan struct{}, a.maxToolConcurrency())
+	var wg sync.WaitGroup
+	for _, i := range batch {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			message, err := a.executeToolCall(ctx, toolCalls[i])
+			results[i] = toolCallResult{message, err}
+		}(i)
+	}
+	wg.Wait()
+}
+
+// planToolCallBatches splits the calls into ordered batches of indexes. In safe mode consecutive calls to
+// read-only or idempotent tools share a batch while any other call runs on its own.
+func (a *Agent) planToolCallBatches(toolCalls []openai.ChatCompletionMessageToolCall) [][]int {
+	mode := a.parallelToolCallsMode()
+	if a.maxToolConcurrency() <= 1 {
+		mode = ParallelToolCallsNone
+	}
+
+	var batches [][]int
+	var current []int
+	for i, tc := range toolCalls {
+		switch {
+		case mode == ParallelToolCallsAll:
+			current = append(current, i)
+		case mode == ParallelToolCallsSafe && a.Tools != nil && a.Tools.IsParallelSafe(tc.Function.Name):
+			current = append(current, i)
+		default:
+			if len(current) > 0 {
+				batches = append(batches, current)
+				current = nil
+			}
+			batches = append(batches, []int{i})
+		}
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+func (a *Agent) parallelToolCallsMode() string {
+	if a.ToolExecution == nil || a.ToolExecution.Parallel == "" {
+		return ParallelToolCallsSafe
+	}
+	return a.ToolExecution.Parallel
+}
+
+func (a *Agent) maxToolConcurrency() int {
+	if a.ToolExecution == nil || a.ToolExecution.MaxConcurrency == 0 {
+		return defaultMaxToolConcurrency
+	}
+	return a.ToolExecution.MaxConcurrency
+}
+
 // executeLocally executes the agent using the built-in OpenAI-compatible engine
-func (a *Agent) executeLocally(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+func (a *Agent) executeLocally(ctx context.Context, userInput Message, history, progress []Message) ([]Message, error) {
 	var tools []openai.ChatCompletionToolParam
 	if a.Tools != nil {
 		tools = a.Tools.ToOpenAITools()
@@ -209,31 +328,67 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 		return nil, err
 	}
 
-	newMessages := []Message{}
+	agentMessages = append(agentMessages, progress...)
+	newMessages := append([]Message{}, progress...)
+	budget := newExecutionBudget(a.FullName(), a.Limits)
+	budget.restore(progress)
+
+	if hasFinalAnswer(progress) {
+		return newMessages, nil
+	}
+
+	if pending := PendingToolCalls(progress); len(pending) > 0 {
+		if err := a.executeToolCalls(ctx, pending, &agentMessages, &newMessages); err != nil {
+			return newMessages, err
+		}
+		a.saveProgress(ctx, newMessages)
+	}
 
 	for {
 		if ctx.Err() != nil {
 			return newMessages, ctx.Err()
 		}
 
+		if err := budget.checkModelCall(); err != nil {
+			return newMessages, err
+		}
+
+		agentMessages, err = a.fitContextWindow(ctx, agentMessages)
+		if err != nil {
+			return newMessages, err
+		}
+
 		response, err := a.executeModelCall(ctx, agentMessages, tools)
 		if err != nil {
-			return nil, err
+			return newMessages, err
 		}
+		budget.recordModelCall(response.Usage)
 
 		choice := response.Choices[0]
 		assistantMessage := a.processAssistantMessage(choice)
 
 		agentMessages = append(agentMessages, assistantMessage)
 		newMessages = append(newMessages, assistantMessage)
+		a.saveProgress(ctx, newMessages)
 
-		if len(choice.Message.ToolCalls) == 0 {
+		toolCalls := choice.Message.ToolCalls
+		if len(toolCalls) == 0 {
 			return newMessages, nil
 		}
 
-		if err := a.executeToolCalls(ctx, choice.Message.ToolCalls, &agentMessages, &newMessages); err != nil {
+		allowed := budget.allowToolCalls(len(toolCalls))
+		if err := a.executeToolCalls(ctx, toolCalls[:allowed], &agentMessages, &newMessages); err != nil {
 			return newMessages, err
 		}
+
+		if allowed < len(toolCalls) {
+			skipped := skippedToolMessages(toolCalls[allowed:], TerminationReasonMaxToolCalls)
+			agentMessages = append(agentMessages, skipped...)
+			newMessages = append(newMessages, skipped...)
+			return newMessages, budget.toolCallLimitReached()
+		}
+
+		a.saveProgress(ctx, newMessages)
 	}
 }
 
@@ -291,7 +446,7 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 
 	tools := NewToolRegistry()
 
-	if err := tools.registerTools(ctx, k8sClient, crd); err != nil {
+	if err := tools.registerTools(ctx, k8sClient, crd, eventRecorder); err != nil {
 		return nil, err
 	}
 
@@ -307,6 +462,9 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 		ExecutionEngine: crd.Spec.ExecutionEngine,
 		Annotations:     crd.Annotations,
 		OutputSchema:    crd.Spec.OutputSchema,
+		ToolExecution:   crd.Spec.ToolExecution,
+		Limits:          crd.Spec.Limits,
+		ContextWindow:   crd.Spec.ContextWindow,
 		client:          k8sClient,
 	}, nil
 }
@@ -0,0 +1,81 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"fmt"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// executionBudget tracks what a single agent execution has consumed against the agent's limits
+type executionBudget struct {
+	agent     string
+	limits    *arkv1alpha1.AgentLimits
+	turns     int
+	toolCalls int
+	tokens    int64
+}
+
+func newExecutionBudget(agent string, limits *arkv1alpha1.AgentLimits) *executionBudget {
+	return &executionBudget{agent: agent, limits: limits}
+}
+
+// checkModelCall returns an ExecutionLimitReached error when another model call would exceed the turn or token limit
+func (b *executionBudget) checkModelCall() error {
+	if b.limits == nil {
+		return nil
+	}
+	if b.limits.MaxTurns != nil && b.turns >= *b.limits.MaxTurns {
+		return b.limitReached(TerminationReasonMaxTurns, int64(*b.limits.MaxTurns))
+	}
+	if b.limits.MaxTokens != nil && b.tokens >= *b.limits.MaxTokens {
+		return b.limitReached(TerminationReasonMaxTokens, *b.limits.MaxTokens)
+	}
+	return nil
+}
+
+// restore counts the turns and tool calls already spent by a checkpointed execution that is being resumed.
+// Token usage is not part of the checkpoint, so the token limit only applies to what the resumed run consumes.
+func (b *executionBudget) restore(progress []Message) {
+	for _, message := range progress {
+		if message.OfAssistant != nil {
+			b.turns++
+			b.toolCalls += len(message.OfAssistant.ToolCalls)
+		}
+	}
+}
+
+func (b *executionBudget) recordModelCall(usage openai.CompletionUsage) {
+	b.turns++
+	b.tokens += usage.TotalTokens
+}
+
+// allowToolCalls reserves budget for up to requested tool calls and returns how many may run
+func (b *executionBudget) allowToolCalls(requested int) int {
+	allowed := requested
+	if b.limits != nil && b.limits.MaxToolCalls != nil {
+		allowed = max(min(requested, *b.limits.MaxToolCalls-b.toolCalls), 0)
+	}
+	b.toolCalls += allowed
+	return allowed
+}
+
+func (b *executionBudget) toolCallLimitReached() error {
+	return b.limitReached(TerminationReasonMaxToolCalls, int64(*b.limits.MaxToolCalls))
+}
+
+func (b *executionBudget) limitReached(reason string, limit int64) error {
+	return &ExecutionLimitReached{Agent: b.agent, Reason: reason, Limit: limit}
+}
+
+// skippedToolMessages answers tool calls that were not executed, so the conversation stays valid for later turns
+func skippedToolMessages(toolCalls []openai.ChatCompletionMessageToolCall, reason string) []Message {
+	messages := make([]Message, 0, len(toolCalls))
+	for _, toolCall := range toolCalls {
+		messages = append(messages, ToolMessage(fmt.Sprintf("tool call not executed: %s limit reached", reason), toolCall.ID))
+	}
+	return messages
+}
@@ -0,0 +1,282 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type slowExecutor struct {
+	delay   time.Duration
+	running atomic.Int32
+	peak    atomic.Int32
+}
+
+func (e *slowExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	running := e.running.Add(1)
+	defer e.running.Add(-1)
+	for {
+		peak := e.peak.Load()
+		if running <= peak || e.peak.CompareAndSwap(peak, running) {
+			break
+		}
+	}
+	time.Sleep(e.delay)
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: "result-" + call.ID}, nil
+}
+
+func newToolCall(id, name string) openai.ChatCompletionMessageToolCall {
+	return openai.ChatCompletionMessageToolCall{
+		ID:       id,
+		Type:     "function",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: name, Arguments: "{}"},
+	}
+}
+
+func newTestAgent(toolExecution *arkv1alpha1.ToolExecution, executor ToolExecutor) *Agent {
+	tools := NewToolRegistry()
+	tools.RegisterTool(ToolDefinition{Name: "lookup", Annotations: &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true}}, executor)
+	tools.RegisterTool(ToolDefinition{Name: "write"}, executor)
+
+	return &Agent{
+		Name:          "researcher",
+		Namespace:     "default",
+		Tools:         tools,
+		Recorder:      &mockRecorder{},
+		ToolExecution: toolExecution,
+	}
+}
+
+func TestPlanToolCallBatches(t *testing.T) {
+	toolCalls := []openai.ChatCompletionMessageToolCall{
+		newToolCall("1", "lookup"),
+		newToolCall("2", "lookup"),
+		newToolCall("3", "write"),
+		newToolCall("4", "lookup"),
+	}
+
+	tests := []struct {
+		name          string
+		toolExecution *arkv1alpha1.ToolExecution
+		expected      [][]int
+	}{
+		{"safe by default", nil, [][]int{{0, 1}, {2}, {3}}},
+		{"all", &arkv1alpha1.ToolExecution{Parallel: ParallelToolCallsAll}, [][]int{{0, 1, 2, 3}}},
+		{"none", &arkv1alpha1.ToolExecution{Parallel: ParallelToolCallsNone}, [][]int{{0}, {1}, {2}, {3}}},
+		{"concurrency of one", &arkv1alpha1.ToolExecution{Parallel: ParallelToolCallsAll, MaxConcurrency: 1}, [][]int{{0}, {1}, {2}, {3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newTestAgent(tt.toolExecution, &slowExecutor{})
+			assert.Equal(t, tt.expected, agent.planToolCallBatches(toolCalls))
+		})
+	}
+}
+
+func TestExecuteToolCallsKeepsCallOrder(t *testing.T) {
+	executor := &slowExecutor{delay: 50 * time.Millisecond}
+	agent := newTestAgent(&arkv1alpha1.ToolExecution{Parallel: ParallelToolCallsSafe, MaxConcurrency: 3}, executor)
+
+	toolCalls := []openai.ChatCompletionMessageToolCall{
+		newToolCall("a", "lookup"),
+		newToolCall("b", "lookup"),
+		newToolCall("c", "lookup"),
+		newToolCall("d", "lookup"),
+	}
+
+	var agentMessages, newMessages []Message
+	start := time.Now()
+	require.NoError(t, agent.executeToolCalls(context.Background(), toolCalls, &agentMessages, &newMessages))
+
+	assert.Less(t, time.Since(start), 4*executor.delay)
+	assert.Equal(t, int32(3), executor.peak.Load())
+
+	require.Len(t, newMessages, 4)
+	for i, id := range []string{"a", "b", "c", "d"} {
+		assert.Equal(t, id, newMessages[i].OfTool.ToolCallID)
+		assert.Equal(t, "result-"+id, newMessages[i].OfTool.Content.OfString.Value)
+	}
+}
+
+func TestAgentLimits(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	tests := []struct {
+		name              string
+		limits            *arkv1alpha1.AgentLimits
+		toolCalls         int
+		expectedReason    string
+		expectedModelCall int
+		expectedMessages  int
+	}{
+		{"max turns", &arkv1alpha1.AgentLimits{MaxTurns: intPtr(3)}, 1, TerminationReasonMaxTurns, 3, 6},
+		{"max tokens", &arkv1alpha1.AgentLimits{MaxTokens: int64Ptr(250)}, 1, TerminationReasonMaxTokens, 3, 6},
+		{"max tool calls", &arkv1alpha1.AgentLimits{MaxToolCalls: intPtr(3)}, 2, TerminationReasonMaxToolCalls, 2, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var toolCalls []openai.ChatCompletionMessageToolCall
+			for i := 0; i < tt.toolCalls; i++ {
+				toolCalls = append(toolCalls, newToolCall(string(rune('a'+i)), "lookup"))
+			}
+			provider := &fakeProvider{toolCalls: [][]openai.ChatCompletionMessageToolCall{toolCalls}, usage: openai.CompletionUsage{TotalTokens: 100}}
+
+			agent := newTestAgent(nil, &slowExecutor{})
+			agent.Model = &Model{Model: "looping", Type: ModelTypeOpenAI, Provider: provider}
+			agent.Limits = tt.limits
+
+			messages, err := agent.Execute(context.Background(), NewUserMessage("hi"), nil)
+			require.Error(t, err)
+			assert.True(t, IsExecutionLimitReached(err))
+			assert.Equal(t, tt.expectedReason, TerminationReason(err))
+			assert.Equal(t, tt.expectedModelCall, provider.calls)
+
+			// Every tool call is answered, including the ones skipped because of the limit
+			require.Len(t, messages, tt.expectedMessages)
+			assert.NotNil(t, messages[len(messages)-1].OfTool)
+		})
+	}
+}
+
+type mockApprover struct {
+	decision ToolApprovalDecision
+	requests []ToolApprovalRequest
+}
+
+func (m *mockApprover) RequestApproval(ctx context.Context, request ToolApprovalRequest) (ToolApprovalDecision, error) {
+	m.requests = append(m.requests, request)
+	return m.decision, nil
+}
+
+func TestDestructiveToolCallsNeedApproval(t *testing.T) {
+	tests := []struct {
+		name            string
+		decision        ToolApprovalDecision
+		expectedContent string
+	}{
+		{"approved", ToolApprovalDecision{Approved: true}, "result-1"},
+		{"denied", ToolApprovalDecision{Reason: "not today"}, "tool call delete-records was denied by the user: not today"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &slowExecutor{}
+			agent := newTestAgent(nil, executor)
+			agent.Tools.RegisterTool(ToolDefinition{Name: "delete-records", Annotations: &arkv1alpha1.ToolAnnotations{DestructiveHint: true}}, executor)
+
+			approver := &mockApprover{decision: tt.decision}
+			ctx := WithToolApprover(context.Background(), approver)
+
+			// Non-destructive tools run without asking
+			_, err := agent.executeToolCall(ctx, newToolCall("0", "lookup"))
+			require.NoError(t, err)
+			assert.Empty(t, approver.requests)
+
+			message, err := agent.executeToolCall(ctx, newToolCall("1", "delete-records"))
+			require.NoError(t, err)
+			require.Len(t, approver.requests, 1)
+			assert.Equal(t, "delete-records", approver.requests[0].Tool)
+			assert.Equal(t, tt.expectedContent, message.OfTool.Content.OfString.Value)
+		})
+	}
+}
+
+type mockCheckpointer struct {
+	progress  []Message
+	toolCalls map[string]bool
+	err       error
+}
+
+func (m *mockCheckpointer) SaveProgress(ctx context.Context, agent string, messages []Message) error {
+	m.progress = append([]Message{}, messages...)
+	return m.err
+}
+
+func (m *mockCheckpointer) ToolCallStarted(ctx context.Context, toolCall ToolCall, sideEffects bool) error {
+	m.toolCalls[toolCall.ID] = sideEffects
+	return m.err
+}
+
+func TestAgentResumeFromCheckpoint(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	interrupted := Message(openai.ChatCompletionMessage{
+		Role:      "assistant",
+		ToolCalls: []openai.ChatCompletionMessageToolCall{newToolCall("a", "lookup"), newToolCall("b", "write")},
+	}.ToParam())
+
+	t.Run("runs unanswered tool calls before the next turn", func(t *testing.T) {
+		provider := &fakeProvider{toolCalls: [][]openai.ChatCompletionMessageToolCall{{newToolCall("c", "lookup")}}}
+		agent := newTestAgent(nil, &slowExecutor{})
+		agent.Model = &Model{Model: "looping", Type: ModelTypeOpenAI, Provider: provider}
+		agent.Limits = &arkv1alpha1.AgentLimits{MaxTurns: intPtr(2)}
+
+		checkpointer := &mockCheckpointer{toolCalls: map[string]bool{}}
+		ctx := WithCheckpointer(context.Background(), checkpointer)
+		progress := []Message{interrupted, ToolMessage("result-a", "a")}
+
+		messages, err := agent.Resume(ctx, NewUserMessage("hi"), nil, progress)
+		require.Error(t, err)
+		assert.Equal(t, TerminationReasonMaxTurns, TerminationReason(err))
+
+		// Only the call without a response is repeated, and the checkpointed turn counts against the limit
+		assert.Equal(t, map[string]bool{"b": true, "c": false}, checkpointer.toolCalls)
+		assert.Equal(t, 1, provider.calls)
+		require.Len(t, messages, 5)
+		assert.Equal(t, "b", messages[2].OfTool.ToolCallID)
+		assert.Equal(t, messages, checkpointer.progress)
+	})
+
+	t.Run("returns a checkpointed final answer", func(t *testing.T) {
+		provider := &fakeProvider{}
+		agent := newTestAgent(nil, &slowExecutor{})
+		agent.Model = &Model{Model: "looping", Type: ModelTypeOpenAI, Provider: provider}
+
+		progress := []Message{interrupted, ToolMessage("result-a", "a"), ToolMessage("result-b", "b"), NewAssistantMessage("done")}
+		messages, err := agent.Resume(context.Background(), NewUserMessage("hi"), nil, progress)
+		require.NoError(t, err)
+		assert.Equal(t, progress, messages)
+		assert.Zero(t, provider.calls)
+	})
+}
+
+func TestAgentContinuesWhenCheckpointsFail(t *testing.T) {
+	intPtr := func(v int) *int { return &v }
+	provider := &fakeProvider{toolCalls: [][]openai.ChatCompletionMessageToolCall{{newToolCall("a", "write")}}}
+	executor := &slowExecutor{}
+	agent := newTestAgent(nil, executor)
+	agent.Model = &Model{Model: "looping", Type: ModelTypeOpenAI, Provider: provider}
+	agent.Limits = &arkv1alpha1.AgentLimits{MaxTurns: intPtr(1)}
+
+	checkpointer := &mockCheckpointer{toolCalls: map[string]bool{}, err: errors.New("configmap too large")}
+	messages, err := agent.Execute(WithCheckpointer(context.Background(), checkpointer), NewUserMessage("hi"), nil)
+	assert.Equal(t, TerminationReasonMaxTurns, TerminationReason(err))
+	require.Len(t, messages, 2)
+	assert.Equal(t, "a", messages[1].OfTool.ToolCallID, "the tool call runs although it could not be checkpointed")
+}
+
+func TestPendingToolCalls(t *testing.T) {
+	assistant := Message(openai.ChatCompletionMessage{
+		Role:      "assistant",
+		ToolCalls: []openai.ChatCompletionMessageToolCall{newToolCall("a", "lookup"), newToolCall("b", "write")},
+	}.ToParam())
+
+	pending := PendingToolCalls([]Message{NewUserMessage("hi"), assistant, ToolMessage("result-b", "b")})
+	require.Len(t, pending, 1)
+	assert.Equal(t, "a", pending[0].ID)
+	assert.Equal(t, "lookup", pending[0].Function.Name)
+
+	assert.Empty(t, PendingToolCalls([]Message{assistant, ToolMessage("result-a", "a"), ToolMessage("result-b", "b"), NewAssistantMessage("done")}))
+}
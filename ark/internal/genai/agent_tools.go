@@ -38,9 +38,9 @@ func (p *MCPClientPool) GetOrCreateClient(ctx context.Context, serverName, serve
 	return mcpClient, nil
 }
 
-func (r *ToolRegistry) registerTools(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent) error {
+func (r *ToolRegistry) registerTools(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent, recorder EventEmitter) error {
 	for _, agentTool := range agent.Spec.Tools {
-		if err := r.registerTool(ctx, k8sClient, agentTool, agent.Namespace); err != nil {
+		if err := r.registerTool(ctx, k8sClient, agentTool, agent.Namespace, recorder); err != nil {
 			return err
 		}
 	}
@@ -149,7 +149,7 @@ func (r *ToolRegistry) registerSingleCustomTool(ctx context.Context, k8sClient c
 	return nil
 }
 
-func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, namespace string) error {
+func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, namespace string, recorder EventEmitter) error {
 	switch agentTool.Type {
 	case AgentToolTypeBuiltIn:
 		switch agentTool.Name {
@@ -157,6 +157,10 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 			r.RegisterTool(GetNoopTool(), &NoopExecutor{})
 		case "terminate":
 			r.RegisterTool(GetTerminateTool(), &TerminateExecutor{})
+		case "scratchpad":
+			r.RegisterTool(GetScratchpadTool(), &ScratchpadExecutor{})
+		case "handoff":
+			r.RegisterTool(GetHandoffTool(), &HandoffExecutor{})
 		default:
 			return fmt.Errorf("unsupported built-in tool %s", agentTool.Name)
 		}
@@ -164,6 +168,10 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 		if err := r.registerCustomTool(ctx, k8sClient, agentTool, namespace); err != nil {
 			return err
 		}
+	case AgentToolTypeAgent, AgentToolTypeTeam:
+		if err := r.registerMemberTool(ctx, k8sClient, agentTool, namespace, recorder); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("unsupported tool type %s %s", agentTool.Type, agentTool.Name)
 	}
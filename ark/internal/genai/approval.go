@@ -0,0 +1,84 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openai/openai-go"
+)
+
+// ToolApprovalRequest describes a tool call that needs a human decision before it runs
+type ToolApprovalRequest struct {
+	ToolCallID string
+	Agent      string
+	Tool       string
+	Arguments  string
+}
+
+type ToolApprovalDecision struct {
+	Approved bool
+	Reason   string
+}
+
+// ToolApprover blocks until the requested tool call is approved or denied, or ctx is done
+type ToolApprover interface {
+	RequestApproval(ctx context.Context, request ToolApprovalRequest) (ToolApprovalDecision, error)
+}
+
+const toolApproverKey contextKey = "toolApprover"
+
+func WithToolApprover(ctx context.Context, approver ToolApprover) context.Context {
+	return context.WithValue(ctx, toolApproverKey, approver)
+}
+
+func getToolApprover(ctx context.Context) ToolApprover {
+	if val := ctx.Value(toolApproverKey); val != nil {
+		if approver, ok := val.(ToolApprover); ok {
+			return approver
+		}
+	}
+	return nil
+}
+
+// requestToolApproval asks the approver attached to ctx for a decision. Without an approver the call is allowed,
+// which keeps direct tool execution outside of queries unchanged.
+func (a *Agent) requestToolApproval(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) (ToolApprovalDecision, error) {
+	approver := getToolApprover(ctx)
+	if approver == nil {
+		return ToolApprovalDecision{Approved: true}, nil
+	}
+
+	tracker := NewOperationTracker(a.Recorder, ctx, "ToolApproval", toolCall.Function.Name, map[string]string{
+		"toolId":    toolCall.ID,
+		"toolName":  toolCall.Function.Name,
+		"agentName": a.FullName(),
+		"queryId":   getQueryID(ctx),
+	})
+
+	decision, err := approver.RequestApproval(ctx, ToolApprovalRequest{
+		ToolCallID: toolCall.ID,
+		Agent:      a.FullName(),
+		Tool:       toolCall.Function.Name,
+		Arguments:  toolCall.Function.Arguments,
+	})
+	if err != nil {
+		tracker.Fail(err)
+		return ToolApprovalDecision{}, err
+	}
+
+	tracker.CompleteWithMetadata(decision.Reason, map[string]string{
+		"approved": fmt.Sprintf("%t", decision.Approved),
+		"reason":   decision.Reason,
+	})
+	return decision, nil
+}
+
+func deniedToolMessage(toolCall openai.ChatCompletionMessageToolCall, reason string) Message {
+	content := fmt.Sprintf("tool call %s was denied by the user", toolCall.Function.Name)
+	if reason != "" {
+		content += ": " + reason
+	}
+	return ToolMessage(content, toolCall.ID)
+}
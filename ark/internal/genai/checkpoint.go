@@ -0,0 +1,105 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Checkpointer durably records how far an execution got, so that it can be resumed after the process running it
+// goes away. Tool calls are recorded before they run, which lets a resume tell apart calls that never started from
+// calls whose side effects may already have happened. Checkpoints are best effort: an execution carries on when one
+// cannot be written, and a resume then starts from the last checkpoint that was.
+type Checkpointer interface {
+	// SaveProgress stores the messages the named agent has produced so far for its current input
+	SaveProgress(ctx context.Context, agent string, messages []Message) error
+	// ToolCallStarted records a tool call that is about to run
+	ToolCallStarted(ctx context.Context, toolCall ToolCall, sideEffects bool) error
+}
+
+const checkpointerKey contextKey = "checkpointer"
+
+func WithCheckpointer(ctx context.Context, checkpointer Checkpointer) context.Context {
+	return context.WithValue(ctx, checkpointerKey, checkpointer)
+}
+
+func getCheckpointer(ctx context.Context) Checkpointer {
+	if val := ctx.Value(checkpointerKey); val != nil {
+		if checkpointer, ok := val.(Checkpointer); ok {
+			return checkpointer
+		}
+	}
+	return nil
+}
+
+func (a *Agent) saveProgress(ctx context.Context, messages []Message) {
+	checkpointer := getCheckpointer(ctx)
+	if checkpointer == nil {
+		return
+	}
+	if err := checkpointer.SaveProgress(ctx, a.Name, messages); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to checkpoint progress, continuing without it", "agent", a.FullName())
+	}
+}
+
+// recordToolCallStarted treats every tool that is not annotated as read-only or idempotent as having side effects
+func (a *Agent) recordToolCallStarted(ctx context.Context, toolCall openai.ChatCompletionMessageToolCall) {
+	sideEffects := a.Tools == nil || !a.Tools.IsParallelSafe(toolCall.Function.Name)
+	RecordToolCallStarted(ctx, ToolCall(toolCall), sideEffects)
+}
+
+// RecordToolCallStarted tells the checkpointer attached to ctx, if any, that toolCall is about to run
+func RecordToolCallStarted(ctx context.Context, toolCall ToolCall, sideEffects bool) {
+	checkpointer := getCheckpointer(ctx)
+	if checkpointer == nil {
+		return
+	}
+	if err := checkpointer.ToolCallStarted(ctx, toolCall, sideEffects); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to checkpoint tool call, continuing without it", "toolCall", toolCall.ID, "tool", toolCall.Function.Name)
+	}
+}
+
+// PendingToolCalls returns the tool calls of the last assistant message that have no tool response yet.
+// These are the calls that were in flight when a checkpointed execution was interrupted.
+func PendingToolCalls(messages []Message) []openai.ChatCompletionMessageToolCall {
+	answered := map[string]bool{}
+	for i := len(messages) - 1; i >= 0; i-- {
+		message := messages[i]
+		if message.OfTool != nil {
+			answered[message.OfTool.ToolCallID] = true
+			continue
+		}
+		if message.OfAssistant == nil {
+			return nil
+		}
+
+		var pending []openai.ChatCompletionMessageToolCall
+		for _, tc := range message.OfAssistant.ToolCalls {
+			if answered[tc.ID] {
+				continue
+			}
+			pending = append(pending, openai.ChatCompletionMessageToolCall{
+				ID: tc.ID,
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+				Type: "function",
+			})
+		}
+		return pending
+	}
+	return nil
+}
+
+// hasFinalAnswer reports whether the checkpointed messages already end with an answer that needs no further turns
+func hasFinalAnswer(messages []Message) bool {
+	if len(messages) == 0 {
+		return false
+	}
+	last := messages[len(messages)-1].OfAssistant
+	return last != nil && len(last.ToolCalls) == 0
+}
@@ -2,15 +2,21 @@ package genai
 
 // Model type constants
 const (
-	ModelTypeAzure   = "azure"
-	ModelTypeOpenAI  = "openai"
-	ModelTypeBedrock = "bedrock"
+	ModelTypeAzure     = "azure"
+	ModelTypeOpenAI    = "openai"
+	ModelTypeBedrock   = "bedrock"
+	ModelTypeAnthropic = "anthropic"
+	ModelTypeGemini    = "gemini"
+	ModelTypeLocal     = "local"
+	ModelTypePool      = "pool"
 )
 
 // Agent tool type constants
 const (
 	AgentToolTypeBuiltIn = "built-in"
 	AgentToolTypeCustom  = "custom"
+	AgentToolTypeAgent   = "agent"
+	AgentToolTypeTeam    = "team"
 )
 
 // Role constants for execution engine messages
@@ -26,3 +32,19 @@ const (
 	ToolTypeHTTP = "http"
 	ToolTypeMCP  = "mcp"
 )
+
+// Parallel tool execution modes
+const (
+	ParallelToolCallsSafe = "safe"
+	ParallelToolCallsAll  = "all"
+	ParallelToolCallsNone = "none"
+)
+
+const defaultMaxToolConcurrency = 4
+
+// Termination reasons for agent executions stopped by AgentLimits
+const (
+	TerminationReasonMaxTurns     = "MaxTurns"
+	TerminationReasonMaxToolCalls = "MaxToolCalls"
+	TerminationReasonMaxTokens    = "MaxTokens"
+)
@@ -0,0 +1,241 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	ContextStrategyTruncate  = "truncate"
+	ContextStrategyKeepLast  = "keep-last"
+	ContextStrategySummarize = "summarize"
+)
+
+const defaultKeepMessages = 10
+
+// messageTokenOverhead accounts for the role and separators every message adds to the prompt
+const messageTokenOverhead = 4
+
+const contextSummaryPrompt = `You summarize conversations so they can continue with less context. Keep the facts, decisions, open questions and tool results the rest of the conversation may need. Reply with the summary only.`
+
+// CountTokens estimates the number of prompt tokens the messages use with this model. Messages are measured in
+// their serialized form, which slightly overestimates and so errs on the side of fitting.
+func (m *Model) CountTokens(messages []Message) int64 {
+	var tokens int64
+	for _, message := range messages {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			continue
+		}
+		tokens += messageTokenOverhead + int64(float64(len(encoded))/m.charsPerToken())
+	}
+	return tokens
+}
+
+// charsPerToken is the average number of characters per token of the model's tokenizer
+func (m *Model) charsPerToken() float64 {
+	name := strings.ToLower(m.Model)
+	switch {
+	case strings.Contains(name, "claude"), m.Type == ModelTypeBedrock:
+		return 3.5
+	default:
+		return 4
+	}
+}
+
+// maxContextTokens returns the token budget of the messages sent to the model, or 0 when there is none
+func (a *Agent) maxContextTokens() int64 {
+	if a.ContextWindow != nil && a.ContextWindow.MaxTokens != nil {
+		return *a.ContextWindow.MaxTokens
+	}
+	if a.Model != nil {
+		return a.Model.MaxContextTokens
+	}
+	return 0
+}
+
+// fitContextWindow returns the messages to send to the model, fitted under the token budget with the agent's
+// strategy. The system prompt and the last message are always kept.
+func (a *Agent) fitContextWindow(ctx context.Context, messages []Message) ([]Message, error) {
+	limit := a.maxContextTokens()
+	if limit == 0 {
+		return messages, nil
+	}
+	before := a.Model.CountTokens(messages)
+	if before <= limit {
+		return messages, nil
+	}
+
+	strategy := ContextStrategyTruncate
+	keepMessages := defaultKeepMessages
+	if a.ContextWindow != nil {
+		if a.ContextWindow.Strategy != "" {
+			strategy = a.ContextWindow.Strategy
+		}
+		if a.ContextWindow.KeepMessages > 0 {
+			keepMessages = a.ContextWindow.KeepMessages
+		}
+	}
+
+	var fitted []Message
+	switch strategy {
+	case ContextStrategyKeepLast:
+		fitted = truncateMessages(a.Model, keepLastMessages(messages, keepMessages), limit)
+	case ContextStrategySummarize:
+		var err error
+		fitted, err = a.summarizeMessages(ctx, messages, limit)
+		if err != nil {
+			// Truncating still fits the context, so a failed summary does not fail the agent
+			logf.FromContext(ctx).Error(err, "falling back to truncating the context", "agent", a.FullName())
+			strategy = ContextStrategyTruncate
+			fitted = truncateMessages(a.Model, messages, limit)
+		}
+	default:
+		fitted = truncateMessages(a.Model, messages, limit)
+	}
+
+	rec := NewExecutionRecorder(a.Recorder)
+	rec.ContextWindowFitted(ctx, a.FullName(), strategy, before, a.Model.CountTokens(fitted), len(messages)-len(fitted))
+	return fitted, nil
+}
+
+// splitSystem separates the leading system prompt, if any, from the conversation
+func splitSystem(messages []Message) ([]Message, []Message) {
+	if len(messages) > 0 && messages[0].OfSystem != nil {
+		return messages[:1], messages[1:]
+	}
+	return nil, messages
+}
+
+// conversationStart moves a cut forward past tool results, so the kept messages never start with the result of a
+// tool call whose request was dropped
+func conversationStart(conversation []Message, cut int) int {
+	for cut < len(conversation)-1 && conversation[cut].OfTool != nil {
+		cut++
+	}
+	return cut
+}
+
+// truncateMessages drops the oldest messages until the rest fit under the limit
+func truncateMessages(model *Model, messages []Message, limit int64) []Message {
+	system, conversation := splitSystem(messages)
+	tokens := model.CountTokens(messages)
+
+	cut := 0
+	for cut < len(conversation)-1 && tokens > limit {
+		next := conversationStart(conversation, cut+1)
+		tokens -= model.CountTokens(conversation[cut:next])
+		cut = next
+	}
+	return append(append([]Message{}, system...), conversation[cut:]...)
+}
+
+// keepLastMessages keeps the system prompt and the last n messages
+func keepLastMessages(messages []Message, n int) []Message {
+	system, conversation := splitSystem(messages)
+	if len(conversation) <= n {
+		return messages
+	}
+	cut := conversationStart(conversation, len(conversation)-n)
+	return append(append([]Message{}, system...), conversation[cut:]...)
+}
+
+// summarizeMessages replaces the oldest messages with a summary written by the agent's model. The messages that
+// are kept leave a quarter of the budget for the summary. The dropped messages are summarized in parts that fit the
+// model's context, each part extending the summary of the ones before it.
+func (a *Agent) summarizeMessages(ctx context.Context, messages []Message, limit int64) ([]Message, error) {
+	system, conversation := splitSystem(messages)
+	kept := truncateMessages(a.Model, messages, limit*3/4)
+	dropped := conversation[:len(conversation)-(len(kept)-len(system))]
+	if len(dropped) == 0 {
+		return kept, nil
+	}
+
+	// Each request holds the prompt, the summary so far and a part of the transcript. The part gets half of the
+	// budget, which leaves the rest for the summary so far.
+	budget := limit/2 - a.Model.CountTokens([]Message{NewSystemMessage(contextSummaryPrompt)})
+	if budget <= 0 {
+		return nil, fmt.Errorf("agent %s has no room in its context to summarize", a.FullName())
+	}
+	parts, err := summaryTranscripts(a.Model, dropped, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := ""
+	for _, part := range parts {
+		if summary != "" {
+			part = "Summary of the conversation so far:\n" + summary + "\n\nThe conversation continues:\n" + part
+		}
+		if summary, err = a.summarizeTranscript(ctx, part); err != nil {
+			return nil, err
+		}
+	}
+
+	fitted := append(append([]Message{}, system...), NewUserMessage("Summary of the earlier conversation:\n"+summary))
+	return append(fitted, kept[len(system):]...), nil
+}
+
+// summaryTranscripts serializes the messages into transcripts of at most budget tokens. A message too large for a
+// transcript on its own, such as a large tool result, is cut short.
+func summaryTranscripts(model *Model, messages []Message, budget int64) ([]string, error) {
+	const truncated = "... [truncated]"
+	maxChars := int(float64(budget) * model.charsPerToken())
+
+	var parts []string
+	var part strings.Builder
+	for _, message := range messages {
+		encoded, err := json.Marshal(message)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize messages to summarize: %w", err)
+		}
+		text := string(encoded)
+		if len(text) > maxChars {
+			text = strings.ToValidUTF8(text[:max(maxChars-len(truncated), 0)], "") + truncated
+		}
+		if part.Len() > 0 && part.Len()+1+len(text) > maxChars {
+			parts = append(parts, part.String())
+			part.Reset()
+		}
+		if part.Len() > 0 {
+			part.WriteByte('\n')
+		}
+		part.WriteString(text)
+	}
+	if part.Len() > 0 {
+		parts = append(parts, part.String())
+	}
+	return parts, nil
+}
+
+// summarizeTranscript asks the agent's model to summarize a transcript
+func (a *Agent) summarizeTranscript(ctx context.Context, transcript string) (string, error) {
+	llmTracker := NewOperationTracker(a.Recorder, ctx, "LLMCall", a.Model.Model, map[string]string{
+		"agent":   a.FullName(),
+		"model":   a.Model.Model,
+		"purpose": "context_summary",
+	})
+
+	// The summary is plain text, whatever output schema the agent uses
+	summaryModel := *a.Model
+	summaryModel.OutputSchema = nil
+	response, usedModel, err := summaryModel.ChatCompletionWithFailover(ctx, []Message{
+		NewSystemMessage(contextSummaryPrompt),
+		NewUserMessage(transcript),
+	}, nil, nil, nil)
+	if err != nil {
+		llmTracker.Fail(err)
+		return "", fmt.Errorf("agent %s failed to summarize its context: %w", a.FullName(), err)
+	}
+	if len(response.Choices) == 0 {
+		err := fmt.Errorf("agent %s received an empty context summary", a.FullName())
+		llmTracker.Fail(err)
+		return "", err
+	}
+	llmTracker.CompleteWithModel("", usedModel.TokenUsage(response.Usage), usedModel.Name)
+	return response.Choices[0].Message.Content, nil
+}
@@ -0,0 +1,158 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// longConversation is a system prompt followed by turns of about 100 tokens each, one of them with a tool call
+func longConversation() []Message {
+	text := strings.Repeat("word ", 80)
+	toolCall := newToolCall("call-1", "search")
+	toolCall.Function.Arguments = `{"query": "sources"}`
+	assistantWithTool := Message(openai.ChatCompletionMessage{Role: "assistant", ToolCalls: []openai.ChatCompletionMessageToolCall{toolCall}}.ToParam())
+
+	return []Message{
+		NewSystemMessage("You are a research assistant."),
+		NewUserMessage("first question " + text),
+		assistantWithTool,
+		ToolMessage("search results "+text, "call-1"),
+		NewAssistantMessage("first answer " + text),
+		NewUserMessage("second question " + text),
+		NewAssistantMessage("second answer " + text),
+		NewUserMessage("third question"),
+	}
+}
+
+func contextAgent(provider ChatCompletionProvider, contextWindow *arkv1alpha1.ContextWindow) (*Agent, *mockRecorder) {
+	recorder := &mockRecorder{}
+	return &Agent{
+		Name:          "researcher",
+		Namespace:     "default",
+		Model:         &Model{Model: "gpt-4o", Type: ModelTypeOpenAI, Provider: provider, MaxContextTokens: 350},
+		Recorder:      recorder,
+		ContextWindow: contextWindow,
+	}, recorder
+}
+
+func TestFitContextWindowWithoutBudget(t *testing.T) {
+	agent, recorder := contextAgent(nil, nil)
+	agent.Model.MaxContextTokens = 0
+
+	messages := longConversation()
+	fitted, err := agent.fitContextWindow(context.Background(), messages)
+	require.NoError(t, err)
+	assert.Equal(t, messages, fitted)
+	assert.Empty(t, recorder.events)
+}
+
+func TestFitContextWindowTruncate(t *testing.T) {
+	agent, recorder := contextAgent(nil, nil)
+
+	fitted, err := agent.fitContextWindow(context.Background(), longConversation())
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, agent.Model.CountTokens(fitted), int64(350))
+	assert.NotNil(t, fitted[0].OfSystem)
+	assert.Nil(t, fitted[1].OfTool, "a tool result must not lose its tool call")
+	assert.Equal(t, "third question", fitted[len(fitted)-1].OfUser.Content.OfString.Value)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, "truncate", recorder.events[0].(ExecutionEvent).Metadata["strategy"])
+}
+
+func TestFitContextWindowKeepLast(t *testing.T) {
+	agent, _ := contextAgent(nil, &arkv1alpha1.ContextWindow{Strategy: ContextStrategyKeepLast, KeepMessages: 3})
+
+	fitted, err := agent.fitContextWindow(context.Background(), longConversation())
+	require.NoError(t, err)
+
+	require.Len(t, fitted, 4)
+	assert.NotNil(t, fitted[0].OfSystem)
+	assert.True(t, strings.HasPrefix(fitted[1].OfUser.Content.OfString.Value, "second question"))
+}
+
+func TestKeepLastMessagesSkipsOrphanedToolResults(t *testing.T) {
+	// The last five messages would start with a tool result, so it is dropped as well
+	kept := keepLastMessages(longConversation(), 5)
+	require.Len(t, kept, 5)
+	assert.NotNil(t, kept[0].OfSystem)
+	assert.NotNil(t, kept[1].OfAssistant)
+}
+
+func TestFitContextWindowSummarize(t *testing.T) {
+	provider := &fakeProvider{replies: []string{"The user asked two questions about sources."}}
+	agent, _ := contextAgent(provider, &arkv1alpha1.ContextWindow{Strategy: ContextStrategySummarize})
+
+	fitted, err := agent.fitContextWindow(context.Background(), longConversation())
+	require.NoError(t, err)
+
+	assert.NotNil(t, fitted[0].OfSystem)
+	assert.Equal(t, "Summary of the earlier conversation:\nThe user asked two questions about sources.", fitted[1].OfUser.Content.OfString.Value)
+	assert.Equal(t, "third question", fitted[len(fitted)-1].OfUser.Content.OfString.Value)
+	assert.Less(t, len(fitted), len(longConversation()))
+}
+
+func TestFitContextWindowSummarizeFallsBackToTruncation(t *testing.T) {
+	provider := &fakeProvider{err: errors.New("context length exceeded"), failures: 10}
+	agent, recorder := contextAgent(provider, &arkv1alpha1.ContextWindow{Strategy: ContextStrategySummarize})
+
+	fitted, err := agent.fitContextWindow(context.Background(), longConversation())
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, agent.Model.CountTokens(fitted), int64(350))
+	assert.Equal(t, "third question", fitted[len(fitted)-1].OfUser.Content.OfString.Value)
+	require.NotZero(t, provider.calls)
+	require.NotEmpty(t, recorder.events)
+	assert.Equal(t, "truncate", recorder.events[len(recorder.events)-1].(ExecutionEvent).Metadata["strategy"])
+}
+
+// summaryRequests records the size of every summary request
+type summaryRequests struct {
+	fakeProvider
+	model  *Model
+	tokens []int64
+}
+
+func (p *summaryRequests) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.tokens = append(p.tokens, p.model.CountTokens(messages))
+	return p.fakeProvider.ChatCompletion(ctx, messages, tools)
+}
+
+func TestFitContextWindowSummarizeFitsTheSummaryRequests(t *testing.T) {
+	provider := &summaryRequests{fakeProvider: fakeProvider{replies: []string{"The search returned many sources."}}}
+	agent, _ := contextAgent(provider, &arkv1alpha1.ContextWindow{Strategy: ContextStrategySummarize})
+	provider.model = agent.Model
+
+	// The tool result alone is larger than the whole context
+	messages := longConversation()
+	messages[3] = ToolMessage("search results "+strings.Repeat("source ", 2000), "call-1")
+
+	fitted, err := agent.fitContextWindow(context.Background(), messages)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, provider.tokens)
+	for _, tokens := range provider.tokens {
+		assert.LessOrEqual(t, tokens, int64(350))
+	}
+	assert.Equal(t, "Summary of the earlier conversation:\nThe search returned many sources.", fitted[1].OfUser.Content.OfString.Value)
+}
+
+func TestAgentMaxContextTokens(t *testing.T) {
+	maxTokens := int64(1000)
+	agent, _ := contextAgent(nil, &arkv1alpha1.ContextWindow{MaxTokens: &maxTokens})
+	assert.Equal(t, int64(1000), agent.maxContextTokens())
+
+	agent.ContextWindow = nil
+	assert.Equal(t, int64(350), agent.maxContextTokens())
+}
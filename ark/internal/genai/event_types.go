@@ -51,6 +51,16 @@ type TokenUsage struct {
 	PromptTokens     int64 `json:"prompt_tokens,omitempty"`
 	CompletionTokens int64 `json:"completion_tokens,omitempty"`
 	TotalTokens      int64 `json:"total_tokens,omitempty"`
+	// Cost of the tokens, for models that have pricing set
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// Add accumulates usage into u
+func (u *TokenUsage) Add(usage TokenUsage) {
+	u.PromptTokens += usage.PromptTokens
+	u.CompletionTokens += usage.CompletionTokens
+	u.TotalTokens += usage.TotalTokens
+	u.Cost += usage.Cost
 }
 
 type OperationEvent struct {
@@ -74,6 +84,9 @@ func (e OperationEvent) ToMap() map[string]interface{} {
 			"completion_tokens": e.TokenUsage.CompletionTokens,
 			"total_tokens":      e.TokenUsage.TotalTokens,
 		}
+		if e.TokenUsage.Cost > 0 {
+			result["cost"] = FormatCost(e.TokenUsage.Cost)
+		}
 	}
 	return result
 }
@@ -313,6 +313,14 @@ func buildModelConfig(model *Model) map[string]any {
 			modelConfig["openai"] = configProvider.BuildConfig()
 		case ModelTypeBedrock:
 			modelConfig["bedrock"] = configProvider.BuildConfig()
+		case ModelTypeAnthropic:
+			modelConfig["anthropic"] = configProvider.BuildConfig()
+		case ModelTypeGemini:
+			modelConfig["gemini"] = configProvider.BuildConfig()
+		case ModelTypeLocal:
+			modelConfig["local"] = configProvider.BuildConfig()
+		case ModelTypePool:
+			modelConfig["pool"] = configProvider.BuildConfig()
 		}
 	}
 
@@ -101,3 +101,34 @@ func (r *ExecutionRecorder) SelectorModelResponse(ctx context.Context, teamName,
 	}
 	r.emitter.EmitEvent(ctx, "SelectorModelResponse", event)
 }
+
+func (r *ExecutionRecorder) ContextWindowFitted(ctx context.Context, agentName, strategy string, tokensBefore, tokensAfter int64, droppedMessages int) {
+	event := ExecutionEvent{
+		BaseEvent: BaseEvent{
+			Name: agentName,
+			Metadata: map[string]string{
+				"strategy":         strategy,
+				"tokens_before":    fmt.Sprintf("%d", tokensBefore),
+				"tokens_after":     fmt.Sprintf("%d", tokensAfter),
+				"dropped_messages": fmt.Sprintf("%d", droppedMessages),
+			},
+		},
+		Type: "agent_context",
+	}
+	r.emitter.EmitEvent(ctx, "ContextWindowFitted", event)
+}
+
+func (r *ExecutionRecorder) ModelFailover(ctx context.Context, caller, fromModel, toModel string, err error) {
+	event := ExecutionEvent{
+		BaseEvent: BaseEvent{
+			Name: caller,
+			Metadata: map[string]string{
+				"from_model": fromModel,
+				"to_model":   toModel,
+				"error":      err.Error(),
+			},
+		},
+		Type: "model_failover",
+	}
+	r.emitter.EmitEvent(ctx, "ModelFailover", event)
+}
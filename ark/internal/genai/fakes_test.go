@@ -0,0 +1,106 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// fakeMember is a team member whose executions are scripted. Each execution answers with the next of its responses,
+// starting over after the last, unless the member fails with err or hands off with the handoff summary. It records
+// the history it was last given.
+type fakeMember struct {
+	name      string
+	responses []string
+	err       error
+	handoff   string
+	calls     int
+	history   []Message
+}
+
+func (m *fakeMember) Execute(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	m.calls++
+	m.history = history
+	if m.err != nil {
+		return nil, m.err
+	}
+
+	if m.handoff != "" {
+		call := ToolCall(newToolCall("1", "handoff"))
+		arguments, _ := json.Marshal(map[string]string{"summary": m.handoff})
+		call.Function.Arguments = string(arguments)
+
+		result, err := (&HandoffExecutor{}).Execute(ctx, call)
+		return []Message{NewAssistantMessage("working on it"), ToolMessage(result.Content, result.ID)}, err
+	}
+
+	response := "done"
+	if len(m.responses) > 0 {
+		response = m.responses[(m.calls-1)%len(m.responses)]
+	}
+	return []Message{NewAssistantMessage(response)}, nil
+}
+
+func (m *fakeMember) GetName() string        { return m.name }
+func (m *fakeMember) GetType() string        { return "agent" }
+func (m *fakeMember) GetDescription() string { return "" }
+
+// testUsage is the usage of a call with 10 prompt tokens and 5 completion tokens
+var testUsage = openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}
+
+// fakeProvider is a model provider whose answers are scripted. Its first failures calls fail with err. The others
+// ask for the next of its tool calls or, when it has none, answer with the next of its replies, starting over after
+// the last. It records the tool results it is sent and the schema it was last asked for.
+type fakeProvider struct {
+	replies     []string
+	toolCalls   [][]openai.ChatCompletionMessageToolCall
+	usage       openai.CompletionUsage
+	err         error
+	failures    int
+	calls       int
+	schemaCalls int
+	schemaName  string
+	toolResults []string
+}
+
+func (p *fakeProvider) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, p.err
+	}
+	if len(messages) > 0 {
+		if last := messages[len(messages)-1].OfTool; last != nil {
+			p.toolResults = append(p.toolResults, last.Content.OfString.Value)
+		}
+	}
+
+	answer := p.calls - p.failures - 1
+	if len(p.toolCalls) > 0 {
+		return &openai.ChatCompletion{
+			Choices: []openai.ChatCompletionChoice{{
+				FinishReason: "tool_calls",
+				Message:      openai.ChatCompletionMessage{Role: "assistant", ToolCalls: p.toolCalls[answer%len(p.toolCalls)]},
+			}},
+			Usage: p.usage,
+		}, nil
+	}
+
+	content := ""
+	if len(p.replies) > 0 {
+		content = p.replies[answer%len(p.replies)]
+	}
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{FinishReason: "stop", Message: openai.ChatCompletionMessage{Role: "assistant", Content: content}}},
+		Usage:   p.usage,
+	}, nil
+}
+
+func (p *fakeProvider) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.schemaCalls++
+	p.schemaName = schemaName
+	return p.ChatCompletion(ctx, messages, tools)
+}
@@ -0,0 +1,151 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// maxMemberToolDepth bounds how deeply agent and team tools can call each other, so agents that call each other
+// cannot recurse forever
+const maxMemberToolDepth = 5
+
+const memberToolDepthKey contextKey = "memberToolDepth"
+
+var invalidToolNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// MemberToolExecutor calls an agent or team as a tool. The agent or team is loaded on each call rather than when
+// the tool is registered, so agents that use each other as tools do not load each other forever.
+type MemberToolExecutor struct {
+	K8sClient  client.Client
+	MemberType string
+	MemberName string
+	Namespace  string
+	Recorder   EventEmitter
+}
+
+func (e *MemberToolExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var arguments struct {
+		Input string `json:"input"`
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("failed to parse arguments of %s tool %s: %w", e.MemberType, e.MemberName, err)
+	}
+
+	depth := memberToolDepth(ctx)
+	if depth >= maxMemberToolDepth {
+		err := fmt.Errorf("%s tool %s exceeds the maximum of %d nested agent and team tool calls", e.MemberType, e.MemberName, maxMemberToolDepth)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	member, err := e.loadMember(ctx)
+	if err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	// The member records its events, and so its token usage, with the calling agent's recorder. It answers the
+	// calling agent rather than the team, so it cannot hand off. Its progress is not checkpointed, as a resume
+	// replays the calling agent's tool call instead, and its answer is not streamed as the query's. Its tool calls
+	// still need the approvals the query asks for.
+	memberCtx := withTeamMember(context.WithValue(ctx, memberToolDepthKey, depth+1), false)
+	memberCtx = WithStreamPublisher(WithCheckpointer(memberCtx, nil), nil)
+	messages, err := member.Execute(memberCtx, NewUserMessage(arguments.Input), nil)
+	// A member that calls terminate has answered. It must not end the caller's team.
+	if err != nil && !IsTerminateTeam(err) {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("%s tool %s failed: %w", e.MemberType, e.MemberName, err)
+	}
+
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: lastMessageContent(messages)}, nil
+}
+
+func (e *MemberToolExecutor) loadMember(ctx context.Context) (TeamMember, error) {
+	key := types.NamespacedName{Name: e.MemberName, Namespace: e.Namespace}
+
+	switch e.MemberType {
+	case AgentToolTypeAgent:
+		var agentCRD arkv1alpha1.Agent
+		if err := e.K8sClient.Get(ctx, key, &agentCRD); err != nil {
+			return nil, fmt.Errorf("failed to get agent %s: %w", e.MemberName, err)
+		}
+		return MakeAgent(ctx, e.K8sClient, &agentCRD, e.Recorder)
+	case AgentToolTypeTeam:
+		var teamCRD arkv1alpha1.Team
+		if err := e.K8sClient.Get(ctx, key, &teamCRD); err != nil {
+			return nil, fmt.Errorf("failed to get team %s: %w", e.MemberName, err)
+		}
+		return MakeTeam(ctx, e.K8sClient, &teamCRD, e.Recorder)
+	default:
+		return nil, fmt.Errorf("unsupported member tool type %s", e.MemberType)
+	}
+}
+
+func memberToolDepth(ctx context.Context) int {
+	if depth, ok := ctx.Value(memberToolDepthKey).(int); ok {
+		return depth
+	}
+	return 0
+}
+
+// CreateMemberTool returns the definition of a tool that sends its input to an agent or team. Characters that
+// are not allowed in tool names are replaced with underscores.
+func CreateMemberTool(name, description string) ToolDefinition {
+	return ToolDefinition{
+		Name:        invalidToolNameChars.ReplaceAllString(name, "_"),
+		Description: description,
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"input": map[string]any{
+					"type":        "string",
+					"description": "The request to send, with all the context needed to answer it",
+				},
+			},
+			"required": []string{"input"},
+		},
+	}
+}
+
+func (r *ToolRegistry) registerMemberTool(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, namespace string, recorder EventEmitter) error {
+	if agentTool.Name == "" {
+		return fmt.Errorf("name must be specified for %s tool", agentTool.Type)
+	}
+
+	description := agentTool.Description
+	key := types.NamespacedName{Name: agentTool.Name, Namespace: namespace}
+	switch agentTool.Type {
+	case AgentToolTypeAgent:
+		var agentCRD arkv1alpha1.Agent
+		if err := k8sClient.Get(ctx, key, &agentCRD); err != nil {
+			return fmt.Errorf("failed to load agent %v for agent tool: %w", key, err)
+		}
+		if description == "" {
+			description = agentCRD.Spec.Description
+		}
+	case AgentToolTypeTeam:
+		var teamCRD arkv1alpha1.Team
+		if err := k8sClient.Get(ctx, key, &teamCRD); err != nil {
+			return fmt.Errorf("failed to load team %v for team tool: %w", key, err)
+		}
+		if description == "" {
+			description = teamCRD.Spec.Description
+		}
+	}
+	if description == "" {
+		description = fmt.Sprintf("Send a request to the %s %s and get its response", agentTool.Type, agentTool.Name)
+	}
+
+	r.RegisterTool(CreateMemberTool(agentTool.Name, description), &MemberToolExecutor{
+		K8sClient:  k8sClient,
+		MemberType: agentTool.Type,
+		MemberName: agentTool.Name,
+		Namespace:  namespace,
+		Recorder:   recorder,
+	})
+	return nil
+}
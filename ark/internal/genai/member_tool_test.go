@@ -0,0 +1,74 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestRegisterMemberTool(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "legal.reviewer", Namespace: "default"},
+			Spec:       arkv1alpha1.AgentSpec{Description: "Reviews contracts for legal risks"},
+		},
+		&arkv1alpha1.Team{
+			ObjectMeta: metav1.ObjectMeta{Name: "pricing", Namespace: "default"},
+		},
+	).Build()
+
+	registry := NewToolRegistry()
+	require.NoError(t, registry.registerTool(context.Background(), k8sClient, arkv1alpha1.AgentTool{Type: "agent", Name: "legal.reviewer"}, "default", nil))
+	require.NoError(t, registry.registerTool(context.Background(), k8sClient, arkv1alpha1.AgentTool{Type: "team", Name: "pricing"}, "default", nil))
+
+	assert.Equal(t, "Reviews contracts for legal risks", registry.tools["legal_reviewer"].Description)
+	assert.Equal(t, "agent", registry.GetToolType("legal_reviewer"))
+	assert.Equal(t, "Send a request to the team pricing and get its response", registry.tools["pricing"].Description)
+	assert.Equal(t, "team", registry.GetToolType("pricing"))
+
+	err := registry.registerTool(context.Background(), k8sClient, arkv1alpha1.AgentTool{Type: "agent", Name: "missing"}, "default", nil)
+	assert.ErrorContains(t, err, "failed to load agent default/missing")
+}
+
+func TestMemberToolDepthLimit(t *testing.T) {
+	executor := &MemberToolExecutor{MemberType: "agent", MemberName: "researcher", Namespace: "default"}
+	ctx := context.WithValue(context.Background(), memberToolDepthKey, maxMemberToolDepth)
+
+	call := ToolCall(newToolCall("1", "researcher"))
+	call.Function.Arguments = `{"input": "find sources"}`
+	result, err := executor.Execute(ctx, call)
+	assert.ErrorContains(t, err, "exceeds the maximum of 5 nested agent and team tool calls")
+	assert.NotEmpty(t, result.Error)
+}
+
+func TestMemberToolDoesNotCheckpointOrStreamTheMember(t *testing.T) {
+	k8sClient := modelServerClient(t, "default", "Three sources found")
+	require.NoError(t, k8sClient.Create(context.Background(), &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "researcher", Namespace: "default"},
+		Spec:       arkv1alpha1.AgentSpec{Prompt: "Find sources", ModelRef: &arkv1alpha1.AgentModelRef{Name: "default"}},
+	}))
+	executor := &MemberToolExecutor{K8sClient: k8sClient, MemberType: "agent", MemberName: "researcher", Namespace: "default", Recorder: &mockRecorder{}}
+
+	checkpointer := &mockCheckpointer{toolCalls: map[string]bool{}}
+	publisher := &mockPublisher{}
+	ctx := WithStreamPublisher(WithCheckpointer(context.Background(), checkpointer), publisher)
+
+	call := ToolCall(newToolCall("1", "researcher"))
+	call.Function.Arguments = `{"input": "find sources"}`
+	result, err := executor.Execute(ctx, call)
+	require.NoError(t, err)
+	assert.Equal(t, "Three sources found", result.Content)
+	assert.Empty(t, checkpointer.progress, "the member's progress is not taken for the caller's")
+	assert.Empty(t, publisher.chunks, "the member's answer is not streamed as the query's")
+}
@@ -45,7 +45,7 @@ func ResolveModelSpec(modelSpec any, defaultNamespace string) (string, string) {
 	}
 }
 
-// LoadModel loads a model by resolving modelSpec and defaultNamespace
+// LoadModel loads a model by resolving modelSpec and defaultNamespace, along with its fallback models
 func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface{}, defaultNamespace string) (*Model, error) {
 	modelName, namespace := ResolveModelSpec(modelSpec, defaultNamespace)
 	modelCRD, err := loadModelCRD(ctx, k8sClient, modelName, namespace)
@@ -53,15 +53,51 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		return nil, fmt.Errorf("failed to load model CRD %s in namespace %s: %w", modelName, namespace, err)
 	}
 
+	modelInstance, err := newModel(ctx, k8sClient, modelCRD)
+	if err != nil {
+		return nil, err
+	}
+
+	// Fallbacks are loaded without their own fallbacks, so that chains cannot loop
+	for _, ref := range modelCRD.Spec.Fallbacks {
+		fallbackName, fallbackNamespace := ResolveModelSpec(&ref, namespace)
+		fallbackCRD, err := loadModelCRD(ctx, k8sClient, fallbackName, fallbackNamespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fallback of model %s/%s: %w", namespace, modelName, err)
+		}
+		fallback, err := newModel(ctx, k8sClient, fallbackCRD)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load fallback of model %s/%s: %w", namespace, modelName, err)
+		}
+		modelInstance.Fallbacks = append(modelInstance.Fallbacks, fallback)
+	}
+
+	return modelInstance, nil
+}
+
+func newModel(ctx context.Context, k8sClient client.Client, modelCRD *arkv1alpha1.Model) (*Model, error) {
+	modelName, namespace := modelCRD.Name, modelCRD.Namespace
+
 	resolver := common.NewValueSourceResolver(k8sClient)
 	model, err := resolver.ResolveValueSource(ctx, modelCRD.Spec.Model, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve model: %w", err)
 	}
 
+	pricing, err := parseModelPricing(modelCRD.Spec.Pricing)
+	if err != nil {
+		return nil, fmt.Errorf("model %s/%s: %w", namespace, modelName, err)
+	}
+
 	modelInstance := &Model{
-		Model: model,
-		Type:  modelCRD.Spec.Type,
+		Name:    modelName,
+		Model:   model,
+		Type:    modelCRD.Spec.Type,
+		Pricing: pricing,
+		Retry:   modelCRD.Spec.Retry,
+	}
+	if modelCRD.Spec.MaxContextTokens != nil {
+		modelInstance.MaxContextTokens = *modelCRD.Spec.MaxContextTokens
 	}
 
 	switch modelCRD.Spec.Type {
@@ -77,6 +113,22 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		if err := loadBedrockConfig(ctx, resolver, modelCRD.Spec.Config.Bedrock, namespace, model, modelInstance); err != nil {
 			return nil, err
 		}
+	case ModelTypeAnthropic:
+		if err := loadAnthropicConfig(ctx, resolver, modelCRD.Spec.Config.Anthropic, namespace, modelInstance); err != nil {
+			return nil, err
+		}
+	case ModelTypeGemini:
+		if err := loadGeminiConfig(ctx, resolver, modelCRD.Spec.Config.Gemini, namespace, modelInstance); err != nil {
+			return nil, err
+		}
+	case ModelTypeLocal:
+		if err := loadLocalConfig(ctx, resolver, modelCRD.Spec.Config.Local, namespace, modelInstance); err != nil {
+			return nil, err
+		}
+	case ModelTypePool:
+		if err := loadPoolConfig(ctx, k8sClient, modelCRD.Spec.Config.Pool, namespace, modelInstance); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported model type: %s", modelCRD.Spec.Type)
 	}
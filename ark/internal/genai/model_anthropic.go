@@ -0,0 +1,74 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadAnthropicConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.AnthropicModelConfig, namespace string, model *Model) error {
+	if config == nil {
+		return fmt.Errorf("anthropic configuration is required for anthropic model type")
+	}
+
+	apiKey, err := resolver.ResolveValueSource(ctx, config.APIKey, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve Anthropic apiKey: %w", err)
+	}
+
+	var baseURL string
+	if config.BaseURL != nil {
+		baseURL, err = resolver.ResolveValueSource(ctx, *config.BaseURL, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Anthropic baseURL: %w", err)
+		}
+	}
+
+	var apiVersion string
+	if config.APIVersion != nil {
+		apiVersion, err = resolver.ResolveValueSource(ctx, *config.APIVersion, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Anthropic apiVersion: %w", err)
+		}
+	}
+
+	var properties map[string]string
+	if config.Properties != nil {
+		properties = make(map[string]string)
+		for key, valueSource := range config.Properties {
+			value, err := resolver.ResolveValueSource(ctx, valueSource, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve Anthropic property %s: %w", key, err)
+			}
+			properties[key] = value
+		}
+	}
+
+	if config.MaxTokens != nil {
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties["max_tokens"] = fmt.Sprintf("%d", *config.MaxTokens)
+	}
+
+	if config.Temperature != nil {
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties["temperature"] = *config.Temperature
+	}
+
+	anthropicProvider := &AnthropicProvider{
+		Model:      model.Model,
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		APIVersion: apiVersion,
+		Properties: properties,
+	}
+	model.Provider = anthropicProvider
+	model.Properties = properties
+
+	return nil
+}
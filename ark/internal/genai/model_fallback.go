@@ -0,0 +1,135 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// Retry policy defaults, matching the defaults of the Model CRD
+const (
+	defaultModelMaxRetries     = 2
+	defaultModelInitialBackoff = time.Second
+	defaultModelMaxBackoff     = 30 * time.Second
+)
+
+// FailoverFunc is told when a model call moves on from a model that failed to the next fallback
+type FailoverFunc func(from, to *Model, err error)
+
+// ChatCompletionWithFailover behaves like ChatCompletionStream, retrying calls that fail with a rate limit, a server
+// error or a connection failure as the model's retry policy allows, then calling the fallback models in order. It
+// returns the model that answered, which is the backend that answered when the model is a pool. A call is not failed
+// over once it has streamed chunks, as they cannot be taken back.
+func (m *Model) ChatCompletionWithFailover(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk), onFailover FailoverFunc) (*openai.ChatCompletion, *Model, error) {
+	models := append([]*Model{m}, m.Fallbacks...)
+
+	var err error
+	for i, model := range models {
+		if i > 0 {
+			logf.FromContext(ctx).Info("model failing over", "from", models[i-1].Name, "to", model.Name, "error", err.Error())
+			if onFailover != nil {
+				onFailover(models[i-1], model, err)
+			}
+			model.OutputSchema = m.OutputSchema
+			model.SchemaName = m.SchemaName
+		}
+
+		streamed := false
+		var handler func(openai.ChatCompletionChunk)
+		if onChunk != nil {
+			handler = func(chunk openai.ChatCompletionChunk) {
+				streamed = true
+				onChunk(chunk)
+			}
+		}
+
+		var response *openai.ChatCompletion
+		var backend *Model
+		response, err = model.chatCompletionWithRetries(withAnsweringBackend(ctx, &backend), messages, tools, handler, &streamed)
+		if err == nil {
+			if backend != nil {
+				return response, backend, nil
+			}
+			return response, model, nil
+		}
+		if streamed || !isRetryableModelError(ctx, err) {
+			return nil, model, err
+		}
+	}
+	return nil, models[len(models)-1], err
+}
+
+func (m *Model) chatCompletionWithRetries(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk), streamed *bool) (*openai.ChatCompletion, error) {
+	maxRetries, initialBackoff, maxBackoff := retryPolicy(m.Retry)
+
+	backoff := initialBackoff
+	for retry := 0; ; retry++ {
+		response, err := m.complete(ctx, messages, tools, onChunk)
+		if err == nil || retry >= maxRetries || *streamed || !isRetryableModelError(ctx, err) {
+			return response, err
+		}
+
+		logf.FromContext(ctx).Info("retrying model call", "model", m.Name, "retry", retry+1, "backoff", backoff.String(), "error", err.Error())
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// retryPolicy returns the number of retries and the backoff bounds of the policy. Models without a policy are not
+// retried, as most provider clients already retry on their own.
+func retryPolicy(policy *arkv1alpha1.ModelRetryPolicy) (int, time.Duration, time.Duration) {
+	if policy == nil {
+		return 0, 0, 0
+	}
+
+	maxRetries := defaultModelMaxRetries
+	if policy.MaxRetries != nil {
+		maxRetries = *policy.MaxRetries
+	}
+	initialBackoff := defaultModelInitialBackoff
+	if policy.InitialBackoff != nil {
+		initialBackoff = policy.InitialBackoff.Duration
+	}
+	maxBackoff := defaultModelMaxBackoff
+	if policy.MaxBackoff != nil {
+		maxBackoff = policy.MaxBackoff.Duration
+	}
+	return maxRetries, initialBackoff, max(initialBackoff, maxBackoff)
+}
+
+// isRetryableModelError reports whether a failed model call may succeed when made again or made to another model.
+// That is the case for rate limits, timeouts and server errors, and for requests that did not reach the provider.
+// Calls are not retried once the caller's context is done.
+func isRetryableModelError(ctx context.Context, err error) bool {
+	if err == nil || ctx.Err() != nil {
+		return false
+	}
+
+	statusCode := 0
+	var openaiErr *openai.Error
+	var statusErr interface{ HTTPStatusCode() int }
+	switch {
+	case errors.As(err, &openaiErr):
+		statusCode = openaiErr.StatusCode
+	case errors.As(err, &statusErr):
+		statusCode = statusErr.HTTPStatusCode()
+	default:
+		var urlErr *url.Error
+		return errors.As(err, &urlErr)
+	}
+
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusRequestTimeout || statusCode >= http.StatusInternalServerError
+}
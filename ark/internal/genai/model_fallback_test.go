@@ -0,0 +1,164 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func fastRetry(maxRetries int) *arkv1alpha1.ModelRetryPolicy {
+	return &arkv1alpha1.ModelRetryPolicy{
+		MaxRetries:     &maxRetries,
+		InitialBackoff: &metav1.Duration{Duration: time.Millisecond},
+		MaxBackoff:     &metav1.Duration{Duration: 2 * time.Millisecond},
+	}
+}
+
+func TestModelRetry(t *testing.T) {
+	rateLimited := &APIError{Source: "anthropic API", StatusCode: http.StatusTooManyRequests, Message: "rate limited"}
+
+	t.Run("retries until the call succeeds", func(t *testing.T) {
+		provider := &fakeProvider{err: rateLimited, failures: 2, replies: []string{"answer"}}
+		model := &Model{Name: "primary", Provider: provider, Retry: fastRetry(2)}
+
+		response, used, err := model.ChatCompletionWithFailover(context.Background(), []Message{NewUserMessage("hi")}, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "answer", response.Choices[0].Message.Content)
+		assert.Same(t, model, used)
+		assert.Equal(t, 3, provider.calls)
+	})
+
+	t.Run("gives up after the last retry", func(t *testing.T) {
+		provider := &fakeProvider{err: rateLimited, failures: 5}
+		model := &Model{Name: "primary", Provider: provider, Retry: fastRetry(1)}
+
+		_, _, err := model.ChatCompletionWithFailover(context.Background(), []Message{NewUserMessage("hi")}, nil, nil, nil)
+		assert.ErrorIs(t, err, rateLimited)
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("does not retry without a policy", func(t *testing.T) {
+		provider := &fakeProvider{err: rateLimited, failures: 1}
+		model := &Model{Name: "primary", Provider: provider}
+
+		_, err := model.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil)
+		assert.Error(t, err)
+		assert.Equal(t, 1, provider.calls)
+	})
+}
+
+func TestModelFailover(t *testing.T) {
+	unavailable := &APIError{Source: "azure", StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+	primary := &fakeProvider{err: unavailable, failures: 10}
+	secondary := &fakeProvider{err: unavailable, failures: 10}
+	last := &fakeProvider{replies: []string{"from the last fallback"}}
+
+	model := &Model{
+		Name:         "primary",
+		Provider:     primary,
+		Retry:        fastRetry(1),
+		OutputSchema: &runtime.RawExtension{Raw: []byte(`{"type": "object"}`)},
+		SchemaName:   "answer",
+		Fallbacks: []*Model{
+			{Name: "secondary", Provider: secondary},
+			{Name: "last", Provider: last},
+		},
+	}
+
+	var failovers []string
+	onFailover := func(from, to *Model, err error) {
+		assert.ErrorIs(t, err, unavailable)
+		failovers = append(failovers, from.Name+"->"+to.Name)
+	}
+
+	response, used, err := model.ChatCompletionWithFailover(context.Background(), []Message{NewUserMessage("hi")}, nil, nil, onFailover)
+	require.NoError(t, err)
+	assert.Equal(t, "from the last fallback", response.Choices[0].Message.Content)
+	assert.Equal(t, "last", used.Name)
+	assert.Equal(t, []string{"primary->secondary", "secondary->last"}, failovers)
+	assert.Equal(t, 2, primary.calls, "the primary is retried under its own policy")
+	assert.Equal(t, 1, secondary.calls, "the fallback has no retry policy")
+	assert.Equal(t, "answer", last.schemaName, "the fallback answers with the output schema of the primary")
+}
+
+func TestModelFailoverStopsOnClientErrors(t *testing.T) {
+	badRequest := &APIError{Source: "openai", StatusCode: http.StatusBadRequest, Message: "invalid tool schema"}
+	fallback := &fakeProvider{replies: []string{"unused"}}
+	model := &Model{
+		Name:      "primary",
+		Provider:  &fakeProvider{err: badRequest, failures: 1},
+		Fallbacks: []*Model{{Name: "fallback", Provider: fallback}},
+	}
+
+	_, used, err := model.ChatCompletionWithFailover(context.Background(), []Message{NewUserMessage("hi")}, nil, nil, nil)
+	assert.ErrorIs(t, err, badRequest)
+	assert.Equal(t, "primary", used.Name)
+	assert.Zero(t, fallback.calls)
+}
+
+func TestIsRetryableModelError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		err       error
+		retryable bool
+	}{
+		{"rate limit", context.Background(), &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", context.Background(), &APIError{StatusCode: http.StatusBadGateway}, true},
+		{"client error", context.Background(), &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"openai rate limit", context.Background(), &openai.Error{StatusCode: http.StatusTooManyRequests}, true},
+		{"connection failure", context.Background(), &url.Error{Op: "Post", URL: "http://model", Err: errors.New("connection refused")}, true},
+		{"canceled", canceled, &APIError{StatusCode: http.StatusServiceUnavailable}, false},
+		{"other", context.Background(), errors.New("failed to parse response"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.retryable, isRetryableModelError(tt.ctx, tt.err))
+		})
+	}
+}
+
+func TestAgentRecordsModelFailover(t *testing.T) {
+	recorder := &mockRecorder{}
+	collector := NewTokenUsageCollector(recorder)
+
+	agent := newTestAgent(nil, &slowExecutor{})
+	agent.Recorder = collector
+	agent.Model = &Model{
+		Name:      "azure-gpt",
+		Provider:  &fakeProvider{err: &APIError{StatusCode: http.StatusTooManyRequests}, failures: 1},
+		Fallbacks: []*Model{{Name: "openai-gpt", Provider: &fakeProvider{replies: []string{"done"}, usage: testUsage}}},
+	}
+
+	_, err := agent.Execute(context.Background(), NewUserMessage("hi"), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"openai-gpt"}, collector.GetModels())
+	assert.Equal(t, int64(15), collector.GetTokenSummary().TotalTokens)
+
+	var failover *ExecutionEvent
+	for _, event := range recorder.events {
+		if e, ok := event.(ExecutionEvent); ok && e.Type == "model_failover" {
+			failover = &e
+		}
+	}
+	require.NotNil(t, failover)
+	assert.Equal(t, "azure-gpt", failover.Metadata["from_model"])
+	assert.Equal(t, "openai-gpt", failover.Metadata["to_model"])
+}
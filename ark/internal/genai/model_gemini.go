@@ -0,0 +1,83 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadGeminiConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.GeminiModelConfig, namespace string, model *Model) error {
+	if config == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+
+	apiKey, err := resolver.ResolveValueSource(ctx, config.APIKey, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve Gemini apiKey: %w", err)
+	}
+
+	var baseURL string
+	if config.BaseURL != nil {
+		baseURL, err = resolver.ResolveValueSource(ctx, *config.BaseURL, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Gemini baseURL: %w", err)
+		}
+	}
+
+	var project string
+	if config.Project != nil {
+		project, err = resolver.ResolveValueSource(ctx, *config.Project, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Gemini project: %w", err)
+		}
+	}
+
+	var location string
+	if config.Location != nil {
+		location, err = resolver.ResolveValueSource(ctx, *config.Location, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Gemini location: %w", err)
+		}
+	}
+
+	var properties map[string]string
+	if config.Properties != nil {
+		properties = make(map[string]string)
+		for key, valueSource := range config.Properties {
+			value, err := resolver.ResolveValueSource(ctx, valueSource, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve Gemini property %s: %w", key, err)
+			}
+			properties[key] = value
+		}
+	}
+
+	if config.MaxTokens != nil {
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties["max_tokens"] = fmt.Sprintf("%d", *config.MaxTokens)
+	}
+
+	if config.Temperature != nil {
+		if properties == nil {
+			properties = make(map[string]string)
+		}
+		properties["temperature"] = *config.Temperature
+	}
+
+	geminiProvider := &GeminiProvider{
+		Model:      model.Model,
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		Project:    project,
+		Location:   location,
+		Properties: properties,
+	}
+	model.Provider = geminiProvider
+	model.Properties = properties
+
+	return nil
+}
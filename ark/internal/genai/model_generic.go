@@ -5,6 +5,8 @@ import (
 
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/telemetry"
 )
 
@@ -18,15 +20,37 @@ type ConfigProvider interface {
 }
 
 type Model struct {
+	// Name is the name of the Model resource
+	Name         string
 	Model        string
 	Type         string
 	Properties   map[string]string
 	Provider     ChatCompletionProvider
 	OutputSchema *runtime.RawExtension
 	SchemaName   string
+	Pricing      *ModelPricing
+	// MaxContextTokens is the size of the context window, or 0 when it is not known
+	MaxContextTokens int64
+	// Retry is the retry policy of failed calls, or nil when they are not retried
+	Retry *arkv1alpha1.ModelRetryPolicy
+	// Fallbacks are called in order when a call keeps failing
+	Fallbacks []*Model
 }
 
 func (m *Model) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return m.ChatCompletionStream(ctx, messages, tools, nil)
+}
+
+// ChatCompletionStream behaves like ChatCompletion and additionally passes each chunk to onChunk as it arrives.
+// Providers without streaming support deliver the whole completion as a single chunk. A nil onChunk disables streaming.
+// Failed calls are retried and failed over to the fallback models.
+func (m *Model) ChatCompletionStream(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	response, _, err := m.ChatCompletionWithFailover(ctx, messages, tools, onChunk, nil)
+	return response, err
+}
+
+// complete makes a single call to the provider of the model
+func (m *Model) complete(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
 	if m.Provider == nil {
 		return nil, nil
 	}
@@ -47,9 +71,13 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, tools []
 	// Call the appropriate provider method based on schema presence
 	var response *openai.ChatCompletion
 	var err error
-	if m.OutputSchema == nil {
+	streamingProvider, canStream := m.Provider.(StreamingChatCompletionProvider)
+	switch {
+	case onChunk != nil && canStream:
+		response, err = streamingProvider.ChatCompletionStream(ctx, messages, m.OutputSchema, m.SchemaName, tools, onChunk)
+	case m.OutputSchema == nil:
 		response, err = m.Provider.ChatCompletion(ctx, messages, tools)
-	} else {
+	default:
 		response, err = m.Provider.ChatCompletionWithSchema(ctx, messages, m.OutputSchema, m.SchemaName, tools)
 	}
 
@@ -58,9 +86,13 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, tools []
 		return nil, err
 	}
 
+	if onChunk != nil && !canStream {
+		onChunk(completionAsChunk(response))
+	}
+
 	// Set output and token usage
 	telemetry.SetLLMCompletionOutput(span, response)
-	telemetry.AddLLMTokenUsage(span, response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Usage.TotalTokens)
+	telemetry.AddLLMTokenUsage(span, response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Usage.TotalTokens, m.Pricing.Cost(response.Usage))
 	telemetry.RecordSuccess(span)
 
 	return response, nil
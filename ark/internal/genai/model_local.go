@@ -0,0 +1,55 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadLocalConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.LocalModelConfig, namespace string, model *Model) error {
+	if config == nil {
+		return fmt.Errorf("local configuration is required for local model type")
+	}
+
+	baseURL, err := resolver.ResolveValueSource(ctx, config.BaseURL, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve local baseURL: %w", err)
+	}
+
+	var apiKey string
+	if config.APIKey != nil {
+		apiKey, err = resolver.ResolveValueSource(ctx, *config.APIKey, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve local apiKey: %w", err)
+		}
+	}
+
+	var properties map[string]string
+	if config.Properties != nil {
+		properties = make(map[string]string)
+		for key, valueSource := range config.Properties {
+			value, err := resolver.ResolveValueSource(ctx, valueSource, namespace)
+			if err != nil {
+				return fmt.Errorf("failed to resolve local property %s: %w", key, err)
+			}
+			properties[key] = value
+		}
+	}
+
+	localProvider := &LocalProvider{
+		Model:      model.Model,
+		BaseURL:    baseURL,
+		APIKey:     apiKey,
+		Server:     config.Server,
+		Properties: properties,
+	}
+	if config.Timeout != nil {
+		localProvider.Timeout = config.Timeout.Duration
+	}
+	model.Provider = localProvider
+	model.Properties = properties
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func loadPoolConfig(ctx context.Context, k8sClient client.Client, config *arkv1alpha1.PoolModelConfig, namespace string, model *Model) error {
+	if config == nil {
+		return fmt.Errorf("pool configuration is required for pool model type")
+	}
+
+	poolProvider := &PoolProvider{
+		Key:      poolKey(namespace, model.Name),
+		Strategy: config.Strategy,
+		Cooldown: defaultPoolCooldown,
+	}
+	if config.Cooldown != nil {
+		poolProvider.Cooldown = config.Cooldown.Duration
+	}
+
+	for _, backendRef := range config.Backends {
+		backendCRD, err := loadModelCRD(ctx, k8sClient, backendRef.Name, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to load backend of model pool %s/%s: %w", namespace, model.Name, err)
+		}
+		if backendCRD.Spec.Type == ModelTypePool {
+			return fmt.Errorf("model pool %s/%s cannot have the pool %s as a backend", namespace, model.Name, backendRef.Name)
+		}
+
+		backend, err := newModel(ctx, k8sClient, backendCRD)
+		if err != nil {
+			return fmt.Errorf("failed to load backend of model pool %s/%s: %w", namespace, model.Name, err)
+		}
+
+		weight := 1
+		if backendRef.Weight != nil {
+			weight = *backendRef.Weight
+		}
+		poolProvider.Backends = append(poolProvider.Backends, backend)
+		poolProvider.Weights = append(poolProvider.Weights, weight)
+	}
+
+	model.Provider = poolProvider
+	return nil
+}
@@ -0,0 +1,194 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestOrderPoolBackends(t *testing.T) {
+	now := time.Now()
+	tokens := func(remaining int64, updated time.Time) poolBackendState {
+		return poolBackendState{remainingTokens: &remaining, quotaUpdated: updated}
+	}
+	// random returns the values in turn, which the weights then scale
+	random := func(values ...float64) func() float64 {
+		return func() float64 {
+			value := values[0]
+			values = values[1:]
+			return value
+		}
+	}
+
+	tests := []struct {
+		name     string
+		strategy string
+		weights  []int
+		states   []poolBackendState
+		random   func() float64
+		order    []int
+	}{
+		{
+			name:     "weighted",
+			strategy: PoolStrategyWeighted,
+			weights:  []int{1, 1, 1},
+			states:   make([]poolBackendState, 3),
+			random:   random(0.2, 0.9, 0.5),
+			order:    []int{1, 2, 0},
+		},
+		{
+			name:     "weighted favours heavier backends",
+			strategy: PoolStrategyWeighted,
+			weights:  []int{1, 9},
+			states:   make([]poolBackendState, 2),
+			random:   random(0.6, 0.1),
+			order:    []int{1, 0},
+		},
+		{
+			name:     "reserve backends come last",
+			strategy: PoolStrategyWeighted,
+			weights:  []int{0, 1},
+			states:   make([]poolBackendState, 2),
+			random:   random(0.1),
+			order:    []int{1, 0},
+		},
+		{
+			name:     "least outstanding",
+			strategy: PoolStrategyLeastOutstanding,
+			weights:  []int{1, 1, 1},
+			states:   []poolBackendState{{outstanding: 4}, {outstanding: 1}, {outstanding: 2}},
+			random:   random(0.9, 0.5, 0.1),
+			order:    []int{1, 2, 0},
+		},
+		{
+			name:     "remaining quota tries unknown quotas first",
+			strategy: PoolStrategyRemainingQuota,
+			weights:  []int{1, 1, 1, 1},
+			states:   []poolBackendState{tokens(1000, now), tokens(50000, now), {}, tokens(90000, now.Add(-2*poolQuotaTTL))},
+			random:   random(0.9, 0.8, 0.7, 0.6),
+			order:    []int{2, 3, 1, 0},
+		},
+		{
+			name:     "unhealthy backends come last",
+			strategy: PoolStrategyLeastOutstanding,
+			weights:  []int{1, 0, 1},
+			states:   []poolBackendState{{unhealthyUntil: now.Add(time.Minute)}, {}, {outstanding: 3}},
+			random:   random(0.9, 0.1),
+			order:    []int{2, 1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.order, orderPoolBackends(tt.strategy, tt.weights, tt.states, now, tt.random))
+		})
+	}
+}
+
+func TestPoolFailover(t *testing.T) {
+	rateLimited := &APIError{Source: "azure", StatusCode: http.StatusTooManyRequests, Message: "rate limited"}
+	eastus := &fakeProvider{err: rateLimited, failures: 1}
+	westus := &fakeProvider{replies: []string{"from westus"}}
+	pool := &Model{
+		Name: "gpt-4o",
+		Provider: &PoolProvider{
+			Key:      poolKey(t.Name(), "gpt-4o"),
+			Cooldown: time.Minute,
+			Backends: []*Model{{Name: "eastus", Provider: eastus}, {Name: "westus", Provider: westus}},
+			Weights:  []int{1, 0},
+		},
+		OutputSchema: &runtime.RawExtension{Raw: []byte(`{"type": "object"}`)},
+		SchemaName:   "answer",
+	}
+
+	response, err := pool.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "from westus", response.Choices[0].Message.Content)
+	assert.Equal(t, "answer", westus.schemaName, "the backend answers with the output schema of the pool")
+
+	statuses := ModelPoolStatus(t.Name(), "gpt-4o", []string{"eastus", "westus"})
+	require.Len(t, statuses, 2)
+	assert.False(t, statuses[0].Healthy)
+	assert.Contains(t, statuses[0].LastError, "rate limited")
+	assert.True(t, statuses[1].Healthy)
+	assert.Zero(t, statuses[1].OutstandingRequests)
+
+	// The rate limited backend is tried last until its cooldown ends
+	_, err = pool.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, eastus.calls)
+	assert.Equal(t, 2, westus.calls)
+}
+
+func TestPoolReportsTheBackendThatAnswered(t *testing.T) {
+	rateLimited := &APIError{Source: "azure", StatusCode: http.StatusTooManyRequests, Message: "rate limited"}
+	pool := &Model{
+		Name: "gpt-4o",
+		Provider: &PoolProvider{
+			Key: poolKey(t.Name(), "gpt-4o"),
+			Backends: []*Model{
+				{Name: "eastus", Provider: &fakeProvider{err: rateLimited, failures: 1}, Pricing: &ModelPricing{InputPer1K: 1}},
+				{Name: "westus", Provider: &fakeProvider{replies: []string{"from westus"}, usage: testUsage}, Pricing: &ModelPricing{InputPer1K: 100}},
+			},
+			Weights: []int{1, 0},
+		},
+		Pricing: &ModelPricing{InputPer1K: 10},
+	}
+
+	response, usedModel, err := pool.ChatCompletionWithFailover(context.Background(), []Message{NewUserMessage("hi")}, nil, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "westus", usedModel.Name)
+	// 10 prompt tokens priced by the backend that answered
+	assert.InDelta(t, 1.0, usedModel.TokenUsage(response.Usage).Cost, 1e-9)
+}
+
+func TestPoolStopsOnClientErrors(t *testing.T) {
+	badRequest := &APIError{Source: "openai", StatusCode: http.StatusBadRequest, Message: "invalid tool schema"}
+	other := &fakeProvider{replies: []string{"unused"}}
+	provider := &PoolProvider{
+		Key:      poolKey(t.Name(), "gpt-4o"),
+		Backends: []*Model{{Name: "first", Provider: &fakeProvider{err: badRequest, failures: 1}}, {Name: "second", Provider: other}},
+		Weights:  []int{1, 0},
+	}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil)
+	assert.ErrorIs(t, err, badRequest)
+	assert.Zero(t, other.calls)
+	assert.True(t, ModelPoolStatus(t.Name(), "gpt-4o", []string{"first"})[0].Healthy)
+}
+
+func TestPoolRecordsRateLimitHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("x-ratelimit-remaining-requests", "99")
+		w.Header().Set("x-ratelimit-remaining-tokens", "42000")
+		_, _ = w.Write([]byte(`{"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "hello"}}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	provider := &PoolProvider{
+		Key:      poolKey(t.Name(), "llama"),
+		Strategy: PoolStrategyRemainingQuota,
+		Backends: []*Model{{Name: "gpu-1", Provider: &LocalProvider{Model: "llama3.1", BaseURL: server.URL + "/v1", Server: LocalServerVLLM}}},
+		Weights:  []int{1},
+	}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("hi")}, nil)
+	require.NoError(t, err)
+
+	status := ModelPoolStatus(t.Name(), "llama", []string{"gpu-1", "gpu-2"})
+	require.Len(t, status, 2)
+	require.NotNil(t, status[0].RemainingTokens)
+	assert.Equal(t, int64(42000), *status[0].RemainingTokens)
+	assert.Equal(t, int64(99), *status[0].RemainingRequests)
+	assert.Equal(t, "gpu-2", status[1].Name)
+	assert.True(t, status[1].Healthy, "backends that have not been called are healthy")
+	assert.Nil(t, status[1].RemainingTokens)
+}
@@ -0,0 +1,64 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ModelPricing holds the parsed price per 1K tokens of a model
+type ModelPricing struct {
+	InputPer1K  float64
+	OutputPer1K float64
+}
+
+func parseModelPricing(pricing *arkv1alpha1.ModelPricing) (*ModelPricing, error) {
+	if pricing == nil {
+		return nil, nil
+	}
+
+	parsed := &ModelPricing{}
+	var err error
+	if pricing.InputPer1K != "" {
+		if parsed.InputPer1K, err = strconv.ParseFloat(pricing.InputPer1K, 64); err != nil {
+			return nil, fmt.Errorf("invalid pricing inputPer1K %q: %w", pricing.InputPer1K, err)
+		}
+	}
+	if pricing.OutputPer1K != "" {
+		if parsed.OutputPer1K, err = strconv.ParseFloat(pricing.OutputPer1K, 64); err != nil {
+			return nil, fmt.Errorf("invalid pricing outputPer1K %q: %w", pricing.OutputPer1K, err)
+		}
+	}
+	return parsed, nil
+}
+
+// Cost returns the price of a call that used the given tokens. A model without pricing costs nothing.
+func (p *ModelPricing) Cost(usage openai.CompletionUsage) float64 {
+	if p == nil {
+		return 0
+	}
+	return float64(usage.PromptTokens)/1000*p.InputPer1K + float64(usage.CompletionTokens)/1000*p.OutputPer1K
+}
+
+// TokenUsage converts the usage reported for a call to this model, adding its cost
+func (m *Model) TokenUsage(usage openai.CompletionUsage) TokenUsage {
+	return TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		Cost:             m.Pricing.Cost(usage),
+	}
+}
+
+// FormatCost renders a cost as a decimal string, rounded to a millionth of the currency unit
+func FormatCost(cost float64) string {
+	if cost <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(cost, 'f', 6, 64)
+}
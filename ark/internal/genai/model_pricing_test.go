@@ -0,0 +1,65 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestModelPricing(t *testing.T) {
+	pricing, err := parseModelPricing(&arkv1alpha1.ModelPricing{InputPer1K: "0.0025", OutputPer1K: "0.01"})
+	require.NoError(t, err)
+
+	model := &Model{Pricing: pricing}
+	usage := model.TokenUsage(openai.CompletionUsage{PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500})
+	assert.Equal(t, int64(2500), usage.TotalTokens)
+	assert.InDelta(t, 0.01, usage.Cost, 1e-9) // 2 * 0.0025 + 0.5 * 0.01
+	assert.Equal(t, "0.010000", FormatCost(usage.Cost))
+
+	// Models without pricing report tokens only
+	unpriced := (&Model{}).TokenUsage(openai.CompletionUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+	assert.Zero(t, unpriced.Cost)
+	assert.Empty(t, FormatCost(unpriced.Cost))
+
+	_, err = parseModelPricing(&arkv1alpha1.ModelPricing{InputPer1K: "cheap"})
+	assert.Error(t, err)
+}
+
+func TestTokenUsageCollectorSumsCost(t *testing.T) {
+	collector := NewTokenUsageCollector(&mockRecorder{})
+	ctx := context.Background()
+
+	collector.EmitEvent(ctx, "LLMCallComplete", OperationEvent{TokenUsage: TokenUsage{TotalTokens: 100, Cost: 0.25}})
+	collector.EmitEvent(ctx, "LLMCallComplete", OperationEvent{TokenUsage: TokenUsage{TotalTokens: 50, Cost: 0.5}})
+
+	assert.InDelta(t, 0.75, collector.GetTokenSummary().Cost, 1e-9)
+}
+
+func TestTeamUsageIsCountedOnce(t *testing.T) {
+	collector := NewTokenUsageCollector(&mockRecorder{})
+
+	agent := newTestAgent(nil, &slowExecutor{})
+	agent.Recorder = collector
+	agent.Model = &Model{
+		Name:     "priced",
+		Provider: &fakeProvider{replies: []string{"done"}, usage: testUsage},
+		Pricing:  &ModelPricing{InputPer1K: 100, OutputPer1K: 100},
+	}
+	inner := &Team{Name: "inner", Namespace: "default", Strategy: "sequential", Members: []TeamMember{agent}, Recorder: collector}
+	outer := &Team{Name: "outer", Namespace: "default", Strategy: "sequential", Members: []TeamMember{inner}, Recorder: collector}
+
+	_, err := outer.Execute(context.Background(), NewUserMessage("hi"), nil)
+	require.NoError(t, err)
+
+	// One call of 15 tokens, priced at 10/1000 * 100 + 5/1000 * 100
+	summary := collector.GetTokenSummary()
+	assert.Equal(t, int64(15), summary.TotalTokens)
+	assert.InDelta(t, 1.5, summary.Cost, 1e-9)
+}
@@ -68,6 +68,15 @@ func (t *OperationTracker) CompleteWithTokens(result string, tokenUsage TokenUsa
 	t.emitCompletion(t.operation+"Complete", "", tokenUsage)
 }
 
+// CompleteWithModel completes a model call, recording the tokens it used and the model that answered it
+func (t *OperationTracker) CompleteWithModel(result string, tokenUsage TokenUsage, modelName string) {
+	log := logf.FromContext(t.ctx)
+	if log.V(3).Enabled() && result != "" {
+		log.V(3).Info("operation response with tokens", "operation", t.operation, "name", t.name, "response", result, "tokens", tokenUsage.TotalTokens, "model", modelName)
+	}
+	t.emitCompletionWithMetadata(t.operation+"Complete", "", tokenUsage, map[string]string{modelUsedMetadataKey: modelName})
+}
+
 func (t *OperationTracker) Fail(err error) {
 	errorMsg := ""
 	if err != nil {
@@ -77,14 +86,22 @@ func (t *OperationTracker) Fail(err error) {
 }
 
 func (t *OperationTracker) CompleteWithTermination(terminationMessage string) {
+	t.CompleteWithTerminationReason("", terminationMessage)
+}
+
+// CompleteWithTerminationReason completes an operation that was stopped early, recording why it stopped
+func (t *OperationTracker) CompleteWithTerminationReason(reason, terminationMessage string) {
 	log := logf.FromContext(t.ctx)
 	if log.V(3).Enabled() && terminationMessage != "" {
-		log.V(3).Info("operation terminated", "operation", t.operation, "name", t.name, "terminationMessage", terminationMessage)
+		log.V(3).Info("operation terminated", "operation", t.operation, "name", t.name, "reason", reason, "terminationMessage", terminationMessage)
 	}
 
 	metadata := make(map[string]string)
 	maps.Copy(metadata, t.metadata)
 	metadata["terminationMessage"] = terminationMessage
+	if reason != "" {
+		metadata["terminationReason"] = reason
+	}
 
 	event := OperationEvent{
 		BaseEvent: BaseEvent{
@@ -0,0 +1,372 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultAnthropicBaseURL    = "https://api.anthropic.com"
+	defaultAnthropicAPIVersion = "2023-06-01"
+	defaultAnthropicSchemaName = "structured_output"
+)
+
+// AnthropicProvider calls the Anthropic Messages API directly and maps its responses to chat completions
+type AnthropicProvider struct {
+	Model      string
+	BaseURL    string
+	APIKey     string
+	APIVersion string
+	Properties map[string]string
+}
+
+type anthropicContent struct {
+	Type      string          `json:"type"`
+	Text      string          `json:"text,omitempty"`
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Input     json.RawMessage `json:"input,omitempty"`
+	ToolUseID string          `json:"tool_use_id,omitempty"`
+	Content   string          `json:"content,omitempty"`
+}
+
+type anthropicMessage struct {
+	Role    string             `json:"role"`
+	Content []anthropicContent `json:"content"`
+}
+
+type anthropicTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	InputSchema map[string]any `json:"input_schema"`
+}
+
+type anthropicToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+}
+
+type anthropicRequest struct {
+	Model       string               `json:"model"`
+	MaxTokens   int                  `json:"max_tokens"`
+	Temperature float64              `json:"temperature"`
+	System      string               `json:"system,omitempty"`
+	Messages    []anthropicMessage   `json:"messages"`
+	Tools       []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice  *anthropicToolChoice `json:"tool_choice,omitempty"`
+}
+
+type anthropicResponse struct {
+	ID         string             `json:"id"`
+	Model      string             `json:"model"`
+	Content    []anthropicContent `json:"content"`
+	StopReason string             `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int64 `json:"input_tokens"`
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+type anthropicError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func (ap *AnthropicProvider) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := ap.buildRequest(messages, tools)
+	response, err := ap.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return convertAnthropicResponse(response, ""), nil
+}
+
+// ChatCompletionWithSchema forces the model to answer through a tool whose input schema is the output schema. The
+// tool input becomes the content of the completion.
+func (ap *AnthropicProvider) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := ap.buildRequest(messages, tools)
+
+	tool, toolChoice, err := schemaTool(outputSchema, schemaName, len(tools))
+	if err != nil {
+		return nil, err
+	}
+	if tool == nil {
+		return ap.ChatCompletion(ctx, messages, tools)
+	}
+	request.Tools = append(request.Tools, *tool)
+	request.ToolChoice = toolChoice
+
+	response, err := ap.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return convertAnthropicResponse(response, tool.Name), nil
+}
+
+// schemaTool returns the tool through which the model gives structured output, and the tool choice that makes the
+// model call a tool. It returns no tool when there is no schema.
+func schemaTool(outputSchema *runtime.RawExtension, schemaName string, otherTools int) (*anthropicTool, *anthropicToolChoice, error) {
+	if outputSchema == nil {
+		return nil, nil, nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(outputSchema.Raw, &schema); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse output schema: %w", err)
+	}
+	if schema == nil {
+		return nil, nil, nil
+	}
+
+	if schemaName == "" {
+		schemaName = defaultAnthropicSchemaName
+	}
+	tool := &anthropicTool{
+		Name:        schemaName,
+		Description: "Respond with output that matches the input schema",
+		InputSchema: schema,
+	}
+	// With other tools available the model may still call them before it answers
+	if otherTools == 0 {
+		return tool, &anthropicToolChoice{Type: "tool", Name: schemaName}, nil
+	}
+	return tool, &anthropicToolChoice{Type: "any"}, nil
+}
+
+func (ap *AnthropicProvider) baseURL() string {
+	if ap.BaseURL == "" {
+		return defaultAnthropicBaseURL
+	}
+	return strings.TrimSuffix(ap.BaseURL, "/")
+}
+
+func (ap *AnthropicProvider) apiVersion() string {
+	if ap.APIVersion == "" {
+		return defaultAnthropicAPIVersion
+	}
+	return ap.APIVersion
+}
+
+func (ap *AnthropicProvider) send(ctx context.Context, request anthropicRequest) (anthropicResponse, error) {
+	var response anthropicResponse
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return response, fmt.Errorf("failed to serialize Anthropic request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ap.baseURL()+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return response, fmt.Errorf("failed to create Anthropic request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", ap.APIKey)
+	req.Header.Set("anthropic-version", ap.apiVersion())
+
+	resp, err := common.NewHTTPClientWithLogging(ctx).Do(req)
+	if err != nil {
+		return response, fmt.Errorf("anthropic request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response, fmt.Errorf("failed to read Anthropic response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr anthropicError
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return response, &APIError{Source: "anthropic API", StatusCode: resp.StatusCode, Message: apiErr.Error.Message}
+		}
+		return response, &APIError{Source: "anthropic API", StatusCode: resp.StatusCode, Message: string(respBody)}
+	}
+
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return response, fmt.Errorf("failed to parse Anthropic response: %w", err)
+	}
+	return response, nil
+}
+
+func (ap *AnthropicProvider) buildRequest(messages []Message, tools []openai.ChatCompletionToolParam) anthropicRequest {
+	anthropicMessages, system := convertAnthropicMessages(messages)
+
+	return anthropicRequest{
+		Model:       ap.Model,
+		MaxTokens:   getIntProperty(ap.Properties, "max_tokens", 4096),
+		Temperature: getFloatProperty(ap.Properties, "temperature", 1.0),
+		System:      system,
+		Messages:    anthropicMessages,
+		Tools:       convertAnthropicTools(tools),
+	}
+}
+
+// convertAnthropicMessages splits the system prompt from the conversation. Tool calls become tool_use blocks and
+// tool results become tool_result blocks of a user message. Consecutive messages of the same role are merged, as
+// the API expects the roles to alternate.
+func convertAnthropicMessages(messages []Message) ([]anthropicMessage, string) {
+	var system []string
+	var result []anthropicMessage
+
+	add := func(role string, content ...anthropicContent) {
+		if len(content) == 0 {
+			return
+		}
+		if last := len(result) - 1; last >= 0 && result[last].Role == role {
+			result[last].Content = append(result[last].Content, content...)
+			return
+		}
+		result = append(result, anthropicMessage{Role: role, Content: content})
+	}
+
+	for _, msg := range messages {
+		text := messageText(msg)
+		switch {
+		case msg.OfSystem != nil, msg.OfDeveloper != nil:
+			if text != "" {
+				system = append(system, text)
+			}
+		case msg.OfUser != nil:
+			if text != "" {
+				add(RoleUser, anthropicContent{Type: "text", Text: text})
+			}
+		case msg.OfAssistant != nil:
+			var content []anthropicContent
+			if text != "" {
+				content = append(content, anthropicContent{Type: "text", Text: text})
+			}
+			for _, toolCall := range msg.OfAssistant.ToolCalls {
+				input := json.RawMessage(toolCall.Function.Arguments)
+				if !json.Valid(input) {
+					input = json.RawMessage("{}")
+				}
+				content = append(content, anthropicContent{
+					Type:  "tool_use",
+					ID:    toolCall.ID,
+					Name:  toolCall.Function.Name,
+					Input: input,
+				})
+			}
+			add(RoleAssistant, content...)
+		case msg.OfTool != nil:
+			add(RoleUser, anthropicContent{
+				Type:      "tool_result",
+				ToolUseID: msg.OfTool.ToolCallID,
+				Content:   text,
+			})
+		}
+	}
+
+	return result, strings.Join(system, "\n\n")
+}
+
+func convertAnthropicTools(tools []openai.ChatCompletionToolParam) []anthropicTool {
+	var anthropicTools []anthropicTool
+	for _, tool := range tools {
+		inputSchema := map[string]any(tool.Function.Parameters)
+		if inputSchema == nil {
+			inputSchema = map[string]any{"type": "object", "properties": map[string]any{}}
+		}
+		anthropicTools = append(anthropicTools, anthropicTool{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description.Value,
+			InputSchema: inputSchema,
+		})
+	}
+	return anthropicTools
+}
+
+// convertAnthropicResponse maps a Messages API response to a chat completion. A call of the schema tool is the
+// structured answer, so its input becomes the content instead of a tool call.
+func convertAnthropicResponse(response anthropicResponse, schemaName string) *openai.ChatCompletion {
+	var text []string
+	var toolCalls []openai.ChatCompletionMessageToolCall
+
+	for _, block := range response.Content {
+		switch block.Type {
+		case "text":
+			text = append(text, block.Text)
+		case "tool_use":
+			arguments := string(block.Input)
+			if arguments == "" {
+				arguments = "{}"
+			}
+			if schemaName != "" && block.Name == schemaName {
+				text = append(text, arguments)
+				continue
+			}
+			toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
+				ID:   block.ID,
+				Type: "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      block.Name,
+					Arguments: arguments,
+				},
+			})
+		}
+	}
+
+	finishReason := "stop"
+	switch response.StopReason {
+	case "max_tokens":
+		finishReason = "length"
+	case "tool_use":
+		if len(toolCalls) > 0 {
+			finishReason = "tool_calls"
+		}
+	case "refusal", "content_filtered", "guardrail_intervened":
+		finishReason = "content_filter"
+	}
+
+	message := openai.ChatCompletionMessage{
+		Role:    "assistant",
+		Content: strings.Join(text, ""),
+	}
+	if len(toolCalls) > 0 {
+		message.ToolCalls = toolCalls
+	}
+
+	return &openai.ChatCompletion{
+		ID:     response.ID,
+		Object: "chat.completion",
+		Model:  response.Model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Index:        0,
+				Message:      message,
+				FinishReason: finishReason,
+			},
+		},
+		Usage: openai.CompletionUsage{
+			PromptTokens:     response.Usage.InputTokens,
+			CompletionTokens: response.Usage.OutputTokens,
+			TotalTokens:      response.Usage.InputTokens + response.Usage.OutputTokens,
+		},
+	}
+}
+
+func (ap *AnthropicProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl":    ap.baseURL(),
+		"apiVersion": ap.apiVersion(),
+	}
+	if ap.APIKey != "" {
+		config["apiKey"] = ap.APIKey
+	}
+	for key, value := range ap.Properties {
+		config[key] = value
+	}
+	return config
+}
@@ -0,0 +1,134 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// anthropicCall is a request received by the mock Anthropic server
+type anthropicCall struct {
+	path   string
+	header http.Header
+	body   anthropicRequest
+}
+
+// anthropicServer replies to every request with the response and keeps the last request it received
+func anthropicServer(t *testing.T, status int, response string) (*httptest.Server, *anthropicCall) {
+	call := &anthropicCall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call.path = r.URL.Path
+		call.header = r.Header.Clone()
+		call.body = anthropicRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&call.body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, call
+}
+
+func TestAnthropicChatCompletion(t *testing.T) {
+	server, call := anthropicServer(t, http.StatusOK, `{
+		"id": "msg_1",
+		"model": "claude-sonnet-4",
+		"stop_reason": "tool_use",
+		"content": [
+			{"type": "text", "text": "Let me look that up."},
+			{"type": "tool_use", "id": "toolu_2", "name": "search", "input": {"query": "weather"}}
+		],
+		"usage": {"input_tokens": 120, "output_tokens": 30}
+	}`)
+	provider := &AnthropicProvider{Model: "claude-sonnet-4", BaseURL: server.URL, APIKey: "secret", Properties: map[string]string{"max_tokens": "1024"}}
+
+	toolCall := newToolCall("toolu_1", "search")
+	toolCall.Function.Arguments = `{"query": "forecast"}`
+	messages := []Message{
+		NewSystemMessage("You are a weather assistant."),
+		NewUserMessage("What is the weather?"),
+		Message(openai.ChatCompletionMessage{Role: "assistant", ToolCalls: []openai.ChatCompletionMessageToolCall{toolCall}}.ToParam()),
+		ToolMessage("sunny", "toolu_1"),
+		NewUserMessage("And tomorrow?"),
+	}
+	tools := []openai.ChatCompletionToolParam{{
+		Function: openai.FunctionDefinitionParam{
+			Name:       "search",
+			Parameters: openai.FunctionParameters{"type": "object"},
+		},
+	}}
+
+	completion, err := provider.ChatCompletion(context.Background(), messages, tools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/v1/messages", call.path)
+	assert.Equal(t, "secret", call.header.Get("x-api-key"))
+	assert.Equal(t, "2023-06-01", call.header.Get("anthropic-version"))
+
+	assert.Equal(t, "You are a weather assistant.", call.body.System)
+	assert.Equal(t, 1024, call.body.MaxTokens)
+	require.Len(t, call.body.Tools, 1)
+	assert.Equal(t, "search", call.body.Tools[0].Name)
+
+	// The tool result and the following question share a user message
+	require.Len(t, call.body.Messages, 3)
+	assert.Equal(t, "assistant", call.body.Messages[1].Role)
+	assert.Equal(t, "tool_use", call.body.Messages[1].Content[0].Type)
+	assert.JSONEq(t, `{"query": "forecast"}`, string(call.body.Messages[1].Content[0].Input))
+	assert.Equal(t, "user", call.body.Messages[2].Role)
+	require.Len(t, call.body.Messages[2].Content, 2)
+	assert.Equal(t, "tool_result", call.body.Messages[2].Content[0].Type)
+	assert.Equal(t, "toolu_1", call.body.Messages[2].Content[0].ToolUseID)
+	assert.Equal(t, "sunny", call.body.Messages[2].Content[0].Content)
+
+	choice := completion.Choices[0]
+	assert.Equal(t, "Let me look that up.", choice.Message.Content)
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "toolu_2", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"query": "weather"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(120), completion.Usage.PromptTokens)
+	assert.Equal(t, int64(30), completion.Usage.CompletionTokens)
+	assert.Equal(t, int64(150), completion.Usage.TotalTokens)
+}
+
+func TestAnthropicChatCompletionWithSchema(t *testing.T) {
+	server, call := anthropicServer(t, http.StatusOK, `{
+		"id": "msg_1",
+		"model": "claude-sonnet-4",
+		"stop_reason": "tool_use",
+		"content": [{"type": "tool_use", "id": "toolu_1", "name": "forecast", "input": {"temperature": 21}}],
+		"usage": {"input_tokens": 50, "output_tokens": 10}
+	}`)
+	provider := &AnthropicProvider{Model: "claude-sonnet-4", BaseURL: server.URL, APIKey: "secret"}
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"temperature": {"type": "number"}}}`)}
+
+	completion, err := provider.ChatCompletionWithSchema(context.Background(), []Message{NewUserMessage("Forecast?")}, schema, "forecast", nil)
+	require.NoError(t, err)
+
+	require.NotNil(t, call.body.ToolChoice)
+	assert.Equal(t, "tool", call.body.ToolChoice.Type)
+	assert.Equal(t, "forecast", call.body.ToolChoice.Name)
+
+	choice := completion.Choices[0]
+	assert.JSONEq(t, `{"temperature": 21}`, choice.Message.Content)
+	assert.Empty(t, choice.Message.ToolCalls)
+	assert.Equal(t, "stop", choice.FinishReason)
+}
+
+func TestAnthropicChatCompletionError(t *testing.T) {
+	server, _ := anthropicServer(t, http.StatusBadRequest, `{"type": "error", "error": {"type": "invalid_request_error", "message": "max_tokens is too large"}}`)
+	provider := &AnthropicProvider{Model: "claude-sonnet-4", BaseURL: server.URL, APIKey: "secret"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, nil)
+	assert.EqualError(t, err, "anthropic API returned 400: max_tokens is too large")
+}
@@ -32,6 +32,13 @@ func (ap *AzureProvider) ChatCompletionWithSchema(ctx context.Context, messages
 	return client.Chat.Completions.New(ctx, params)
 }
 
+func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	params := buildChatCompletionParams(ap.Model, messages, tools, ap.Properties, outputSchema, schemaName)
+
+	client := ap.createClient(ctx)
+	return streamChatCompletion(ctx, client, params, onChunk)
+}
+
 func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
 	httpClient := common.NewHTTPClientWithLogging(ctx)
 
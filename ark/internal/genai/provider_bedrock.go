@@ -10,6 +10,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/document"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime/types"
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -25,43 +27,91 @@ type BedrockModel struct {
 	client          *bedrockruntime.Client
 }
 
-type bedrockMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+// bedrockAnthropicVersion is the Messages API version that Bedrock expects in the body of Anthropic requests
+const bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+// bedrockAnthropicRequest is a Messages API request as InvokeModel takes it, with the version in the body and the
+// model in the path
+type bedrockAnthropicRequest struct {
+	AnthropicVersion string               `json:"anthropic_version"`
+	MaxTokens        int                  `json:"max_tokens"`
+	Temperature      float64              `json:"temperature"`
+	System           string               `json:"system,omitempty"`
+	Messages         []anthropicMessage   `json:"messages"`
+	Tools            []anthropicTool      `json:"tools,omitempty"`
+	ToolChoice       *anthropicToolChoice `json:"tool_choice,omitempty"`
 }
 
-type bedrockRequest struct {
-	Messages         []bedrockMessage `json:"messages"`
-	MaxTokens        int              `json:"max_tokens"`
-	Temperature      float64          `json:"temperature"`
-	SystemPrompt     string           `json:"system,omitempty"`
-	AnthropicVersion string           `json:"anthropic_version,omitempty"`
-	Tools            []bedrockTool    `json:"tools,omitempty"`
+// bedrockStreamEvent is one event of the Anthropic messages stream returned by InvokeModelWithResponseStream
+type bedrockStreamEvent struct {
+	Type         string             `json:"type"`
+	Index        int                `json:"index"`
+	Message      *anthropicResponse `json:"message,omitempty"`
+	ContentBlock *anthropicContent  `json:"content_block,omitempty"`
+	Delta        struct {
+		Type        string `json:"type"`
+		Text        string `json:"text,omitempty"`
+		PartialJSON string `json:"partial_json,omitempty"`
+		StopReason  string `json:"stop_reason,omitempty"`
+	} `json:"delta"`
+	Usage struct {
+		OutputTokens int64 `json:"output_tokens"`
+	} `json:"usage"`
 }
 
-type bedrockTool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"input_schema"`
+type bedrockStreamAccumulator struct {
+	response  anthropicResponse
+	toolInput map[int]*strings.Builder
 }
 
-type bedrockResponse struct {
-	Content    []bedrockContent `json:"content"`
-	ID         string           `json:"id"`
-	Model      string           `json:"model"`
-	StopReason string           `json:"stop_reason"`
-	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
-	} `json:"usage"`
+func newBedrockStreamAccumulator() *bedrockStreamAccumulator {
+	return &bedrockStreamAccumulator{toolInput: map[int]*strings.Builder{}}
 }
 
-type bedrockContent struct {
-	Text  string                 `json:"text,omitempty"`
-	Type  string                 `json:"type"`
-	ID    string                 `json:"id,omitempty"`
-	Name  string                 `json:"name,omitempty"`
-	Input map[string]interface{} `json:"input,omitempty"`
+// add folds a stream event into the response and returns any text delta it carried
+func (a *bedrockStreamAccumulator) add(event bedrockStreamEvent) string {
+	switch event.Type {
+	case "message_start":
+		if event.Message != nil {
+			a.response.ID = event.Message.ID
+			a.response.Model = event.Message.Model
+			a.response.Usage.InputTokens = event.Message.Usage.InputTokens
+		}
+	case "content_block_start":
+		for len(a.response.Content) <= event.Index {
+			a.response.Content = append(a.response.Content, anthropicContent{})
+		}
+		if event.ContentBlock != nil {
+			a.response.Content[event.Index] = *event.ContentBlock
+		}
+	case "content_block_delta":
+		if event.Index >= len(a.response.Content) {
+			return ""
+		}
+		switch event.Delta.Type {
+		case "text_delta":
+			a.response.Content[event.Index].Text += event.Delta.Text
+			return event.Delta.Text
+		case "input_json_delta":
+			if a.toolInput[event.Index] == nil {
+				a.toolInput[event.Index] = &strings.Builder{}
+			}
+			a.toolInput[event.Index].WriteString(event.Delta.PartialJSON)
+		}
+	case "message_delta":
+		a.response.StopReason = event.Delta.StopReason
+		a.response.Usage.OutputTokens = event.Usage.OutputTokens
+	}
+	return ""
+}
+
+func (a *bedrockStreamAccumulator) finish() anthropicResponse {
+	for index, input := range a.toolInput {
+		if json.Valid([]byte(input.String())) {
+			a.response.Content[index].Input = json.RawMessage(input.String())
+		}
+	}
+	return a.response
 }
 
 func NewBedrockModel(model, region, accessKeyID, secretAccessKey, sessionToken, modelArn string, properties map[string]string) *BedrockModel {
@@ -100,31 +150,32 @@ func (bm *BedrockModel) initClient(ctx context.Context) error {
 }
 
 func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return bm.ChatCompletionWithSchema(ctx, messages, nil, "", tools)
+}
+
+// ChatCompletionWithSchema forces the model to answer through a tool whose input schema is the output schema, as
+// with the Anthropic provider. Claude models are called with the Messages API and other models with the Converse API.
+func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	if err := bm.initClient(ctx); err != nil {
 		return nil, err
 	}
 
-	bedrockMessages, systemPrompt := bm.convertMessages(messages)
-	bedrockTools := bm.convertTools(tools)
-
-	request := bm.buildRequest(bedrockMessages, systemPrompt, bedrockTools)
+	request, schemaName, err := bm.buildRequest(messages, outputSchema, schemaName, tools)
+	if err != nil {
+		return nil, err
+	}
 
-	if strings.Contains(strings.ToLower(bm.Model), "claude") {
-		request.AnthropicVersion = "bedrock-2023-05-31"
+	if !bm.isAnthropicModel() {
+		return bm.converse(ctx, request, schemaName)
 	}
 
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return nil, err
-	}
-
-	modelID := bm.Model
-	if bm.ModelArn != "" {
-		modelID = bm.ModelArn
+		return nil, fmt.Errorf("failed to serialize Bedrock request: %w", err)
 	}
 
 	input := &bedrockruntime.InvokeModelInput{
-		ModelId:     aws.String(modelID),
+		ModelId:     aws.String(bm.modelID()),
 		Body:        requestBody,
 		ContentType: aws.String("application/json"),
 		Accept:      aws.String("application/json"),
@@ -135,179 +186,243 @@ func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message,
 		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
 	}
 
-	var response bedrockResponse
+	var response anthropicResponse
 	if err := json.Unmarshal(result.Body, &response); err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse Bedrock response: %w", err)
 	}
 
-	return bm.convertResponse(response), nil
+	return convertAnthropicResponse(response, schemaName), nil
 }
 
-func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
-	return bm.ChatCompletion(ctx, messages, tools)
-}
+// ChatCompletionStream streams Anthropic models through InvokeModelWithResponseStream.
+// Other model families go through the Converse API and are returned as a single chunk.
+func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	if !bm.isAnthropicModel() {
+		completion, err := bm.ChatCompletionWithSchema(ctx, messages, outputSchema, schemaName, tools)
+		if err != nil {
+			return nil, err
+		}
+		onChunk(completionAsChunk(completion))
+		return completion, nil
+	}
 
-func (bm *BedrockModel) buildRequest(messages []bedrockMessage, systemPrompt string, tools []bedrockTool) bedrockRequest {
-	temperature := getFloatProperty(bm.Properties, "temperature", 1.0)
-	maxTokens := getIntProperty(bm.Properties, "max_tokens", 4096)
+	if err := bm.initClient(ctx); err != nil {
+		return nil, err
+	}
+
+	request, schemaName, err := bm.buildRequest(messages, outputSchema, schemaName, tools)
+	if err != nil {
+		return nil, err
+	}
 
-	return bedrockRequest{
-		Messages:     messages,
-		MaxTokens:    maxTokens,
-		Temperature:  temperature,
-		SystemPrompt: systemPrompt,
-		Tools:        tools,
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize Bedrock request: %w", err)
 	}
-}
 
-func (bm *BedrockModel) convertMessages(messages []Message) ([]bedrockMessage, string) {
-	var bedrockMessages []bedrockMessage
-	var systemPrompt string
+	input := &bedrockruntime.InvokeModelWithResponseStreamInput{
+		ModelId:     aws.String(bm.modelID()),
+		Body:        requestBody,
+		ContentType: aws.String("application/json"),
+		Accept:      aws.String("application/json"),
+	}
 
-	for _, msg := range messages {
-		content, role := extractMessageContent(msg)
-		if content == "" {
+	result, err := bm.client.InvokeModelWithResponseStream(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to invoke Bedrock model with response stream: %w", err)
+	}
+
+	stream := result.GetStream()
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	acc := newBedrockStreamAccumulator()
+	for event := range stream.Events() {
+		chunk, ok := event.(*types.ResponseStreamMemberChunk)
+		if !ok {
 			continue
 		}
 
-		switch role {
-		case RoleSystem:
-			systemPrompt = content
-		case RoleUser, RoleAssistant, RoleTool:
-			msgRole := role
-			if role == RoleTool {
-				msgRole = "user"
-			}
-			bedrockMessages = append(bedrockMessages, bedrockMessage{
-				Role:    msgRole,
-				Content: content,
-			})
+		var streamEvent bedrockStreamEvent
+		if err := json.Unmarshal(chunk.Value.Bytes, &streamEvent); err != nil {
+			return nil, fmt.Errorf("failed to parse Bedrock stream event: %w", err)
 		}
-	}
 
-	return bedrockMessages, systemPrompt
-}
-
-func (bm *BedrockModel) convertResponse(response bedrockResponse) *openai.ChatCompletion {
-	var content string
-	var toolCalls []openai.ChatCompletionMessageToolCall
-
-	for _, c := range response.Content {
-		switch c.Type {
-		case "text":
-			content = c.Text
-		case "tool_use":
-			toolCall := openai.ChatCompletionMessageToolCall{
-				ID:   c.ID,
-				Type: "function",
-				Function: openai.ChatCompletionMessageToolCallFunction{
-					Name:      c.Name,
-					Arguments: mustMarshalJSON(c.Input),
+		if text := acc.add(streamEvent); text != "" {
+			onChunk(openai.ChatCompletionChunk{
+				ID:    acc.response.ID,
+				Model: acc.response.Model,
+				Choices: []openai.ChatCompletionChunkChoice{
+					{Delta: openai.ChatCompletionChunkChoiceDelta{Content: text}},
 				},
-			}
-			toolCalls = append(toolCalls, toolCall)
+			})
 		}
 	}
 
-	finishReason := "stop"
-	switch response.StopReason {
-	case "max_tokens":
-		finishReason = "length"
-	case "tool_use":
-		finishReason = "tool_calls"
+	if err := stream.Err(); err != nil {
+		return nil, fmt.Errorf("bedrock response stream failed: %w", err)
 	}
 
-	message := openai.ChatCompletionMessage{
-		Role:    "assistant",
-		Content: content,
+	return convertAnthropicResponse(acc.finish(), schemaName), nil
+}
+
+func (bm *BedrockModel) isAnthropicModel() bool {
+	return strings.Contains(strings.ToLower(bm.Model), "claude")
+}
+
+func (bm *BedrockModel) modelID() string {
+	if bm.ModelArn != "" {
+		return bm.ModelArn
 	}
+	return bm.Model
+}
 
-	if len(toolCalls) > 0 {
-		message.ToolCalls = toolCalls
+// buildRequest builds the request in the Messages API format, which the Converse API request is mapped from. It
+// returns the name of the schema tool, which is empty when there is no output schema.
+func (bm *BedrockModel) buildRequest(messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (bedrockAnthropicRequest, string, error) {
+	anthropicMessages, system := convertAnthropicMessages(messages)
+
+	request := bedrockAnthropicRequest{
+		AnthropicVersion: bedrockAnthropicVersion,
+		MaxTokens:        getIntProperty(bm.Properties, "max_tokens", 4096),
+		Temperature:      getFloatProperty(bm.Properties, "temperature", 1.0),
+		System:           system,
+		Messages:         anthropicMessages,
+		Tools:            convertAnthropicTools(tools),
 	}
 
-	return &openai.ChatCompletion{
-		ID:     response.ID,
-		Object: "chat.completion",
-		Model:  response.Model,
-		Choices: []openai.ChatCompletionChoice{
-			{
-				Index:        0,
-				Message:      message,
-				FinishReason: finishReason,
-			},
-		},
-		Usage: openai.CompletionUsage{
-			PromptTokens:     int64(response.Usage.InputTokens),
-			CompletionTokens: int64(response.Usage.OutputTokens),
-			TotalTokens:      int64(response.Usage.InputTokens + response.Usage.OutputTokens),
-		},
+	tool, toolChoice, err := schemaTool(outputSchema, schemaName, len(tools))
+	if err != nil || tool == nil {
+		return request, "", err
 	}
+	request.Tools = append(request.Tools, *tool)
+	request.ToolChoice = toolChoice
+	return request, tool.Name, nil
 }
 
-func mustMarshalJSON(v interface{}) string {
-	if v == nil {
-		return "{}"
-	}
-	data, err := json.Marshal(v)
+func (bm *BedrockModel) converse(ctx context.Context, request bedrockAnthropicRequest, schemaName string) (*openai.ChatCompletion, error) {
+	input, err := bm.converseInput(request)
 	if err != nil {
-		return "{}"
+		return nil, err
 	}
-	return string(data)
-}
 
-func (bm *BedrockModel) convertTools(tools []openai.ChatCompletionToolParam) []bedrockTool {
-	var bedrockTools []bedrockTool
+	output, err := bm.client.Converse(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call Bedrock Converse API: %w", err)
+	}
 
-	for _, tool := range tools {
-		if tool.Type == "function" {
-			bedrockTool := bedrockTool{
-				Name: tool.Function.Name,
-			}
+	response, err := convertConverseOutput(output)
+	if err != nil {
+		return nil, err
+	}
+	response.Model = bm.Model
+	return convertAnthropicResponse(response, schemaName), nil
+}
 
-			if tool.Function.Description.Value != "" {
-				bedrockTool.Description = tool.Function.Description.Value
-			}
+// converseInput maps a Messages API request to the Converse API, which gives the same tool use blocks to every
+// model family that supports tools
+func (bm *BedrockModel) converseInput(request bedrockAnthropicRequest) (*bedrockruntime.ConverseInput, error) {
+	input := &bedrockruntime.ConverseInput{
+		ModelId: aws.String(bm.modelID()),
+		InferenceConfig: &types.InferenceConfiguration{
+			MaxTokens:   aws.Int32(int32(request.MaxTokens)),
+			Temperature: aws.Float32(float32(request.Temperature)),
+		},
+	}
+	if request.System != "" {
+		input.System = []types.SystemContentBlock{&types.SystemContentBlockMemberText{Value: request.System}}
+	}
 
-			if tool.Function.Parameters != nil {
-				bedrockTool.InputSchema = map[string]interface{}(tool.Function.Parameters)
+	for _, message := range request.Messages {
+		converseMessage := types.Message{Role: types.ConversationRole(message.Role)}
+		for _, block := range message.Content {
+			switch block.Type {
+			case "text":
+				converseMessage.Content = append(converseMessage.Content, &types.ContentBlockMemberText{Value: block.Text})
+			case "tool_use":
+				var arguments any
+				if err := json.Unmarshal(block.Input, &arguments); err != nil {
+					return nil, fmt.Errorf("failed to parse arguments of tool call %s: %w", block.ID, err)
+				}
+				converseMessage.Content = append(converseMessage.Content, &types.ContentBlockMemberToolUse{
+					Value: types.ToolUseBlock{
+						ToolUseId: aws.String(block.ID),
+						Name:      aws.String(block.Name),
+						Input:     document.NewLazyDocument(arguments),
+					},
+				})
+			case "tool_result":
+				converseMessage.Content = append(converseMessage.Content, &types.ContentBlockMemberToolResult{
+					Value: types.ToolResultBlock{
+						ToolUseId: aws.String(block.ToolUseID),
+						Content:   []types.ToolResultContentBlock{&types.ToolResultContentBlockMemberText{Value: block.Content}},
+					},
+				})
 			}
-
-			bedrockTools = append(bedrockTools, bedrockTool)
 		}
+		input.Messages = append(input.Messages, converseMessage)
 	}
 
-	return bedrockTools
-}
-
-func extractMessageContent(msg Message) (string, string) {
-	openaiMsg := openai.ChatCompletionMessageParamUnion(msg)
-
-	if systemMsg := openaiMsg.OfSystem; systemMsg != nil {
-		if content := systemMsg.Content.OfString; content.Value != "" {
-			return content.Value, "system"
+	if len(request.Tools) == 0 {
+		return input, nil
+	}
+	input.ToolConfig = &types.ToolConfiguration{}
+	for _, tool := range request.Tools {
+		spec := types.ToolSpecification{
+			Name:        aws.String(tool.Name),
+			InputSchema: &types.ToolInputSchemaMemberJson{Value: document.NewLazyDocument(tool.InputSchema)},
 		}
+		if tool.Description != "" {
+			spec.Description = aws.String(tool.Description)
+		}
+		input.ToolConfig.Tools = append(input.ToolConfig.Tools, &types.ToolMemberToolSpec{Value: spec})
 	}
-
-	if userMsg := openaiMsg.OfUser; userMsg != nil {
-		if content := userMsg.Content.OfString; content.Value != "" {
-			return content.Value, "user"
+	if request.ToolChoice != nil {
+		switch request.ToolChoice.Type {
+		case "tool":
+			input.ToolConfig.ToolChoice = &types.ToolChoiceMemberTool{Value: types.SpecificToolChoice{Name: aws.String(request.ToolChoice.Name)}}
+		case "any":
+			input.ToolConfig.ToolChoice = &types.ToolChoiceMemberAny{}
 		}
 	}
+	return input, nil
+}
 
-	if assistantMsg := openaiMsg.OfAssistant; assistantMsg != nil {
-		if content := assistantMsg.Content.OfString; content.Value != "" {
-			return content.Value, "assistant"
-		}
+// convertConverseOutput maps a Converse API response to the Messages API format, so that both APIs share the
+// conversion to a chat completion
+func convertConverseOutput(output *bedrockruntime.ConverseOutput) (anthropicResponse, error) {
+	response := anthropicResponse{StopReason: string(output.StopReason)}
+	if output.Usage != nil {
+		response.Usage.InputTokens = int64(aws.ToInt32(output.Usage.InputTokens))
+		response.Usage.OutputTokens = int64(aws.ToInt32(output.Usage.OutputTokens))
 	}
 
-	if toolMsg := openaiMsg.OfTool; toolMsg != nil {
-		if content := toolMsg.Content.OfString; content.Value != "" {
-			return content.Value, "tool"
+	message, ok := output.Output.(*types.ConverseOutputMemberMessage)
+	if !ok {
+		return response, nil
+	}
+	for _, block := range message.Value.Content {
+		switch block := block.(type) {
+		case *types.ContentBlockMemberText:
+			response.Content = append(response.Content, anthropicContent{Type: "text", Text: block.Value})
+		case *types.ContentBlockMemberToolUse:
+			content := anthropicContent{
+				Type: "tool_use",
+				ID:   aws.ToString(block.Value.ToolUseId),
+				Name: aws.ToString(block.Value.Name),
+			}
+			if block.Value.Input != nil {
+				input, err := block.Value.Input.MarshalSmithyDocument()
+				if err != nil {
+					return response, fmt.Errorf("failed to read arguments of tool call %s: %w", content.ID, err)
+				}
+				content.Input = input
+			}
+			response.Content = append(response.Content, content)
 		}
 	}
-
-	return "", ""
+	return response, nil
 }
 
 func (bm *BedrockModel) BuildConfig() map[string]any {
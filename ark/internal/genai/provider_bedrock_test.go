@@ -0,0 +1,208 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// bedrockCall is a request received by the mock Bedrock server
+type bedrockCall struct {
+	path string
+	body []byte
+}
+
+// bedrockModel returns a model whose client calls a mock Bedrock server, which replies to every request with the
+// response and keeps the last request it received
+func bedrockModel(t *testing.T, model, response string) (*BedrockModel, *bedrockCall) {
+	call := &bedrockCall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call.path = r.URL.Path
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		call.body = body
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	bm := NewBedrockModel(model, "us-east-1", "", "", "", "", map[string]string{"max_tokens": "1024"})
+	bm.client = bedrockruntime.New(bedrockruntime.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		Credentials:  credentials.NewStaticCredentialsProvider("key", "secret", ""),
+	})
+	return bm, call
+}
+
+// bedrockConversation has two tool calls in one turn and their results
+func bedrockConversation() ([]Message, []openai.ChatCompletionToolParam) {
+	search := newToolCall("tool-1", "search")
+	search.Function.Arguments = `{"query": "forecast"}`
+	calculate := newToolCall("tool-2", "calculate")
+	calculate.Function.Arguments = `{"expression": "21 * 1.8 + 32"}`
+
+	messages := []Message{
+		NewSystemMessage("You are a weather assistant."),
+		NewUserMessage("What is the weather in Fahrenheit?"),
+		Message(openai.ChatCompletionMessage{Role: "assistant", ToolCalls: []openai.ChatCompletionMessageToolCall{search, calculate}}.ToParam()),
+		ToolMessage("21C and sunny", "tool-1"),
+		ToolMessage("69.8", "tool-2"),
+	}
+	return messages, localTools("search", "calculate")
+}
+
+func TestBedrockChatCompletionAnthropic(t *testing.T) {
+	bm, call := bedrockModel(t, "anthropic.claude-3-5-sonnet-20241022-v2:0", `{
+		"id": "msg-1",
+		"model": "claude-3-5-sonnet",
+		"stop_reason": "tool_use",
+		"content": [{"type": "tool_use", "id": "tool-3", "name": "search", "input": {"query": "tomorrow"}}],
+		"usage": {"input_tokens": 100, "output_tokens": 20}
+	}`)
+	messages, tools := bedrockConversation()
+
+	completion, err := bm.ChatCompletion(context.Background(), messages, tools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/model/anthropic.claude-3-5-sonnet-20241022-v2:0/invoke", call.path)
+	var request bedrockAnthropicRequest
+	require.NoError(t, json.Unmarshal(call.body, &request))
+	assert.Equal(t, bedrockAnthropicVersion, request.AnthropicVersion)
+	assert.Equal(t, "You are a weather assistant.", request.System)
+	assert.Equal(t, 1024, request.MaxTokens)
+	require.Len(t, request.Tools, 2)
+
+	require.Len(t, request.Messages, 3)
+	assert.Equal(t, []string{"tool-1", "tool-2"}, []string{request.Messages[1].Content[0].ID, request.Messages[1].Content[1].ID})
+	results := request.Messages[2].Content
+	require.Len(t, results, 2)
+	assert.Equal(t, "tool_result", results[0].Type)
+	assert.Equal(t, "tool-1", results[0].ToolUseID)
+	assert.Equal(t, "21C and sunny", results[0].Content)
+	assert.Equal(t, "tool-2", results[1].ToolUseID)
+
+	choice := completion.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "tool-3", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"query": "tomorrow"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(120), completion.Usage.TotalTokens)
+}
+
+func TestBedrockChatCompletionWithSchemaAnthropic(t *testing.T) {
+	bm, call := bedrockModel(t, "anthropic.claude-3-5-sonnet-20241022-v2:0", `{
+		"id": "msg-1",
+		"stop_reason": "tool_use",
+		"content": [{"type": "tool_use", "id": "tool-1", "name": "forecast", "input": {"temperature": 21}}],
+		"usage": {"input_tokens": 50, "output_tokens": 10}
+	}`)
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"temperature": {"type": "number"}}}`)}
+
+	completion, err := bm.ChatCompletionWithSchema(context.Background(), []Message{NewUserMessage("Forecast?")}, schema, "forecast", nil)
+	require.NoError(t, err)
+
+	var request bedrockAnthropicRequest
+	require.NoError(t, json.Unmarshal(call.body, &request))
+	require.NotNil(t, request.ToolChoice)
+	assert.Equal(t, "tool", request.ToolChoice.Type)
+	assert.Equal(t, "forecast", request.ToolChoice.Name)
+
+	choice := completion.Choices[0]
+	assert.JSONEq(t, `{"temperature": 21}`, choice.Message.Content)
+	assert.Empty(t, choice.Message.ToolCalls)
+	assert.Equal(t, "stop", choice.FinishReason)
+}
+
+func TestBedrockChatCompletionConverse(t *testing.T) {
+	bm, call := bedrockModel(t, "meta.llama3-1-70b-instruct-v1:0", `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"text": "Looking it up."},
+			{"toolUse": {"toolUseId": "tool-3", "name": "search", "input": {"query": "tomorrow"}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 100, "outputTokens": 20, "totalTokens": 120},
+		"metrics": {"latencyMs": 300}
+	}`)
+	messages, tools := bedrockConversation()
+
+	completion, err := bm.ChatCompletion(context.Background(), messages, tools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/model/meta.llama3-1-70b-instruct-v1:0/converse", call.path)
+	var request struct {
+		System   []map[string]any `json:"system"`
+		Messages []struct {
+			Role    string           `json:"role"`
+			Content []map[string]any `json:"content"`
+		} `json:"messages"`
+		InferenceConfig map[string]any `json:"inferenceConfig"`
+		ToolConfig      struct {
+			Tools      []map[string]any `json:"tools"`
+			ToolChoice map[string]any   `json:"toolChoice"`
+		} `json:"toolConfig"`
+	}
+	require.NoError(t, json.Unmarshal(call.body, &request))
+	assert.Equal(t, "You are a weather assistant.", request.System[0]["text"])
+	assert.EqualValues(t, 1024, request.InferenceConfig["maxTokens"])
+	assert.Len(t, request.ToolConfig.Tools, 2)
+	assert.Nil(t, request.ToolConfig.ToolChoice)
+
+	require.Len(t, request.Messages, 3)
+	assert.Equal(t, map[string]any{"toolUseId": "tool-2", "name": "calculate", "input": map[string]any{"expression": "21 * 1.8 + 32"}}, request.Messages[1].Content[1]["toolUse"])
+	assert.Equal(t, "user", request.Messages[2].Role)
+	require.Len(t, request.Messages[2].Content, 2)
+	assert.Equal(t, map[string]any{"toolUseId": "tool-1", "content": []any{map[string]any{"text": "21C and sunny"}}}, request.Messages[2].Content[0]["toolResult"])
+
+	choice := completion.Choices[0]
+	assert.Equal(t, "Looking it up.", choice.Message.Content)
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "tool-3", choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"query": "tomorrow"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "meta.llama3-1-70b-instruct-v1:0", completion.Model)
+	assert.Equal(t, int64(120), completion.Usage.TotalTokens)
+}
+
+func TestBedrockChatCompletionWithSchemaConverse(t *testing.T) {
+	bm, call := bedrockModel(t, "amazon.nova-pro-v1:0", `{
+		"output": {"message": {"role": "assistant", "content": [
+			{"toolUse": {"toolUseId": "tool-1", "name": "forecast", "input": {"temperature": 21}}}
+		]}},
+		"stopReason": "tool_use",
+		"usage": {"inputTokens": 50, "outputTokens": 10, "totalTokens": 60},
+		"metrics": {"latencyMs": 300}
+	}`)
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"temperature": {"type": "number"}}}`)}
+
+	completion, err := bm.ChatCompletionWithSchema(context.Background(), []Message{NewUserMessage("Forecast?")}, schema, "forecast", localTools("search"))
+	require.NoError(t, err)
+
+	var request struct {
+		ToolConfig struct {
+			Tools      []map[string]any `json:"tools"`
+			ToolChoice map[string]any   `json:"toolChoice"`
+		} `json:"toolConfig"`
+	}
+	require.NoError(t, json.Unmarshal(call.body, &request))
+	assert.Len(t, request.ToolConfig.Tools, 2)
+	assert.Contains(t, request.ToolConfig.ToolChoice, "any", "the model may call other tools before it answers")
+
+	choice := completion.Choices[0]
+	assert.JSONEq(t, `{"temperature": 21}`, choice.Message.Content)
+	assert.Empty(t, choice.Message.ToolCalls)
+	assert.Equal(t, "stop", choice.FinishReason)
+}
@@ -0,0 +1,399 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultGeminiBaseURL  = "https://generativelanguage.googleapis.com/v1beta"
+	defaultVertexLocation = "us-central1"
+)
+
+// GeminiProvider calls the generateContent API of Google Gemini, or of Vertex AI when a project is set, and maps
+// its responses to chat completions
+type GeminiProvider struct {
+	Model      string
+	BaseURL    string
+	APIKey     string
+	Project    string
+	Location   string
+	Properties map[string]string
+}
+
+type geminiFunctionCall struct {
+	ID   string          `json:"id,omitempty"`
+	Name string          `json:"name"`
+	Args json.RawMessage `json:"args,omitempty"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string          `json:"id,omitempty"`
+	Name     string          `json:"name"`
+	Response json.RawMessage `json:"response"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	Thought          bool                    `json:"thought,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiFunctionDeclaration struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type geminiTool struct {
+	FunctionDeclarations []geminiFunctionDeclaration `json:"functionDeclarations"`
+}
+
+type geminiGenerationConfig struct {
+	Temperature        float64        `json:"temperature"`
+	MaxOutputTokens    int            `json:"maxOutputTokens,omitempty"`
+	ResponseMimeType   string         `json:"responseMimeType,omitempty"`
+	ResponseJSONSchema map[string]any `json:"responseJsonSchema,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent         `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent        `json:"contents"`
+	Tools             []geminiTool           `json:"tools,omitempty"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiResponse struct {
+	ResponseID   string `json:"responseId"`
+	ModelVersion string `json:"modelVersion"`
+	Candidates   []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata struct {
+		PromptTokenCount     int64 `json:"promptTokenCount"`
+		CandidatesTokenCount int64 `json:"candidatesTokenCount"`
+		TotalTokenCount      int64 `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+type geminiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	} `json:"error"`
+}
+
+func (gp *GeminiProvider) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	response, err := gp.send(ctx, gp.buildRequest(messages, tools))
+	if err != nil {
+		return nil, err
+	}
+	return gp.convertResponse(response)
+}
+
+func (gp *GeminiProvider) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	request := gp.buildRequest(messages, tools)
+
+	if outputSchema != nil {
+		var schema map[string]any
+		if err := json.Unmarshal(outputSchema.Raw, &schema); err != nil {
+			return nil, fmt.Errorf("failed to parse output schema: %w", err)
+		}
+		request.GenerationConfig.ResponseMimeType = "application/json"
+		request.GenerationConfig.ResponseJSONSchema = schema
+	}
+
+	response, err := gp.send(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	return gp.convertResponse(response)
+}
+
+func (gp *GeminiProvider) isVertex() bool {
+	return gp.Project != ""
+}
+
+func (gp *GeminiProvider) location() string {
+	if gp.Location == "" {
+		return defaultVertexLocation
+	}
+	return gp.Location
+}
+
+func (gp *GeminiProvider) baseURL() string {
+	switch {
+	case gp.BaseURL != "":
+		return strings.TrimSuffix(gp.BaseURL, "/")
+	case gp.isVertex():
+		return fmt.Sprintf("https://%s-aiplatform.googleapis.com/v1", gp.location())
+	default:
+		return defaultGeminiBaseURL
+	}
+}
+
+func (gp *GeminiProvider) endpoint() string {
+	if gp.isVertex() {
+		return fmt.Sprintf("%s/projects/%s/locations/%s/publishers/google/models/%s:generateContent", gp.baseURL(), gp.Project, gp.location(), gp.Model)
+	}
+	return fmt.Sprintf("%s/models/%s:generateContent", gp.baseURL(), gp.Model)
+}
+
+func (gp *GeminiProvider) send(ctx context.Context, request geminiRequest) (geminiResponse, error) {
+	var response geminiResponse
+
+	body, err := json.Marshal(request)
+	if err != nil {
+		return response, fmt.Errorf("failed to serialize Gemini request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, gp.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return response, fmt.Errorf("failed to create Gemini request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Vertex AI authenticates with an OAuth access token, the Gemini API with an API key
+	if gp.isVertex() {
+		req.Header.Set("Authorization", "Bearer "+gp.APIKey)
+	} else {
+		req.Header.Set("x-goog-api-key", gp.APIKey)
+	}
+
+	resp, err := common.NewHTTPClientWithLogging(ctx).Do(req)
+	if err != nil {
+		return response, fmt.Errorf("gemini request failed: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return response, fmt.Errorf("failed to read Gemini response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr geminiError
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error.Message != "" {
+			return response, &APIError{Source: "gemini API", StatusCode: resp.StatusCode, Message: apiErr.Error.Message}
+		}
+		return response, &APIError{Source: "gemini API", StatusCode: resp.StatusCode, Message: string(respBody)}
+	}
+
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return response, fmt.Errorf("failed to parse Gemini response: %w", err)
+	}
+	return response, nil
+}
+
+func (gp *GeminiProvider) buildRequest(messages []Message, tools []openai.ChatCompletionToolParam) geminiRequest {
+	contents, system := convertGeminiMessages(messages)
+
+	request := geminiRequest{
+		Contents: contents,
+		GenerationConfig: geminiGenerationConfig{
+			Temperature:     getFloatProperty(gp.Properties, "temperature", 1.0),
+			MaxOutputTokens: getIntProperty(gp.Properties, "max_tokens", 0),
+		},
+	}
+	if system != "" {
+		request.SystemInstruction = &geminiContent{Parts: []geminiPart{{Text: system}}}
+	}
+	if declarations := convertGeminiTools(tools); len(declarations) > 0 {
+		request.Tools = []geminiTool{{FunctionDeclarations: declarations}}
+	}
+	return request
+}
+
+// convertGeminiMessages splits the system instruction from the conversation. Assistant messages take the model role
+// and tool results become function responses of a user turn. Function responses are matched to their call by name,
+// which Gemini requires, so the names are looked up from the tool calls that came before.
+func convertGeminiMessages(messages []Message) ([]geminiContent, string) {
+	var system []string
+	var contents []geminiContent
+	toolNames := map[string]string{}
+
+	add := func(role string, parts ...geminiPart) {
+		if len(parts) == 0 {
+			return
+		}
+		if last := len(contents) - 1; last >= 0 && contents[last].Role == role {
+			contents[last].Parts = append(contents[last].Parts, parts...)
+			return
+		}
+		contents = append(contents, geminiContent{Role: role, Parts: parts})
+	}
+
+	for _, msg := range messages {
+		text := messageText(msg)
+		switch {
+		case msg.OfSystem != nil, msg.OfDeveloper != nil:
+			if text != "" {
+				system = append(system, text)
+			}
+		case msg.OfUser != nil:
+			if text != "" {
+				add("user", geminiPart{Text: text})
+			}
+		case msg.OfAssistant != nil:
+			var parts []geminiPart
+			if text != "" {
+				parts = append(parts, geminiPart{Text: text})
+			}
+			for _, toolCall := range msg.OfAssistant.ToolCalls {
+				toolNames[toolCall.ID] = toolCall.Function.Name
+				args := json.RawMessage(toolCall.Function.Arguments)
+				if !json.Valid(args) {
+					args = json.RawMessage("{}")
+				}
+				parts = append(parts, geminiPart{FunctionCall: &geminiFunctionCall{
+					ID:   toolCall.ID,
+					Name: toolCall.Function.Name,
+					Args: args,
+				}})
+			}
+			add("model", parts...)
+		case msg.OfTool != nil:
+			add("user", geminiPart{FunctionResponse: &geminiFunctionResponse{
+				ID:       msg.OfTool.ToolCallID,
+				Name:     toolNames[msg.OfTool.ToolCallID],
+				Response: geminiFunctionResult(text),
+			}})
+		}
+	}
+
+	return contents, strings.Join(system, "\n\n")
+}
+
+// geminiFunctionResult wraps a tool result in the object Gemini expects, unless it already is a JSON object
+func geminiFunctionResult(text string) json.RawMessage {
+	var object map[string]any
+	if err := json.Unmarshal([]byte(text), &object); err == nil {
+		return json.RawMessage(text)
+	}
+	wrapped, _ := json.Marshal(map[string]string{"content": text})
+	return wrapped
+}
+
+func convertGeminiTools(tools []openai.ChatCompletionToolParam) []geminiFunctionDeclaration {
+	var declarations []geminiFunctionDeclaration
+	for _, tool := range tools {
+		declarations = append(declarations, geminiFunctionDeclaration{
+			Name:        tool.Function.Name,
+			Description: tool.Function.Description.Value,
+			Parameters:  map[string]any(tool.Function.Parameters),
+		})
+	}
+	return declarations
+}
+
+func (gp *GeminiProvider) convertResponse(response geminiResponse) (*openai.ChatCompletion, error) {
+	if len(response.Candidates) == 0 {
+		return nil, fmt.Errorf("gemini returned no candidates")
+	}
+	candidate := response.Candidates[0]
+
+	var text []string
+	var toolCalls []openai.ChatCompletionMessageToolCall
+	for _, part := range candidate.Content.Parts {
+		if part.FunctionCall != nil {
+			arguments := string(part.FunctionCall.Args)
+			if arguments == "" {
+				arguments = "{}"
+			}
+			// Gemini only returns call IDs on some endpoints, and tool results are matched to calls by ID
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = newToolCallID()
+			}
+			toolCalls = append(toolCalls, openai.ChatCompletionMessageToolCall{
+				ID:   id,
+				Type: "function",
+				Function: openai.ChatCompletionMessageToolCallFunction{
+					Name:      part.FunctionCall.Name,
+					Arguments: arguments,
+				},
+			})
+			continue
+		}
+		// Thought summaries of thinking models are not part of the answer
+		if !part.Thought {
+			text = append(text, part.Text)
+		}
+	}
+
+	finishReason := "stop"
+	switch {
+	case len(toolCalls) > 0:
+		finishReason = "tool_calls"
+	case candidate.FinishReason == "MAX_TOKENS":
+		finishReason = "length"
+	case candidate.FinishReason == "SAFETY", candidate.FinishReason == "RECITATION", candidate.FinishReason == "PROHIBITED_CONTENT":
+		finishReason = "content_filter"
+	}
+
+	message := openai.ChatCompletionMessage{
+		Role:    "assistant",
+		Content: strings.Join(text, ""),
+	}
+	if len(toolCalls) > 0 {
+		message.ToolCalls = toolCalls
+	}
+
+	model := response.ModelVersion
+	if model == "" {
+		model = gp.Model
+	}
+
+	return &openai.ChatCompletion{
+		ID:     response.ResponseID,
+		Object: "chat.completion",
+		Model:  model,
+		Choices: []openai.ChatCompletionChoice{
+			{
+				Index:        0,
+				Message:      message,
+				FinishReason: finishReason,
+			},
+		},
+		Usage: openai.CompletionUsage{
+			PromptTokens:     response.UsageMetadata.PromptTokenCount,
+			CompletionTokens: response.UsageMetadata.CandidatesTokenCount,
+			TotalTokens:      response.UsageMetadata.TotalTokenCount,
+		},
+	}, nil
+}
+
+func (gp *GeminiProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl": gp.baseURL(),
+	}
+	if gp.APIKey != "" {
+		config["apiKey"] = gp.APIKey
+	}
+	if gp.isVertex() {
+		config["project"] = gp.Project
+		config["location"] = gp.location()
+	}
+	for key, value := range gp.Properties {
+		config[key] = value
+	}
+	return config
+}
@@ -0,0 +1,135 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// geminiCall is a request received by the mock Gemini server
+type geminiCall struct {
+	path   string
+	header http.Header
+	body   geminiRequest
+}
+
+// geminiServer replies to every request with the response and keeps the last request it received
+func geminiServer(t *testing.T, status int, response string) (*httptest.Server, *geminiCall) {
+	call := &geminiCall{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		call.path = r.URL.Path
+		call.header = r.Header.Clone()
+		call.body = geminiRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&call.body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, call
+}
+
+func TestGeminiChatCompletion(t *testing.T) {
+	server, call := geminiServer(t, http.StatusOK, `{
+		"responseId": "resp-1",
+		"modelVersion": "gemini-2.5-flash",
+		"candidates": [{
+			"content": {"role": "model", "parts": [
+				{"text": "thinking about it", "thought": true},
+				{"text": "Let me look that up."},
+				{"functionCall": {"name": "search", "args": {"query": "weather"}}}
+			]},
+			"finishReason": "STOP"
+		}],
+		"usageMetadata": {"promptTokenCount": 120, "candidatesTokenCount": 30, "totalTokenCount": 150}
+	}`)
+	provider := &GeminiProvider{Model: "gemini-2.5-flash", BaseURL: server.URL, APIKey: "secret"}
+
+	toolCall := newToolCall("call-1", "search")
+	toolCall.Function.Arguments = `{"query": "forecast"}`
+	messages := []Message{
+		NewSystemMessage("You are a weather assistant."),
+		NewUserMessage("What is the weather?"),
+		Message(openai.ChatCompletionMessage{Role: "assistant", ToolCalls: []openai.ChatCompletionMessageToolCall{toolCall}}.ToParam()),
+		ToolMessage("sunny", "call-1"),
+	}
+	tools := []openai.ChatCompletionToolParam{{
+		Function: openai.FunctionDefinitionParam{
+			Name:       "search",
+			Parameters: openai.FunctionParameters{"type": "object"},
+		},
+	}}
+
+	completion, err := provider.ChatCompletion(context.Background(), messages, tools)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/models/gemini-2.5-flash:generateContent", call.path)
+	assert.Equal(t, "secret", call.header.Get("x-goog-api-key"))
+
+	require.NotNil(t, call.body.SystemInstruction)
+	assert.Equal(t, "You are a weather assistant.", call.body.SystemInstruction.Parts[0].Text)
+	require.Len(t, call.body.Tools, 1)
+	assert.Equal(t, "search", call.body.Tools[0].FunctionDeclarations[0].Name)
+
+	require.Len(t, call.body.Contents, 3)
+	assert.Equal(t, "model", call.body.Contents[1].Role)
+	assert.JSONEq(t, `{"query": "forecast"}`, string(call.body.Contents[1].Parts[0].FunctionCall.Args))
+	response := call.body.Contents[2].Parts[0].FunctionResponse
+	require.NotNil(t, response)
+	assert.Equal(t, "search", response.Name, "the function response takes the name of its call")
+	assert.JSONEq(t, `{"content": "sunny"}`, string(response.Response))
+
+	choice := completion.Choices[0]
+	assert.Equal(t, "Let me look that up.", choice.Message.Content)
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.NotEmpty(t, choice.Message.ToolCalls[0].ID)
+	assert.JSONEq(t, `{"query": "weather"}`, choice.Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(120), completion.Usage.PromptTokens)
+	assert.Equal(t, int64(30), completion.Usage.CompletionTokens)
+	assert.Equal(t, int64(150), completion.Usage.TotalTokens)
+}
+
+func TestGeminiChatCompletionWithSchema(t *testing.T) {
+	server, call := geminiServer(t, http.StatusOK, `{
+		"candidates": [{"content": {"role": "model", "parts": [{"text": "{\"temperature\": 21}"}]}, "finishReason": "STOP"}]
+	}`)
+	provider := &GeminiProvider{Model: "gemini-2.5-flash", BaseURL: server.URL, APIKey: "secret"}
+	schema := &runtime.RawExtension{Raw: []byte(`{"type": "object", "properties": {"temperature": {"type": "number"}}}`)}
+
+	completion, err := provider.ChatCompletionWithSchema(context.Background(), []Message{NewUserMessage("Forecast?")}, schema, "forecast", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/json", call.body.GenerationConfig.ResponseMimeType)
+	assert.Equal(t, "object", call.body.GenerationConfig.ResponseJSONSchema["type"])
+	assert.JSONEq(t, `{"temperature": 21}`, completion.Choices[0].Message.Content)
+	assert.Equal(t, "gemini-2.5-flash", completion.Model)
+}
+
+func TestGeminiVertexEndpoint(t *testing.T) {
+	server, call := geminiServer(t, http.StatusOK, `{"candidates": [{"content": {"parts": [{"text": "Hi"}]}, "finishReason": "STOP"}]}`)
+	provider := &GeminiProvider{Model: "gemini-2.5-pro", BaseURL: server.URL, APIKey: "token", Project: "research"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "/projects/research/locations/us-central1/publishers/google/models/gemini-2.5-pro:generateContent", call.path)
+	assert.Equal(t, "Bearer token", call.header.Get("Authorization"))
+}
+
+func TestGeminiChatCompletionError(t *testing.T) {
+	server, _ := geminiServer(t, http.StatusBadRequest, `{"error": {"code": 400, "message": "API key not valid", "status": "INVALID_ARGUMENT"}}`)
+	provider := &GeminiProvider{Model: "gemini-2.5-flash", BaseURL: server.URL, APIKey: "secret"}
+
+	_, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, nil)
+	assert.EqualError(t, err, "gemini API returned 400: API key not valid")
+}
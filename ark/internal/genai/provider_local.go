@@ -0,0 +1,366 @@
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	"mckinsey.com/ark/internal/common"
+)
+
+// Local server kinds
+const (
+	LocalServerOllama   = "ollama"
+	LocalServerVLLM     = "vllm"
+	LocalServerLlamaCpp = "llamacpp"
+)
+
+// defaultLocalTimeout allows for slow inference on CPUs and for loading the model on the first call
+const defaultLocalTimeout = 10 * time.Minute
+
+const mistralToolCallsPrefix = "[TOOL_CALLS]"
+
+var (
+	hermesToolCallPattern = regexp.MustCompile(`(?s)<tool_call>\s*(.*?)\s*</tool_call>`)
+	markdownFencePattern  = regexp.MustCompile("(?s)^```(?:json)?\\s*(.*?)\\s*```$")
+)
+
+// LocalProvider calls the OpenAI compatible API of a local Ollama, vLLM or llama.cpp server. The responses are
+// parsed leniently, as local models often return tool calls in the text of the message.
+type LocalProvider struct {
+	Model      string
+	BaseURL    string
+	APIKey     string
+	Server     string
+	Timeout    time.Duration
+	Properties map[string]string
+}
+
+type localToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type localResponse struct {
+	ID      string `json:"id"`
+	Model   string `json:"model"`
+	Created int64  `json:"created"`
+	Choices []struct {
+		Index        int64  `json:"index"`
+		FinishReason string `json:"finish_reason"`
+		Message      struct {
+			Content   string          `json:"content"`
+			ToolCalls []localToolCall `json:"tool_calls"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int64 `json:"prompt_tokens"`
+		CompletionTokens int64 `json:"completion_tokens"`
+		TotalTokens      int64 `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// textToolCall is a tool call written in the text of a message
+type textToolCall struct {
+	Name       string          `json:"name"`
+	Arguments  json.RawMessage `json:"arguments"`
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+func (lp *LocalProvider) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	params := buildChatCompletionParams(lp.Model, messages, tools, lp.Properties, nil, "")
+	return lp.send(ctx, params, tools)
+}
+
+func (lp *LocalProvider) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	params := buildChatCompletionParams(lp.Model, messages, tools, lp.Properties, outputSchema, schemaName)
+	return lp.send(ctx, params, tools)
+}
+
+func (lp *LocalProvider) server() string {
+	if lp.Server == "" {
+		return LocalServerOllama
+	}
+	return lp.Server
+}
+
+func (lp *LocalProvider) timeout() time.Duration {
+	if lp.Timeout <= 0 {
+		return defaultLocalTimeout
+	}
+	return lp.Timeout
+}
+
+// baseURL is the address of the server, without the /v1 of its OpenAI compatible API
+func (lp *LocalProvider) baseURL() string {
+	return strings.TrimSuffix(strings.TrimSuffix(lp.BaseURL, "/"), "/v1")
+}
+
+func (lp *LocalProvider) do(ctx context.Context, method, path string, body any) ([]byte, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, lp.baseURL()+path, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if lp.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+lp.APIKey)
+	}
+
+	resp, err := common.NewHTTPClientWithLogging(ctx).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s server request failed: %w", lp.server(), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s server response: %w", lp.server(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &APIError{Source: lp.server() + " server", StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+	}
+	return respBody, nil
+}
+
+func (lp *LocalProvider) send(ctx context.Context, params openai.ChatCompletionNewParams, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	ctx, cancel := context.WithTimeout(ctx, lp.timeout())
+	defer cancel()
+
+	respBody, err := lp.do(ctx, http.MethodPost, "/v1/chat/completions", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response localResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse %s server response: %w", lp.server(), err)
+	}
+	return convertLocalResponse(response, tools), nil
+}
+
+// CheckAvailability reports whether the model is pulled on an Ollama server, or served by a vLLM or llama.cpp
+// server. An error means the server could not be asked.
+func (lp *LocalProvider) CheckAvailability(ctx context.Context) (bool, error) {
+	if lp.server() == LocalServerOllama {
+		respBody, err := lp.do(ctx, http.MethodGet, "/api/tags", nil)
+		if err != nil {
+			return false, err
+		}
+		var tags struct {
+			Models []struct {
+				Name  string `json:"name"`
+				Model string `json:"model"`
+			} `json:"models"`
+		}
+		if err := json.Unmarshal(respBody, &tags); err != nil {
+			return false, fmt.Errorf("failed to parse ollama models: %w", err)
+		}
+		for _, model := range tags.Models {
+			// Ollama adds the latest tag to models pulled without one
+			if model.Name == lp.Model || model.Model == lp.Model || model.Name == lp.Model+":latest" {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	respBody, err := lp.do(ctx, http.MethodGet, "/v1/models", nil)
+	if err != nil {
+		return false, err
+	}
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &models); err != nil {
+		return false, fmt.Errorf("failed to parse %s models: %w", lp.server(), err)
+	}
+	// llama.cpp serves a single model whatever name is asked for
+	if lp.server() == LocalServerLlamaCpp {
+		return len(models.Data) > 0, nil
+	}
+	for _, model := range models.Data {
+		if model.ID == lp.Model {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// convertLocalResponse maps the response to a chat completion. Arguments returned as JSON objects are turned into
+// strings, missing call IDs are generated, and tool calls written in the text are extracted when tools were offered.
+func convertLocalResponse(response localResponse, tools []openai.ChatCompletionToolParam) *openai.ChatCompletion {
+	completion := &openai.ChatCompletion{
+		ID:      response.ID,
+		Object:  "chat.completion",
+		Model:   response.Model,
+		Created: response.Created,
+		Usage: openai.CompletionUsage{
+			PromptTokens:     response.Usage.PromptTokens,
+			CompletionTokens: response.Usage.CompletionTokens,
+			TotalTokens:      response.Usage.TotalTokens,
+		},
+	}
+
+	for _, choice := range response.Choices {
+		content := choice.Message.Content
+		var toolCalls []openai.ChatCompletionMessageToolCall
+		for _, call := range choice.Message.ToolCalls {
+			toolCalls = append(toolCalls, localToolCallOf(call.ID, call.Function.Name, call.Function.Arguments))
+		}
+		if len(toolCalls) == 0 && len(tools) > 0 {
+			toolCalls, content = parseTextToolCalls(content, tools)
+		}
+
+		finishReason := choice.FinishReason
+		if finishReason == "" {
+			finishReason = "stop"
+		}
+		message := openai.ChatCompletionMessage{Role: "assistant", Content: content}
+		if len(toolCalls) > 0 {
+			message.ToolCalls = toolCalls
+			finishReason = "tool_calls"
+		}
+
+		completion.Choices = append(completion.Choices, openai.ChatCompletionChoice{
+			Index:        choice.Index,
+			Message:      message,
+			FinishReason: finishReason,
+		})
+	}
+
+	return completion
+}
+
+func localToolCallOf(id, name string, arguments json.RawMessage) openai.ChatCompletionMessageToolCall {
+	if id == "" {
+		id = newToolCallID()
+	}
+	return openai.ChatCompletionMessageToolCall{
+		ID:   id,
+		Type: "function",
+		Function: openai.ChatCompletionMessageToolCallFunction{
+			Name:      name,
+			Arguments: normalizeArguments(arguments),
+		},
+	}
+}
+
+// normalizeArguments returns tool call arguments as a JSON string, whether they were sent as a string or an object
+func normalizeArguments(arguments json.RawMessage) string {
+	trimmed := bytes.TrimSpace(arguments)
+	if len(trimmed) == 0 || string(trimmed) == "null" {
+		return "{}"
+	}
+	var encoded string
+	if err := json.Unmarshal(trimmed, &encoded); err == nil {
+		if encoded == "" {
+			return "{}"
+		}
+		return encoded
+	}
+	return string(trimmed)
+}
+
+// parseTextToolCalls extracts tool calls that a model wrote in its message instead of returning them as tool calls.
+// It recognizes <tool_call> tags, the [TOOL_CALLS] prefix and a message that is only the JSON of one or more calls.
+// Calls of tools that were not offered leave the content as it is, as it is more likely an answer than a call.
+func parseTextToolCalls(content string, tools []openai.ChatCompletionToolParam) ([]openai.ChatCompletionMessageToolCall, string) {
+	trimmed := strings.TrimSpace(content)
+	if trimmed == "" {
+		return nil, content
+	}
+
+	var candidates []string
+	remaining := ""
+	switch {
+	case hermesToolCallPattern.MatchString(trimmed):
+		for _, match := range hermesToolCallPattern.FindAllStringSubmatch(trimmed, -1) {
+			candidates = append(candidates, match[1])
+		}
+		remaining = strings.TrimSpace(hermesToolCallPattern.ReplaceAllString(trimmed, ""))
+	case strings.HasPrefix(trimmed, mistralToolCallsPrefix):
+		candidates = []string{strings.TrimSpace(strings.TrimPrefix(trimmed, mistralToolCallsPrefix))}
+	default:
+		if match := markdownFencePattern.FindStringSubmatch(trimmed); match != nil {
+			trimmed = match[1]
+		}
+		candidates = []string{trimmed}
+	}
+
+	offered := map[string]bool{}
+	for _, tool := range tools {
+		offered[tool.Function.Name] = true
+	}
+
+	var toolCalls []openai.ChatCompletionMessageToolCall
+	for _, candidate := range candidates {
+		var calls []textToolCall
+		if strings.HasPrefix(candidate, "[") {
+			if err := json.Unmarshal([]byte(candidate), &calls); err != nil {
+				return nil, content
+			}
+		} else {
+			var call textToolCall
+			if err := json.Unmarshal([]byte(candidate), &call); err != nil {
+				return nil, content
+			}
+			calls = []textToolCall{call}
+		}
+
+		for _, call := range calls {
+			if !offered[call.Name] {
+				return nil, content
+			}
+			arguments := call.Arguments
+			if len(arguments) == 0 {
+				arguments = call.Parameters
+			}
+			toolCalls = append(toolCalls, localToolCallOf("", call.Name, arguments))
+		}
+	}
+
+	if len(toolCalls) == 0 {
+		return nil, content
+	}
+	return toolCalls, remaining
+}
+
+func (lp *LocalProvider) BuildConfig() map[string]any {
+	config := map[string]any{
+		"baseUrl": lp.baseURL(),
+		"server":  lp.server(),
+		"timeout": lp.timeout().String(),
+	}
+	if lp.APIKey != "" {
+		config["apiKey"] = lp.APIKey
+	}
+	for key, value := range lp.Properties {
+		config[key] = value
+	}
+	return config
+}
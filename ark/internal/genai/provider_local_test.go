@@ -0,0 +1,140 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func localTools(names ...string) []openai.ChatCompletionToolParam {
+	var tools []openai.ChatCompletionToolParam
+	for _, name := range names {
+		tools = append(tools, openai.ChatCompletionToolParam{
+			Function: openai.FunctionDefinitionParam{Name: name, Parameters: openai.FunctionParameters{"type": "object"}},
+		})
+	}
+	return tools
+}
+
+// localServer serves the responses by path
+func localServer(t *testing.T, responses map[string]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseTextToolCalls(t *testing.T) {
+	tools := localTools("search", "calculate")
+
+	tests := []struct {
+		name      string
+		content   string
+		calls     []string
+		arguments string
+		remaining string
+	}{
+		{"hermes tags", "Searching now.\n<tool_call>\n{\"name\": \"search\", \"arguments\": {\"query\": \"weather\"}}\n</tool_call>", []string{"search"}, `{"query": "weather"}`, "Searching now."},
+		{"mistral prefix", `[TOOL_CALLS] [{"name": "search", "arguments": {"query": "weather"}}, {"name": "calculate", "arguments": {}}]`, []string{"search", "calculate"}, `{"query": "weather"}`, ""},
+		{"bare json with parameters", `{"name": "search", "parameters": {"query": "weather"}}`, []string{"search"}, `{"query": "weather"}`, ""},
+		{"fenced json", "```json\n{\"name\": \"search\", \"arguments\": \"{\\\"query\\\": \\\"weather\\\"}\"}\n```", []string{"search"}, `{"query": "weather"}`, ""},
+		{"tool not offered", `{"name": "delete_everything", "arguments": {}}`, nil, "", `{"name": "delete_everything", "arguments": {}}`},
+		{"plain answer", "It is sunny.", nil, "", "It is sunny."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, remaining := parseTextToolCalls(tt.content, tools)
+			require.Len(t, calls, len(tt.calls))
+			for i, name := range tt.calls {
+				assert.Equal(t, name, calls[i].Function.Name)
+				assert.NotEmpty(t, calls[i].ID)
+			}
+			if len(calls) > 0 {
+				assert.JSONEq(t, tt.arguments, calls[0].Function.Arguments)
+			}
+			assert.Equal(t, tt.remaining, remaining)
+		})
+	}
+}
+
+func TestLocalChatCompletion(t *testing.T) {
+	server := localServer(t, map[string]string{
+		"/v1/chat/completions": `{
+			"id": "chatcmpl-1",
+			"model": "qwen2.5:7b",
+			"choices": [{"index": 0, "finish_reason": "stop", "message": {"role": "assistant", "content": "<tool_call>{\"name\": \"search\", \"arguments\": {\"query\": \"weather\"}}</tool_call>"}}],
+			"usage": {"prompt_tokens": 40, "completion_tokens": 12, "total_tokens": 52}
+		}`,
+	})
+	provider := &LocalProvider{Model: "qwen2.5:7b", BaseURL: server.URL + "/v1"}
+
+	completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("What is the weather?")}, localTools("search"))
+	require.NoError(t, err)
+
+	choice := completion.Choices[0]
+	assert.Equal(t, "tool_calls", choice.FinishReason)
+	require.Len(t, choice.Message.ToolCalls, 1)
+	assert.Equal(t, "search", choice.Message.ToolCalls[0].Function.Name)
+	assert.Empty(t, choice.Message.Content)
+	assert.Equal(t, int64(52), completion.Usage.TotalTokens)
+}
+
+func TestLocalChatCompletionObjectArguments(t *testing.T) {
+	server := localServer(t, map[string]string{
+		"/v1/chat/completions": `{
+			"choices": [{"index": 0, "finish_reason": "tool_calls", "message": {"role": "assistant", "content": "", "tool_calls": [
+				{"type": "function", "function": {"name": "search", "arguments": {"query": "weather"}}}
+			]}}]
+		}`,
+	})
+	provider := &LocalProvider{Model: "llama3.1", BaseURL: server.URL}
+
+	completion, err := provider.ChatCompletion(context.Background(), []Message{NewUserMessage("What is the weather?")}, localTools("search"))
+	require.NoError(t, err)
+
+	toolCall := completion.Choices[0].Message.ToolCalls[0]
+	assert.NotEmpty(t, toolCall.ID)
+	assert.JSONEq(t, `{"query": "weather"}`, toolCall.Function.Arguments)
+}
+
+func TestLocalCheckAvailability(t *testing.T) {
+	server := localServer(t, map[string]string{
+		"/api/tags":  `{"models": [{"name": "llama3.1:latest", "model": "llama3.1:latest"}]}`,
+		"/v1/models": `{"data": [{"id": "Qwen/Qwen2.5-7B-Instruct"}]}`,
+	})
+
+	tests := []struct {
+		server    string
+		model     string
+		available bool
+	}{
+		{LocalServerOllama, "llama3.1", true},
+		{LocalServerOllama, "llama3.1:latest", true},
+		{LocalServerOllama, "mistral", false},
+		{LocalServerVLLM, "Qwen/Qwen2.5-7B-Instruct", true},
+		{LocalServerVLLM, "llama3.1", false},
+		{LocalServerLlamaCpp, "anything", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.server+"/"+tt.model, func(t *testing.T) {
+			provider := &LocalProvider{Model: tt.model, BaseURL: server.URL, Server: tt.server}
+			available, err := provider.CheckAvailability(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.available, available)
+		})
+	}
+}
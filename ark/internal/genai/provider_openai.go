@@ -30,6 +30,13 @@ func (op *OpenAIProvider) ChatCompletionWithSchema(ctx context.Context, messages
 	return client.Chat.Completions.New(ctx, params)
 }
 
+func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	params := buildChatCompletionParams(op.Model, messages, tools, op.Properties, outputSchema, schemaName)
+
+	client := op.createClient(ctx)
+	return streamChatCompletion(ctx, client, params, onChunk)
+}
+
 func (op *OpenAIProvider) createClient(ctx context.Context) openai.Client {
 	httpClient := common.NewHTTPClientWithLogging(ctx)
 
@@ -0,0 +1,320 @@
+package genai
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+// Strategies for picking the backend of a pooled call
+const (
+	PoolStrategyWeighted         = "weighted"
+	PoolStrategyLeastOutstanding = "least-outstanding"
+	PoolStrategyRemainingQuota   = "remaining-quota"
+)
+
+// defaultPoolCooldown matches the default cooldown of the Model CRD
+const defaultPoolCooldown = 30 * time.Second
+
+// poolQuotaTTL is how long the remaining quota of a backend is trusted. Rate limit windows are usually a minute, so
+// older readings say little about the quota left now.
+const poolQuotaTTL = time.Minute
+
+// Rate limit headers of the OpenAI and Azure OpenAI APIs, and of the Anthropic API
+var (
+	remainingRequestsHeaders = []string{"x-ratelimit-remaining-requests", "anthropic-ratelimit-requests-remaining"}
+	remainingTokensHeaders   = []string{"x-ratelimit-remaining-tokens", "anthropic-ratelimit-tokens-remaining"}
+)
+
+// poolBackendState is what the calls made through a pool have seen of one of its backends. It is kept per process
+// and shared by every load of the pool, as models are loaded again for each query.
+type poolBackendState struct {
+	outstanding       int64
+	remainingRequests *int64
+	remainingTokens   *int64
+	quotaUpdated      time.Time
+	unhealthyUntil    time.Time
+	lastError         string
+}
+
+var (
+	poolStatesMu sync.Mutex
+	poolStates   = map[string]*poolBackendState{}
+)
+
+// PoolProvider spreads calls across the backend models of a pool. Backends are tried in the order picked by the
+// strategy, healthy backends first, and a call that fails with a rate limit, a server error or a connection failure
+// moves on to the next backend. The retry policies and fallbacks of the backends are not used.
+type PoolProvider struct {
+	// Key identifies the state of the pool, which outlives the provider
+	Key      string
+	Strategy string
+	Cooldown time.Duration
+	Backends []*Model
+	// Weights of the backends, in the order of Backends
+	Weights []int
+}
+
+// answeringBackendKey is the context key of the model a pooled call reports its answering backend to
+type answeringBackendKey struct{}
+
+// withAnsweringBackend returns a context whose pooled calls set answered to the backend that answered them
+func withAnsweringBackend(ctx context.Context, answered **Model) context.Context {
+	return context.WithValue(ctx, answeringBackendKey{}, answered)
+}
+
+func poolKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func poolBackendKey(pool, backend string) string {
+	return pool + "/" + backend
+}
+
+// poolBackend returns the state of a backend, creating it on first use. poolStatesMu must be held.
+func poolBackend(key string) *poolBackendState {
+	state, ok := poolStates[key]
+	if !ok {
+		state = &poolBackendState{}
+		poolStates[key] = state
+	}
+	return state
+}
+
+func (p *PoolProvider) ChatCompletion(ctx context.Context, messages []Message, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.complete(ctx, messages, nil, "", tools, nil)
+}
+
+func (p *PoolProvider) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.complete(ctx, messages, outputSchema, schemaName, tools, nil)
+}
+
+// ChatCompletionStream streams from backends that can stream. A call is not moved to another backend once it has
+// streamed chunks.
+func (p *PoolProvider) ChatCompletionStream(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	return p.complete(ctx, messages, outputSchema, schemaName, tools, onChunk)
+}
+
+func (p *PoolProvider) complete(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	if len(p.Backends) == 0 {
+		return nil, fmt.Errorf("model pool %s has no backends", p.Key)
+	}
+
+	var err error
+	for _, i := range p.order() {
+		backend := p.Backends[i]
+		backend.OutputSchema = outputSchema
+		backend.SchemaName = schemaName
+
+		streamed := false
+		var handler func(openai.ChatCompletionChunk)
+		if onChunk != nil {
+			handler = func(chunk openai.ChatCompletionChunk) {
+				streamed = true
+				onChunk(chunk)
+			}
+		}
+
+		var response *openai.ChatCompletion
+		response, err = p.call(ctx, backend, messages, tools, handler)
+		if err == nil {
+			if answered, ok := ctx.Value(answeringBackendKey{}).(**Model); ok {
+				*answered = backend
+			}
+			return response, nil
+		}
+		if streamed || !isRetryableModelError(ctx, err) {
+			return nil, err
+		}
+		logf.FromContext(ctx).Info("model pool backend failed", "pool", p.Key, "backend", backend.Name, "error", err.Error())
+	}
+	return nil, err
+}
+
+// call makes a call to a backend, counting it as outstanding while it runs and recording the rate limits and the
+// health the backend responds with
+func (p *PoolProvider) call(ctx context.Context, backend *Model, messages []Message, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	poolStatesMu.Lock()
+	state := poolBackend(poolBackendKey(p.Key, backend.Name))
+	state.outstanding++
+	poolStatesMu.Unlock()
+
+	ctx = common.WithResponseObserver(ctx, func(resp *http.Response) {
+		recordQuota(state, resp.Header, time.Now())
+	})
+	response, err := backend.complete(ctx, messages, tools, onChunk)
+
+	poolStatesMu.Lock()
+	defer poolStatesMu.Unlock()
+	state.outstanding--
+	switch {
+	case err == nil:
+		state.unhealthyUntil = time.Time{}
+		state.lastError = ""
+	case isRetryableModelError(ctx, err):
+		state.unhealthyUntil = time.Now().Add(p.Cooldown)
+		state.lastError = err.Error()
+	default:
+		state.lastError = err.Error()
+	}
+	return response, err
+}
+
+// order returns the indexes of the backends in the order they are tried
+func (p *PoolProvider) order() []int {
+	poolStatesMu.Lock()
+	states := make([]poolBackendState, len(p.Backends))
+	for i, backend := range p.Backends {
+		states[i] = *poolBackend(poolBackendKey(p.Key, backend.Name))
+	}
+	poolStatesMu.Unlock()
+
+	return orderPoolBackends(p.Strategy, p.Weights, states, time.Now(), rand.Float64)
+}
+
+// orderPoolBackends orders the backends by the strategy, starting from a random order weighted by the weights so that
+// backends that rank the same share the calls. Healthy backends come first, and backends with a weight of 0 come
+// after the others that are as healthy.
+func orderPoolBackends(strategy string, weights []int, states []poolBackendState, now time.Time, random func() float64) []int {
+	order := make([]int, len(weights))
+	keys := make([]float64, len(weights))
+	for i, weight := range weights {
+		order[i] = i
+		if weight > 0 {
+			// Weighted random sampling without replacement (Efraimidis and Spirakis)
+			keys[i] = math.Pow(random(), 1/float64(weight))
+		}
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(keys[b], keys[a])
+	})
+
+	switch strategy {
+	case PoolStrategyLeastOutstanding:
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(states[a].outstanding, states[b].outstanding)
+		})
+	case PoolStrategyRemainingQuota:
+		// Backends whose quota is not known are tried first, so that it gets known
+		quota := func(i int) int64 {
+			if states[i].remainingTokens == nil || now.Sub(states[i].quotaUpdated) > poolQuotaTTL {
+				return math.MaxInt64
+			}
+			return *states[i].remainingTokens
+		}
+		slices.SortStableFunc(order, func(a, b int) int {
+			return cmp.Compare(quota(b), quota(a))
+		})
+	}
+
+	rank := func(i int) int {
+		r := 0
+		if now.Before(states[i].unhealthyUntil) {
+			r += 2
+		}
+		if weights[i] <= 0 {
+			r++
+		}
+		return r
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+	return order
+}
+
+// recordQuota records the remaining quota of a backend from the rate limit headers of a response
+func recordQuota(state *poolBackendState, header http.Header, now time.Time) {
+	requests := headerInt(header, remainingRequestsHeaders)
+	tokens := headerInt(header, remainingTokensHeaders)
+	if requests == nil && tokens == nil {
+		return
+	}
+
+	poolStatesMu.Lock()
+	defer poolStatesMu.Unlock()
+	state.remainingRequests = requests
+	state.remainingTokens = tokens
+	state.quotaUpdated = now
+}
+
+func headerInt(header http.Header, names []string) *int64 {
+	for _, name := range names {
+		if value, err := strconv.ParseInt(header.Get(name), 10, 64); err == nil {
+			return &value
+		}
+	}
+	return nil
+}
+
+// ModelPoolStatus returns the health of the backends of a pool, as seen by the calls made through the pool in this
+// process. Backends that have not been called yet are healthy.
+func ModelPoolStatus(namespace, name string, backends []string) []arkv1alpha1.ModelBackendStatus {
+	poolStatesMu.Lock()
+	defer poolStatesMu.Unlock()
+
+	now := time.Now()
+	statuses := make([]arkv1alpha1.ModelBackendStatus, 0, len(backends))
+	for _, backend := range backends {
+		status := arkv1alpha1.ModelBackendStatus{Name: backend, Healthy: true}
+		if state, ok := poolStates[poolBackendKey(poolKey(namespace, name), backend)]; ok {
+			status.Healthy = !now.Before(state.unhealthyUntil)
+			status.OutstandingRequests = state.outstanding
+			status.LastError = state.lastError
+			if now.Sub(state.quotaUpdated) <= poolQuotaTTL {
+				status.RemainingRequests = copyInt64(state.remainingRequests)
+				status.RemainingTokens = copyInt64(state.remainingTokens)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func copyInt64(value *int64) *int64 {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func (p *PoolProvider) BuildConfig() map[string]any {
+	backends := make([]map[string]any, 0, len(p.Backends))
+	for i, backend := range p.Backends {
+		config := map[string]any{
+			"name":   backend.Name,
+			"model":  backend.Model,
+			"type":   backend.Type,
+			"weight": p.Weights[i],
+		}
+		if configProvider, ok := backend.Provider.(ConfigProvider); ok {
+			config["config"] = configProvider.BuildConfig()
+		}
+		backends = append(backends, config)
+	}
+
+	strategy := p.Strategy
+	if strategy == "" {
+		strategy = PoolStrategyWeighted
+	}
+	return map[string]any{
+		"strategy": strategy,
+		"cooldown": p.Cooldown.String(),
+		"backends": backends,
+	}
+}
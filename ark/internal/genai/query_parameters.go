@@ -4,16 +4,18 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"text/template"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
-func ResolveQueryInput(ctx context.Context, k8sClient client.Client, namespace, input string, parameters []arkv1alpha1.Parameter) (string, error) {
+func ResolveQueryInput(ctx context.Context, k8sClient client.Client, namespace, input string, parameters []arkv1alpha1.QueryParameter) (string, error) {
 	if len(parameters) == 0 {
 		return input, nil
 	}
@@ -36,7 +38,7 @@ func ResolveQueryInput(ctx context.Context, k8sClient client.Client, namespace,
 	return buf.String(), nil
 }
 
-func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namespace string, parameters []arkv1alpha1.Parameter) (map[string]string, error) {
+func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namespace string, parameters []arkv1alpha1.QueryParameter) (map[string]string, error) {
 	templateData := make(map[string]string)
 
 	for _, param := range parameters {
@@ -59,7 +61,23 @@ func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namesp
 	return templateData, nil
 }
 
-func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespace string, valueFrom *arkv1alpha1.ValueFromSource) (string, error) {
+// AsQueryParameters returns parameters that cannot reference queries in the form query parameters are resolved in
+func AsQueryParameters(parameters []arkv1alpha1.Parameter) []arkv1alpha1.QueryParameter {
+	if parameters == nil {
+		return nil
+	}
+	queryParameters := make([]arkv1alpha1.QueryParameter, 0, len(parameters))
+	for _, param := range parameters {
+		queryParam := arkv1alpha1.QueryParameter{Name: param.Name, Value: param.Value}
+		if param.ValueFrom != nil {
+			queryParam.ValueFrom = &arkv1alpha1.QueryParameterSource{ValueFromSource: *param.ValueFrom}
+		}
+		queryParameters = append(queryParameters, queryParam)
+	}
+	return queryParameters
+}
+
+func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespace string, valueFrom *arkv1alpha1.QueryParameterSource) (string, error) {
 	if valueFrom.ConfigMapKeyRef != nil {
 		configMap := &corev1.ConfigMap{}
 		key := types.NamespacedName{Name: valueFrom.ConfigMapKeyRef.Name, Namespace: namespace}
@@ -88,9 +106,123 @@ func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespa
 		return string(value), nil
 	}
 
+	if valueFrom.QueryRef != nil {
+		return resolveQueryResponse(ctx, k8sClient, namespace, valueFrom.QueryRef)
+	}
+
 	return "", fmt.Errorf("no supported valueFrom source specified")
 }
 
+// resolveQueryResponse returns the content of a response of a finished query. The response target may be
+// omitted when the query has a single response.
+func resolveQueryResponse(ctx context.Context, k8sClient client.Client, namespace string, queryRef *arkv1alpha1.QueryRef) (string, error) {
+	query := &arkv1alpha1.Query{}
+	key := QueryParameterRef(namespace, queryRef)
+	if err := k8sClient.Get(ctx, key, query); err != nil {
+		return "", fmt.Errorf("failed to get Query %s: %w", key.Name, err)
+	}
+
+	if query.Status.Phase != "done" {
+		return "", fmt.Errorf("query %s is %s, not done", key.Name, queryPhase(query))
+	}
+
+	if queryRef.ResponseTarget == "" {
+		if len(query.Status.Responses) != 1 {
+			return "", fmt.Errorf("query %s has %d responses, responseTarget must name one of them", key.Name, len(query.Status.Responses))
+		}
+		return query.Status.Responses[0].Content, nil
+	}
+
+	for _, response := range query.Status.Responses {
+		if response.Target.Name == queryRef.ResponseTarget {
+			return response.Content, nil
+		}
+	}
+	return "", fmt.Errorf("query %s has no response from target %s", key.Name, queryRef.ResponseTarget)
+}
+
+// QueryParameterRefs returns the queries whose responses the parameters reference
+func QueryParameterRefs(namespace string, parameters []arkv1alpha1.QueryParameter) []types.NamespacedName {
+	var refs []types.NamespacedName
+	for _, param := range parameters {
+		if param.Value == "" && param.ValueFrom != nil && param.ValueFrom.QueryRef != nil {
+			refs = append(refs, QueryParameterRef(namespace, param.ValueFrom.QueryRef))
+		}
+	}
+	return refs
+}
+
+// QueryParameterCycle follows the queries that query uses as parameters, and the queries they use in turn, and
+// returns the path back to query when they form a cycle. The queries of a cycle would wait for each other forever.
+// Queries that do not exist yet, or that k8sClient cannot read, end the search; a cycle is found once its last
+// query is created.
+func QueryParameterCycle(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query) ([]types.NamespacedName, error) {
+	self := types.NamespacedName{Name: query.Name, Namespace: query.Namespace}
+	visited := map[types.NamespacedName]bool{}
+
+	var follow func(ref types.NamespacedName, path []types.NamespacedName) ([]types.NamespacedName, error)
+	follow = func(ref types.NamespacedName, path []types.NamespacedName) ([]types.NamespacedName, error) {
+		path = append(path, ref)
+		if ref == self {
+			return path, nil
+		}
+		if visited[ref] {
+			return nil, nil
+		}
+		visited[ref] = true
+
+		var upstream arkv1alpha1.Query
+		if err := k8sClient.Get(ctx, ref, &upstream); err != nil {
+			if errors.IsNotFound(err) || errors.IsForbidden(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to get Query %s: %w", ref.Name, err)
+		}
+		for _, next := range QueryParameterRefs(upstream.Namespace, upstream.Spec.Parameters) {
+			if cycle, err := follow(next, path); cycle != nil || err != nil {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	for _, ref := range QueryParameterRefs(query.Namespace, query.Spec.Parameters) {
+		if cycle, err := follow(ref, []types.NamespacedName{self}); cycle != nil || err != nil {
+			return cycle, err
+		}
+	}
+	return nil, nil
+}
+
+// FormatQueryPath formats a chain of query references, naming the namespace of queries outside namespace
+func FormatQueryPath(namespace string, path []types.NamespacedName) string {
+	names := make([]string, 0, len(path))
+	for _, ref := range path {
+		if ref.Namespace == namespace {
+			names = append(names, ref.Name)
+		} else {
+			names = append(names, ref.String())
+		}
+	}
+	return strings.Join(names, " -> ")
+}
+
+// QueryParameterRef returns the query a parameter references, which defaults to the namespace of the query
+// that has the parameter
+func QueryParameterRef(namespace string, queryRef *arkv1alpha1.QueryRef) types.NamespacedName {
+	if queryRef.Namespace != "" {
+		namespace = queryRef.Namespace
+	}
+	return types.NamespacedName{Name: queryRef.Name, Namespace: namespace}
+}
+
+func queryPhase(query *arkv1alpha1.Query) string {
+	if query.Status.Phase == "" {
+		return "pending"
+	}
+	return query.Status.Phase
+}
+
 // ResolveBodyTemplate resolves body template with parameters and input data
 func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace, bodyTemplate string, parameters []arkv1alpha1.Parameter, inputData map[string]any) (string, error) {
 	if bodyTemplate == "" {
@@ -104,7 +236,7 @@ func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace
 	}
 
 	if len(parameters) > 0 {
-		paramData, err := resolveQueryParameters(ctx, k8sClient, namespace, parameters)
+		paramData, err := resolveQueryParameters(ctx, k8sClient, namespace, AsQueryParameters(parameters))
 		if err != nil {
 			return "", fmt.Errorf("failed to resolve body parameters: %w", err)
 		}
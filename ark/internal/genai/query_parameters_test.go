@@ -0,0 +1,72 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestResolveQueryInputFromQueryResponse(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "default"},
+			Status: arkv1alpha1.QueryStatus{
+				Phase: "done",
+				Responses: []arkv1alpha1.Response{
+					{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "researcher"}, Content: "three findings"},
+					{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "critic"}, Content: "two concerns"},
+				},
+			},
+		},
+		&arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "running", Namespace: "default"},
+			Status:     arkv1alpha1.QueryStatus{Phase: "running"},
+		},
+	).Build()
+
+	fromQuery := func(name, target string) []arkv1alpha1.QueryParameter {
+		return []arkv1alpha1.QueryParameter{{
+			Name:      "findings",
+			ValueFrom: &arkv1alpha1.QueryParameterSource{QueryRef: &arkv1alpha1.QueryRef{Name: name, ResponseTarget: target}},
+		}}
+	}
+
+	input, err := ResolveQueryInput(context.Background(), k8sClient, "default", "Summarize: {{.findings}}", fromQuery("research", "critic"))
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize: two concerns", input)
+
+	_, err = ResolveQueryInput(context.Background(), k8sClient, "default", "{{.findings}}", fromQuery("research", ""))
+	assert.ErrorContains(t, err, "has 2 responses")
+
+	_, err = ResolveQueryInput(context.Background(), k8sClient, "default", "{{.findings}}", fromQuery("running", ""))
+	assert.ErrorContains(t, err, "is running, not done")
+
+	_, err = ResolveQueryInput(context.Background(), k8sClient, "default", "{{.findings}}", fromQuery("missing", ""))
+	assert.Error(t, err)
+}
+
+func TestQueryParameterRefs(t *testing.T) {
+	parameters := []arkv1alpha1.QueryParameter{
+		{Name: "direct", Value: "value"},
+		{Name: "same", ValueFrom: &arkv1alpha1.QueryParameterSource{QueryRef: &arkv1alpha1.QueryRef{Name: "a"}}},
+		{Name: "other", ValueFrom: &arkv1alpha1.QueryParameterSource{QueryRef: &arkv1alpha1.QueryRef{Name: "b", Namespace: "shared"}}},
+	}
+
+	assert.Equal(t, []types.NamespacedName{
+		{Name: "a", Namespace: "team-a"},
+		{Name: "b", Namespace: "shared"},
+	}, QueryParameterRefs("team-a", parameters))
+}
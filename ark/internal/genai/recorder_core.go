@@ -32,6 +32,13 @@ func NewModelRecorder(model *arkv1alpha1.Model, recorder record.EventRecorder) *
 	}
 }
 
+func NewWorkflowRecorder(workflow *arkv1alpha1.Workflow, recorder record.EventRecorder) *Recorder[*arkv1alpha1.Workflow] {
+	return &Recorder[*arkv1alpha1.Workflow]{
+		resource: workflow,
+		recorder: recorder,
+	}
+}
+
 func (r *Recorder[T]) EmitEvent(ctx context.Context, eventType string, data EventData) {
 	log := logf.FromContext(ctx).WithValues("eventType", eventType)
 
@@ -0,0 +1,137 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+
+	"github.com/openai/openai-go"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	ToolCallPhaseRunning   = "running"
+	ToolCallPhaseCompleted = "completed"
+	ToolCallPhaseError     = "error"
+)
+
+// StreamChunk is a fragment of output published while a target is still executing
+type StreamChunk struct {
+	// Source is the agent or model producing the output
+	Source string
+	// MessageStart marks the beginning of a new assistant message; content and
+	// tool calls published afterwards belong to that message
+	MessageStart bool
+	// Content is appended to the current assistant message
+	Content  string
+	ToolCall *ToolCallProgress
+}
+
+type ToolCallProgress struct {
+	ID        string
+	Name      string
+	Arguments string
+	Phase     string
+}
+
+// StreamPublisher receives partial output. Implementations must be safe for concurrent use.
+type StreamPublisher interface {
+	Publish(chunk StreamChunk)
+}
+
+// StreamingChatCompletionProvider is implemented by providers that can return a completion incrementally.
+// The accumulated completion is returned once the stream ends.
+type StreamingChatCompletionProvider interface {
+	ChatCompletionStream(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error)
+}
+
+const streamPublisherKey contextKey = "streamPublisher"
+
+func WithStreamPublisher(ctx context.Context, publisher StreamPublisher) context.Context {
+	return context.WithValue(ctx, streamPublisherKey, publisher)
+}
+
+func getStreamPublisher(ctx context.Context) StreamPublisher {
+	if val := ctx.Value(streamPublisherKey); val != nil {
+		if publisher, ok := val.(StreamPublisher); ok {
+			return publisher
+		}
+	}
+	return nil
+}
+
+// NewStreamChunkHandler starts a new assistant message for source and returns a handler that
+// publishes content deltas to the publisher attached to ctx. It returns nil when streaming is not enabled.
+func NewStreamChunkHandler(ctx context.Context, source string) func(openai.ChatCompletionChunk) {
+	publisher := getStreamPublisher(ctx)
+	if publisher == nil {
+		return nil
+	}
+
+	publisher.Publish(StreamChunk{Source: source, MessageStart: true})
+	return func(chunk openai.ChatCompletionChunk) {
+		for _, choice := range chunk.Choices {
+			if choice.Index == 0 && choice.Delta.Content != "" {
+				publisher.Publish(StreamChunk{Source: source, Content: choice.Delta.Content})
+			}
+		}
+	}
+}
+
+func publishToolCallProgress(ctx context.Context, source string, toolCall openai.ChatCompletionMessageToolCall, phase string) {
+	publisher := getStreamPublisher(ctx)
+	if publisher == nil {
+		return
+	}
+
+	publisher.Publish(StreamChunk{
+		Source: source,
+		ToolCall: &ToolCallProgress{
+			ID:        toolCall.ID,
+			Name:      toolCall.Function.Name,
+			Arguments: toolCall.Function.Arguments,
+			Phase:     phase,
+		},
+	})
+}
+
+// streamChatCompletion runs a streaming request against an OpenAI-compatible endpoint and accumulates the chunks
+func streamChatCompletion(ctx context.Context, client openai.Client, params openai.ChatCompletionNewParams, onChunk func(openai.ChatCompletionChunk)) (*openai.ChatCompletion, error) {
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{
+		IncludeUsage: openai.Bool(true),
+	}
+
+	stream := client.Chat.Completions.NewStreaming(ctx, params)
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	acc := openai.ChatCompletionAccumulator{}
+	for stream.Next() {
+		chunk := stream.Current()
+		acc.AddChunk(chunk)
+		onChunk(chunk)
+	}
+
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+
+	return &acc.ChatCompletion, nil
+}
+
+// completionAsChunk converts a non-streamed completion into a single chunk so callers see the same output either way
+func completionAsChunk(completion *openai.ChatCompletion) openai.ChatCompletionChunk {
+	chunk := openai.ChatCompletionChunk{
+		ID:    completion.ID,
+		Model: completion.Model,
+	}
+	for _, choice := range completion.Choices {
+		chunk.Choices = append(chunk.Choices, openai.ChatCompletionChunkChoice{
+			Index:        choice.Index,
+			Delta:        openai.ChatCompletionChunkChoiceDelta{Content: choice.Message.Content},
+			FinishReason: choice.FinishReason,
+		})
+	}
+	return chunk
+}
@@ -0,0 +1,104 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockPublisher struct {
+	mu     sync.Mutex
+	chunks []StreamChunk
+}
+
+func (m *mockPublisher) Publish(chunk StreamChunk) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.chunks = append(m.chunks, chunk)
+}
+
+func TestOpenAIProviderChatCompletionStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, delta := range []string{"Hel", "lo"} {
+			fmt.Fprintf(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt\",\"choices\":[{\"index\":0,\"delta\":{\"role\":\"assistant\",\"content\":%q}}]}\n\n", delta)
+		}
+		fmt.Fprint(w, "data: {\"id\":\"c1\",\"object\":\"chat.completion.chunk\",\"model\":\"gpt\",\"choices\":[],\"usage\":{\"prompt_tokens\":5,\"completion_tokens\":2,\"total_tokens\":7}}\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer server.Close()
+
+	provider := &OpenAIProvider{Model: "gpt", BaseURL: server.URL, APIKey: "test"}
+	publisher := &mockPublisher{}
+	ctx := WithStreamPublisher(context.Background(), publisher)
+
+	completion, err := provider.ChatCompletionStream(ctx, []Message{NewUserMessage("hi")}, nil, "", nil, NewStreamChunkHandler(ctx, "agent"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Hello", completion.Choices[0].Message.Content)
+	assert.Equal(t, int64(7), completion.Usage.TotalTokens)
+
+	require.Len(t, publisher.chunks, 3)
+	assert.True(t, publisher.chunks[0].MessageStart)
+	assert.Equal(t, "Hel", publisher.chunks[1].Content)
+	assert.Equal(t, "lo", publisher.chunks[2].Content)
+}
+
+func TestModelChatCompletionStreamFallsBackToSingleChunk(t *testing.T) {
+	model := &Model{Model: "static", Type: ModelTypeOpenAI, Provider: &fakeProvider{replies: []string{"full answer"}}}
+
+	var chunks []openai.ChatCompletionChunk
+	completion, err := model.ChatCompletionStream(context.Background(), []Message{NewUserMessage("hi")}, nil, func(chunk openai.ChatCompletionChunk) {
+		chunks = append(chunks, chunk)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "full answer", completion.Choices[0].Message.Content)
+	require.Len(t, chunks, 1)
+	assert.Equal(t, "full answer", chunks[0].Choices[0].Delta.Content)
+}
+
+func TestNewStreamChunkHandlerWithoutPublisher(t *testing.T) {
+	assert.Nil(t, NewStreamChunkHandler(context.Background(), "agent"))
+}
+
+func TestBedrockStreamAccumulator(t *testing.T) {
+	acc := newBedrockStreamAccumulator()
+
+	events := []string{
+		`{"type":"message_start","message":{"id":"msg-1","model":"claude","usage":{"input_tokens":10}}}`,
+		`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+		`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Checking"}}`,
+		`{"type":"content_block_start","index":1,"content_block":{"type":"tool_use","id":"tool-1","name":"get-weather"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"{\"city\":"}}`,
+		`{"type":"content_block_delta","index":1,"delta":{"type":"input_json_delta","partial_json":"\"Paris\"}"}}`,
+		`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":12}}`,
+	}
+
+	var text string
+	for _, raw := range events {
+		var event bedrockStreamEvent
+		require.NoError(t, json.Unmarshal([]byte(raw), &event))
+		text += acc.add(event)
+	}
+	assert.Equal(t, "Checking", text)
+
+	completion := convertAnthropicResponse(acc.finish(), "")
+	assert.Equal(t, "msg-1", completion.ID)
+	assert.Equal(t, "tool_calls", completion.Choices[0].FinishReason)
+	assert.Equal(t, "Checking", completion.Choices[0].Message.Content)
+	require.Len(t, completion.Choices[0].Message.ToolCalls, 1)
+	assert.Equal(t, "get-weather", completion.Choices[0].Message.ToolCalls[0].Function.Name)
+	assert.JSONEq(t, `{"city":"Paris"}`, completion.Choices[0].Message.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, int64(22), completion.Usage.TotalTokens)
+}
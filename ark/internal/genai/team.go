@@ -2,6 +2,7 @@ package genai
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 
@@ -19,6 +20,9 @@ type Team struct {
 	MaxTurns    *int
 	Selector    *arkv1alpha1.TeamSelectorSpec
 	Graph       *arkv1alpha1.TeamGraphSpec
+	Parallel    *arkv1alpha1.TeamParallelSpec
+	Aggregator  TeamMember
+	Manager     *Agent
 	Recorder    EventEmitter
 	Client      client.Client
 	Namespace   string
@@ -42,6 +46,11 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 		"memberCount": fmt.Sprintf("%d", len(t.Members)),
 	})
 
+	// Nested teams share the scratchpad of the team or query that runs them
+	if getScratchpad(ctx) == nil {
+		ctx = WithScratchpad(ctx, NewScratchpad())
+	}
+
 	var execFunc func(context.Context, Message, []Message) ([]Message, error)
 	switch t.Strategy {
 	case "sequential":
@@ -52,6 +61,10 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 		execFunc = t.executeSelector
 	case "graph":
 		execFunc = t.executeGraph
+	case "parallel":
+		execFunc = t.executeParallel
+	case "planner":
+		execFunc = t.executePlanner
 	default:
 		err := fmt.Errorf("unsupported strategy %s for team %s", t.Strategy, t.FullName())
 		teamTracker.Fail(err)
@@ -134,6 +147,22 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		return nil, err
 	}
 
+	var aggregator TeamMember
+	if crd.Spec.Parallel != nil && crd.Spec.Parallel.Aggregator != nil {
+		aggregator, err = loadTeamMember(ctx, k8sClient, *crd.Spec.Parallel.Aggregator, crd.Namespace, crd.Name, recorder)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var manager *Agent
+	if crd.Spec.Planner != nil {
+		manager, err = loadPlannerManager(ctx, k8sClient, crd, recorder)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &Team{
 		Name:        crd.Name,
 		Members:     members,
@@ -142,6 +171,9 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		MaxTurns:    crd.Spec.MaxTurns,
 		Selector:    crd.Spec.Selector,
 		Graph:       crd.Spec.Graph,
+		Parallel:    crd.Spec.Parallel,
+		Aggregator:  aggregator,
+		Manager:     manager,
 		Recorder:    recorder,
 		Client:      k8sClient,
 		Namespace:   crd.Namespace,
@@ -184,6 +216,7 @@ func (t *Team) executeWithTracking(tracker *OperationTracker, execFunc func(cont
 			PromptTokens:     finalTokens.PromptTokens - initialTokens.PromptTokens,
 			CompletionTokens: finalTokens.CompletionTokens - initialTokens.CompletionTokens,
 			TotalTokens:      finalTokens.TotalTokens - initialTokens.TotalTokens,
+			Cost:             finalTokens.Cost - initialTokens.Cost,
 		}
 	}
 
@@ -192,20 +225,61 @@ func (t *Team) executeWithTracking(tracker *OperationTracker, execFunc func(cont
 			tracker.CompleteWithTermination(err.Error())
 			return result, err
 		}
+		if IsExecutionLimitReached(err) {
+			tracker.CompleteWithTerminationReason(TerminationReason(err), err.Error())
+			return result, err
+		}
 		tracker.Fail(err)
 		return result, err
 	}
 
+	// The members' calls have already been counted, so the team's total is reported as metadata rather than as
+	// token usage, which collectors and budgets would count a second time
 	if teamTokenUsage.TotalTokens > 0 {
-		tracker.CompleteWithTokens("", teamTokenUsage)
+		metadata := map[string]string{"totalTokens": fmt.Sprintf("%d", teamTokenUsage.TotalTokens)}
+		if teamTokenUsage.Cost > 0 {
+			metadata["cost"] = FormatCost(teamTokenUsage.Cost)
+		}
+		tracker.CompleteWithMetadata("", metadata)
 	} else {
 		tracker.Complete("")
 	}
 	return result, err
 }
 
-// executeMemberAndAccumulate executes a member and accumulates new messages
+// lastMessageContent returns the response of a member: the content of its last assistant message, or of the
+// terminate tool result when the member ended the conversation
+func lastMessageContent(messages []Message) string {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if m := messages[i].OfAssistant; m != nil && m.Content.OfString.Value != "" {
+			return m.Content.OfString.Value
+		}
+		if m := messages[i].OfTool; m != nil {
+			return m.Content.OfString.Value
+		}
+	}
+	return ""
+}
+
+// executeMemberAndAccumulate executes a member and accumulates new messages. When the member hands off, the
+// members that follow see its summary instead of the accumulated messages.
 func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember, userInput Message, messages, newMessages *[]Message, turn int) error {
+	memberNewMessages, err := t.executeMember(ctx, member, userInput, *messages, turn)
+	*newMessages = append(*newMessages, memberNewMessages...)
+
+	var handoff *TeamHandoff
+	if errors.As(err, &handoff) {
+		*messages = handoffMessages(member, handoff)
+		return nil
+	}
+
+	// Still accumulate messages even on error
+	*messages = append(*messages, memberNewMessages...)
+	return err
+}
+
+// executeMember executes a member and tracks the execution as a team member operation
+func (t *Team) executeMember(ctx context.Context, member TeamMember, userInput Message, history []Message, turn int) ([]Message, error) {
 	memberTracker := NewOperationTracker(t.Recorder, ctx, "TeamMember", member.GetName(), map[string]string{
 		"team":       t.FullName(),
 		"memberType": member.GetType(),
@@ -215,23 +289,23 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 		"strategy":   t.Strategy,
 	})
 
-	memberNewMessages, err := member.Execute(ctx, userInput, *messages)
+	memberNewMessages, err := member.Execute(withTeamMember(ctx, true), userInput, history)
 	if err != nil {
-		if IsTerminateTeam(err) {
+		switch {
+		case IsTerminateTeam(err):
 			memberTracker.CompleteWithTermination(err.Error())
-		} else {
+		case IsTeamHandoff(err):
+			memberTracker.CompleteWithMetadata("", map[string]string{"handoff": "true"})
+		case IsExecutionLimitReached(err):
+			memberTracker.CompleteWithTerminationReason(TerminationReason(err), err.Error())
+		default:
 			memberTracker.Fail(err)
 		}
-		// Still accumulate messages even on error
-		*messages = append(*messages, memberNewMessages...)
-		*newMessages = append(*newMessages, memberNewMessages...)
-		return err
+		return memberNewMessages, err
 	}
 
 	memberTracker.Complete("")
-	*messages = append(*messages, memberNewMessages...)
-	*newMessages = append(*newMessages, memberNewMessages...)
-	return nil
+	return memberNewMessages, nil
 }
 
 func loadTeamMember(ctx context.Context, k8sClient client.Client, memberSpec arkv1alpha1.TeamMember, namespace, teamName string, recorder EventEmitter) (TeamMember, error) {
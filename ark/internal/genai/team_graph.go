@@ -2,9 +2,107 @@ package genai
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/itchyny/gojq"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
+const graphEdgeSelectorPrompt = `Read the following conversation. %s has just responded. Choose who responds next from the options below. Only return the name of the chosen option.
+
+%s
+
+%s`
+
+// graphEdge is an edge of the team graph with its condition compiled
+type graphEdge struct {
+	arkv1alpha1.TeamGraphEdge
+	pattern *regexp.Regexp
+	query   *gojq.Code
+}
+
+// ValidateTeamGraphEdge checks that the condition of an edge sets at least one field and that its regular
+// expression and jq expression compile
+func ValidateTeamGraphEdge(edge arkv1alpha1.TeamGraphEdge) error {
+	_, err := compileGraphEdge(edge)
+	return err
+}
+
+func compileGraphEdge(edge arkv1alpha1.TeamGraphEdge) (graphEdge, error) {
+	compiled := graphEdge{TeamGraphEdge: edge}
+	condition := edge.Condition
+	if condition == nil {
+		return compiled, nil
+	}
+	if condition.Contains == "" && condition.Matches == "" && condition.JQ == "" && condition.Description == "" {
+		return compiled, fmt.Errorf("condition of edge %s -> %s must set contains, matches, jq or description", edge.From, edge.To)
+	}
+
+	if condition.Matches != "" {
+		pattern, err := regexp.Compile(condition.Matches)
+		if err != nil {
+			return compiled, fmt.Errorf("invalid regular expression in condition of edge %s -> %s: %w", edge.From, edge.To, err)
+		}
+		compiled.pattern = pattern
+	}
+
+	if condition.JQ != "" {
+		parsed, err := gojq.Parse(condition.JQ)
+		if err != nil {
+			return compiled, fmt.Errorf("failed to parse jq expression '%s' in condition of edge %s -> %s: %w", condition.JQ, edge.From, edge.To, err)
+		}
+		query, err := gojq.Compile(parsed)
+		if err != nil {
+			return compiled, fmt.Errorf("failed to compile jq expression '%s' in condition of edge %s -> %s: %w", condition.JQ, edge.From, edge.To, err)
+		}
+		compiled.query = query
+	}
+
+	return compiled, nil
+}
+
+// holds reports whether the contains, matches and jq parts of the condition hold for the content. The
+// description is left to the selector model.
+func (e graphEdge) holds(content string) bool {
+	if e.Condition.Contains != "" && !strings.Contains(content, e.Condition.Contains) {
+		return false
+	}
+	if e.pattern != nil && !e.pattern.MatchString(content) {
+		return false
+	}
+	if e.query != nil && !jqConditionHolds(e.query, content) {
+		return false
+	}
+	return true
+}
+
+// jqConditionHolds runs the query over the content parsed as JSON. Content that is not JSON, and queries that
+// fail on it, do not hold.
+func jqConditionHolds(query *gojq.Code, content string) bool {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return false
+	}
+
+	iter := query.Run(data)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			return false
+		}
+		if _, isErr := v.(error); isErr {
+			return false
+		}
+		if matched, isBool := v.(bool); isBool && matched {
+			return true
+		}
+	}
+}
+
 func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
 	if len(t.Members) == 0 {
 		return nil, fmt.Errorf("team %s has no members for graph execution", t.FullName())
@@ -18,10 +116,14 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		memberMap[member.GetName()] = member
 	}
 
-	transitionMap := make(map[string]string)
+	outgoing := make(map[string][]graphEdge)
 	if t.Graph != nil {
 		for _, edge := range t.Graph.Edges {
-			transitionMap[edge.From] = edge.To
+			compiled, err := compileGraphEdge(edge)
+			if err != nil {
+				return nil, fmt.Errorf("team %s: %w", t.FullName(), err)
+			}
+			outgoing[edge.From] = append(outgoing[edge.From], compiled)
 		}
 	}
 
@@ -29,6 +131,7 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 	turnTracker.TeamTurn(ctx, "Start", t.FullName(), t.Strategy, 0)
 
 	currentMemberName := t.Members[0].GetName()
+	selectionReason := "graph"
 
 	for turns := 0; ; turns++ {
 		member, exists := memberMap[currentMemberName]
@@ -37,7 +140,7 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		}
 
 		memberTracker := NewExecutionRecorder(t.Recorder)
-		memberTracker.ParticipantSelected(ctx, t.FullName(), currentMemberName, "graph")
+		memberTracker.ParticipantSelected(ctx, t.FullName(), currentMemberName, selectionReason)
 
 		if err := t.executeMemberAndAccumulate(ctx, member, userInput, &messages, &newMessages, turns); err != nil {
 			if IsTerminateTeam(err) {
@@ -46,12 +149,16 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 			return newMessages, err
 		}
 
-		nextMember := transitionMap[currentMemberName]
+		nextMember, reason, err := t.nextGraphMember(ctx, currentMemberName, outgoing[currentMemberName], messages)
+		if err != nil {
+			return newMessages, err
+		}
 		if nextMember == "" {
 			break
 		}
 
 		currentMemberName = nextMember
+		selectionReason = reason
 
 		if t.MaxTurns != nil && turns+1 >= *t.MaxTurns {
 			turnTracker.TeamTurn(ctx, "MaxTurns", t.FullName(), t.Strategy, turns+1)
@@ -61,3 +168,99 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 
 	return newMessages, nil
 }
+
+// nextGraphMember picks the edge to follow from the current member. The first edge whose condition holds
+// without a description wins. Otherwise the selector model chooses among the edges with a description whose
+// other conditions hold, and the edge without a condition is followed when there are none. An empty name means
+// the graph has reached its end.
+func (t *Team) nextGraphMember(ctx context.Context, current string, edges []graphEdge, messages []Message) (string, string, error) {
+	content := lastMessageContent(messages)
+
+	var fallback string
+	var described []graphEdge
+	for _, edge := range edges {
+		switch {
+		case edge.Condition == nil:
+			if fallback == "" {
+				fallback = edge.To
+			}
+		case !edge.holds(content):
+		case edge.Condition.Description != "":
+			described = append(described, edge)
+		default:
+			return edge.To, "graph_condition", nil
+		}
+	}
+
+	if len(described) > 0 {
+		selected, err := t.selectGraphEdge(ctx, current, described, fallback, messages)
+		if err != nil {
+			return "", "", err
+		}
+		return selected, "graph_selector", nil
+	}
+
+	if fallback != "" {
+		return fallback, "graph_default", nil
+	}
+	return "", "", nil
+}
+
+// selectGraphEdge asks the selector model which of the described edges to follow. The edge without a condition
+// is offered as the answer when none of the descriptions apply.
+func (t *Team) selectGraphEdge(ctx context.Context, current string, edges []graphEdge, fallback string, messages []Message) (string, error) {
+	options := make([]string, 0, len(edges)+1)
+	names := make([]string, 0, len(edges)+1)
+	for _, edge := range edges {
+		options = append(options, fmt.Sprintf("- %s: %s", edge.To, edge.Condition.Description))
+		names = append(names, edge.To)
+	}
+	if fallback != "" {
+		options = append(options, fmt.Sprintf("- %s: none of the above", fallback))
+		names = append(names, fallback)
+	}
+
+	model, err := LoadModel(ctx, t.Client, t.Selector, t.Namespace)
+	if err != nil {
+		return "", err
+	}
+
+	selectorMessages := []Message{
+		NewSystemMessage(fmt.Sprintf(graphEdgeSelectorPrompt, current, strings.Join(options, "\n"), buildHistory(messages))),
+		NewUserMessage("Select the next participant to respond."),
+	}
+
+	llmTracker := NewOperationTracker(t.Recorder, ctx, "LLMCall", model.Model, map[string]string{
+		"team":    t.FullName(),
+		"model":   model.Model,
+		"purpose": "graph_edge_selection",
+	})
+
+	response, usedModel, err := model.ChatCompletionWithFailover(ctx, selectorMessages, nil, nil, nil)
+	if err != nil {
+		llmTracker.Fail(err)
+		return "", fmt.Errorf("selector model call failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		err := fmt.Errorf("selector model returned no choices")
+		llmTracker.Fail(err)
+		return "", err
+	}
+	llmTracker.CompleteWithModel("", usedModel.TokenUsage(response.Usage), usedModel.Name)
+
+	selectedName := strings.TrimSpace(response.Choices[0].Message.Content)
+	rec := NewExecutionRecorder(t.Recorder)
+	rec.SelectorModelResponse(ctx, t.FullName(), model.Model, selectedName, strings.Join(names, ", "))
+
+	for _, name := range names {
+		if name == selectedName {
+			return name, nil
+		}
+	}
+
+	// Fall back to the unconditional edge, or the first described edge when there is none
+	if fallback != "" {
+		return fallback, nil
+	}
+	return edges[0].To, nil
+}
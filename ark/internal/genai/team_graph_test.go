@@ -0,0 +1,132 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func graphTeam(maxTurns *int, edges []arkv1alpha1.TeamGraphEdge, members ...TeamMember) *Team {
+	return &Team{
+		Name:      "review",
+		Namespace: "default",
+		Strategy:  "graph",
+		Members:   members,
+		MaxTurns:  maxTurns,
+		Graph:     &arkv1alpha1.TeamGraphSpec{Edges: edges},
+		Recorder:  &mockRecorder{},
+	}
+}
+
+func TestExecuteGraphConditionalEdges(t *testing.T) {
+	writer := &fakeMember{name: "writer", responses: []string{"draft"}}
+	reviewer := &fakeMember{name: "reviewer", responses: []string{`{"approved": false}`, `{"approved": true}`}}
+	publisher := &fakeMember{name: "publisher", responses: []string{"published"}}
+
+	edges := []arkv1alpha1.TeamGraphEdge{
+		{From: "writer", To: "reviewer"},
+		{From: "reviewer", To: "publisher", Condition: &arkv1alpha1.TeamGraphEdgeCondition{JQ: ".approved"}},
+		{From: "reviewer", To: "writer"},
+	}
+	maxTurns := 10
+	team := graphTeam(&maxTurns, edges, writer, reviewer, publisher)
+
+	messages, err := team.executeGraph(context.Background(), NewUserMessage("write a post"), nil)
+	require.NoError(t, err)
+
+	// writer -> reviewer (rejects) -> writer -> reviewer (approves) -> publisher
+	assert.Len(t, messages, 5)
+	assert.Equal(t, 2, writer.calls)
+	assert.Equal(t, 2, reviewer.calls)
+	assert.Equal(t, 1, publisher.calls)
+}
+
+func TestExecuteGraphLoopBoundedByMaxTurns(t *testing.T) {
+	writer := &fakeMember{name: "writer", responses: []string{"draft"}}
+	reviewer := &fakeMember{name: "reviewer", responses: []string{"REJECTED: too long"}}
+
+	edges := []arkv1alpha1.TeamGraphEdge{
+		{From: "writer", To: "reviewer"},
+		{From: "reviewer", To: "writer", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Matches: "^REJECTED"}},
+	}
+	maxTurns := 4
+	team := graphTeam(&maxTurns, edges, writer, reviewer)
+
+	messages, err := team.executeGraph(context.Background(), NewUserMessage("write a post"), nil)
+	assert.ErrorContains(t, err, "MaxTurns 4 reached")
+	assert.Len(t, messages, 4)
+}
+
+func TestGraphEdgeSelectionIsTracked(t *testing.T) {
+	writer := &fakeMember{name: "writer", responses: []string{"draft"}}
+	publisher := &fakeMember{name: "publisher", responses: []string{"published"}}
+	edges := []arkv1alpha1.TeamGraphEdge{
+		{From: "writer", To: "publisher", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Description: "the draft is ready"}},
+	}
+	maxTurns := 3
+	team := graphTeam(&maxTurns, edges, writer, publisher)
+	team.Client = modelServerClient(t, "router", "publisher")
+	team.Selector = &arkv1alpha1.TeamSelectorSpec{Model: "router"}
+	collector := NewTokenUsageCollector(&mockRecorder{})
+	team.Recorder = collector
+
+	_, err := team.executeGraph(context.Background(), NewUserMessage("write a post"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, publisher.calls)
+	assert.Equal(t, int64(42), collector.GetTokenSummary().TotalTokens, "the edge selection call counts towards the usage")
+}
+
+func TestNextGraphMember(t *testing.T) {
+	edges := []arkv1alpha1.TeamGraphEdge{
+		{From: "triage", To: "billing", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Contains: "invoice"}},
+		{From: "triage", To: "support", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Matches: `(?i)\berror\b`}},
+		{From: "triage", To: "sales", Condition: &arkv1alpha1.TeamGraphEdgeCondition{JQ: `.intent == "buy"`}},
+		{From: "triage", To: "general"},
+	}
+	var compiled []graphEdge
+	for _, edge := range edges {
+		c, err := compileGraphEdge(edge)
+		require.NoError(t, err)
+		compiled = append(compiled, c)
+	}
+	team := graphTeam(nil, edges)
+
+	tests := []struct {
+		content string
+		next    string
+		reason  string
+	}{
+		{"The invoice is wrong", "billing", "graph_condition"},
+		{"I get an Error on login", "support", "graph_condition"},
+		{`{"intent": "buy"}`, "sales", "graph_condition"},
+		{`{"intent": "browse"}`, "general", "graph_default"},
+		{"hello", "general", "graph_default"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			next, reason, err := team.nextGraphMember(context.Background(), "triage", compiled, []Message{NewAssistantMessage(tt.content)})
+			require.NoError(t, err)
+			assert.Equal(t, tt.next, next)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+
+	next, _, err := team.nextGraphMember(context.Background(), "triage", compiled[:1], []Message{NewAssistantMessage("hello")})
+	require.NoError(t, err)
+	assert.Empty(t, next)
+}
+
+func TestValidateTeamGraphEdge(t *testing.T) {
+	assert.NoError(t, ValidateTeamGraphEdge(arkv1alpha1.TeamGraphEdge{From: "a", To: "b"}))
+	assert.NoError(t, ValidateTeamGraphEdge(arkv1alpha1.TeamGraphEdge{From: "a", To: "b", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Description: "the user wants a refund"}}))
+
+	assert.ErrorContains(t, ValidateTeamGraphEdge(arkv1alpha1.TeamGraphEdge{From: "a", To: "b", Condition: &arkv1alpha1.TeamGraphEdgeCondition{}}), "must set contains, matches, jq or description")
+	assert.ErrorContains(t, ValidateTeamGraphEdge(arkv1alpha1.TeamGraphEdge{From: "a", To: "b", Condition: &arkv1alpha1.TeamGraphEdgeCondition{Matches: "("}}), "invalid regular expression")
+	assert.ErrorContains(t, ValidateTeamGraphEdge(arkv1alpha1.TeamGraphEdge{From: "a", To: "b", Condition: &arkv1alpha1.TeamGraphEdgeCondition{JQ: ".["}}), "failed to parse jq expression")
+}
@@ -0,0 +1,171 @@
+package genai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/packages/param"
+)
+
+const (
+	AggregationConcat     = "concat"
+	AggregationAggregator = "aggregator"
+	AggregationModel      = "model"
+	AggregationVote       = "vote"
+)
+
+const defaultAggregationPrompt = `You combine the responses of several team members who answered the same request independently. Write a single answer to the request that keeps the best points of each response and resolves any disagreements between them.`
+
+// parallelResponse is the outcome of one member of a parallel team
+type parallelResponse struct {
+	member   string
+	messages []Message
+}
+
+func (t *Team) executeParallel(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	turnTracker := NewExecutionRecorder(t.Recorder)
+	turnTracker.TeamTurn(ctx, "Start", t.FullName(), t.Strategy, 0)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]parallelResponse, len(t.Members))
+	var wg sync.WaitGroup
+	var failure sync.Once
+	var memberErr error
+	for i, member := range t.Members {
+		wg.Add(1)
+		go func(i int, member TeamMember) {
+			defer wg.Done()
+			messages, err := t.executeMember(ctx, member, userInput, slices.Clone(history), i)
+			// A member that terminates the team or hands off has still answered. Any other failure fails the
+			// team, so the remaining members are stopped.
+			if err != nil && !IsTerminateTeam(err) && !IsTeamHandoff(err) {
+				failure.Do(func() {
+					memberErr = fmt.Errorf("member %s of team %s failed: %w", member.GetName(), t.FullName(), err)
+					cancel()
+				})
+			}
+			responses[i] = parallelResponse{member: member.GetName(), messages: messages}
+		}(i, member)
+	}
+	wg.Wait()
+
+	var newMessages []Message
+	for _, response := range responses {
+		newMessages = append(newMessages, response.messages...)
+	}
+	if memberErr != nil {
+		return newMessages, memberErr
+	}
+
+	aggregated, err := t.aggregateResponses(ctx, userInput, history, responses)
+	newMessages = append(newMessages, aggregated...)
+	return newMessages, err
+}
+
+// aggregateResponses combines the member responses into the messages that end the team's turn
+func (t *Team) aggregateResponses(ctx context.Context, userInput Message, history []Message, responses []parallelResponse) ([]Message, error) {
+	aggregation := AggregationConcat
+	if t.Parallel != nil && t.Parallel.Aggregation != "" {
+		aggregation = t.Parallel.Aggregation
+	}
+
+	switch aggregation {
+	case AggregationConcat:
+		return []Message{t.teamMessage(formatParallelResponses(responses))}, nil
+	case AggregationVote:
+		return []Message{t.teamMessage(majorityResponse(responses))}, nil
+	case AggregationAggregator:
+		if t.Aggregator == nil {
+			return nil, fmt.Errorf("team %s uses aggregator aggregation without an aggregator", t.FullName())
+		}
+		input := NewUserMessage(aggregationInput(userInput, responses))
+		return t.executeMember(ctx, t.Aggregator, input, history, len(t.Members))
+	case AggregationModel:
+		return t.aggregateWithModel(ctx, userInput, responses)
+	default:
+		return nil, fmt.Errorf("unsupported aggregation %s for team %s", aggregation, t.FullName())
+	}
+}
+
+func (t *Team) aggregateWithModel(ctx context.Context, userInput Message, responses []parallelResponse) ([]Message, error) {
+	model, err := LoadModel(ctx, t.Client, t.Parallel.Model, t.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := defaultAggregationPrompt
+	if t.Parallel.Prompt != "" {
+		prompt = t.Parallel.Prompt
+	}
+
+	llmTracker := NewOperationTracker(t.Recorder, ctx, "LLMCall", model.Model, map[string]string{
+		"team":    t.FullName(),
+		"model":   model.Model,
+		"purpose": "parallel_aggregation",
+	})
+
+	response, usedModel, err := model.ChatCompletionWithFailover(ctx, []Message{
+		NewSystemMessage(prompt),
+		NewUserMessage(aggregationInput(userInput, responses)),
+	}, nil, nil, nil)
+	if err != nil {
+		llmTracker.Fail(err)
+		return nil, fmt.Errorf("aggregation model call failed: %w", err)
+	}
+	if len(response.Choices) == 0 {
+		err := fmt.Errorf("aggregation model returned no choices")
+		llmTracker.Fail(err)
+		return nil, err
+	}
+	llmTracker.CompleteWithModel("", usedModel.TokenUsage(response.Usage), usedModel.Name)
+
+	return []Message{t.teamMessage(response.Choices[0].Message.Content)}, nil
+}
+
+// teamMessage returns an assistant message attributed to the team
+func (t *Team) teamMessage(content string) Message {
+	message := NewAssistantMessage(content)
+	message.OfAssistant.Name = param.Opt[string]{Value: t.Name}
+	return message
+}
+
+func formatParallelResponses(responses []parallelResponse) string {
+	sections := make([]string, 0, len(responses))
+	for _, response := range responses {
+		sections = append(sections, fmt.Sprintf("# %s:\n%s", response.member, lastMessageContent(response.messages)))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+func aggregationInput(userInput Message, responses []parallelResponse) string {
+	request := ""
+	if m := userInput.OfUser; m != nil {
+		request = m.Content.OfString.Value
+	}
+	return fmt.Sprintf("Request:\n%s\n\nResponses:\n\n%s", request, formatParallelResponses(responses))
+}
+
+// majorityResponse returns the response given by most members, ignoring case and surrounding whitespace. Ties go
+// to the response given first.
+func majorityResponse(responses []parallelResponse) string {
+	contents := make([]string, 0, len(responses))
+	counts := make(map[string]int, len(responses))
+	for _, response := range responses {
+		content := strings.TrimSpace(lastMessageContent(response.messages))
+		contents = append(contents, content)
+		counts[strings.ToLower(content)]++
+	}
+
+	winner, winnerCount := "", 0
+	for _, content := range contents {
+		if count := counts[strings.ToLower(content)]; count > winnerCount {
+			winner, winnerCount = content, count
+		}
+	}
+	return winner
+}
@@ -0,0 +1,125 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func parallelTeam(parallel *arkv1alpha1.TeamParallelSpec, members ...TeamMember) *Team {
+	return &Team{
+		Name:      "ensemble",
+		Namespace: "default",
+		Strategy:  "parallel",
+		Members:   members,
+		Parallel:  parallel,
+		Recorder:  &mockRecorder{},
+	}
+}
+
+func TestExecuteParallelConcat(t *testing.T) {
+	team := parallelTeam(nil,
+		&fakeMember{name: "optimist", responses: []string{"Ship it"}},
+		&fakeMember{name: "skeptic", responses: []string{"Wait a week"}},
+	)
+
+	messages, err := team.executeParallel(context.Background(), NewUserMessage("Should we release?"), nil)
+	require.NoError(t, err)
+
+	require.Len(t, messages, 3)
+	assert.Equal(t, "# optimist:\nShip it\n\n# skeptic:\nWait a week", lastMessageContent(messages))
+	assert.Equal(t, "ensemble", messages[2].OfAssistant.Name.Value)
+}
+
+func TestExecuteParallelVote(t *testing.T) {
+	team := parallelTeam(&arkv1alpha1.TeamParallelSpec{Aggregation: AggregationVote},
+		&fakeMember{name: "a", responses: []string{"Reject"}},
+		&fakeMember{name: "b", responses: []string{"approve"}},
+		&fakeMember{name: "c", responses: []string{"Approve "}},
+	)
+
+	messages, err := team.executeParallel(context.Background(), NewUserMessage("Review the change"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "approve", lastMessageContent(messages))
+}
+
+func TestExecuteParallelAggregator(t *testing.T) {
+	judge := &fakeMember{name: "judge", responses: []string{"Release next week"}}
+	team := parallelTeam(&arkv1alpha1.TeamParallelSpec{Aggregation: AggregationAggregator},
+		&fakeMember{name: "optimist", responses: []string{"Ship it"}},
+		&fakeMember{name: "skeptic", responses: []string{"Wait a week"}},
+	)
+	team.Aggregator = judge
+
+	messages, err := team.executeParallel(context.Background(), NewUserMessage("Should we release?"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, judge.calls)
+	assert.Equal(t, "Release next week", lastMessageContent(messages))
+}
+
+// modelServerClient returns a client holding an OpenAI model of the given name, served by a server that answers
+// every call with content and 42 tokens of usage
+func modelServerClient(t *testing.T, name, content string) client.Client {
+	reply, err := json.Marshal(map[string]any{
+		"choices": []map[string]any{{"index": 0, "finish_reason": "stop", "message": map[string]any{"role": "assistant", "content": content}}},
+		"usage":   map[string]any{"prompt_tokens": 30, "completion_tokens": 12, "total_tokens": 42},
+	})
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(reply)
+	}))
+	t.Cleanup(server.Close)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(&arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:  ModelTypeOpenAI,
+			Model: arkv1alpha1.ValueSource{Value: "gpt-4o"},
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: server.URL + "/v1"},
+				APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+			}},
+		},
+	}).Build()
+}
+
+func TestExecuteParallelModelAggregationIsTracked(t *testing.T) {
+	team := parallelTeam(&arkv1alpha1.TeamParallelSpec{Aggregation: AggregationModel, Model: "judge"},
+		&fakeMember{name: "optimist", responses: []string{"Ship it"}},
+	)
+	team.Client = modelServerClient(t, "judge", "Release next week")
+	collector := NewTokenUsageCollector(&mockRecorder{})
+	team.Recorder = collector
+
+	messages, err := team.executeParallel(context.Background(), NewUserMessage("Should we release?"), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Release next week", lastMessageContent(messages))
+	assert.Equal(t, int64(42), collector.GetTokenSummary().TotalTokens, "the aggregation call counts towards the usage")
+}
+
+func TestExecuteParallelMemberFailure(t *testing.T) {
+	team := parallelTeam(nil,
+		&fakeMember{name: "optimist", responses: []string{"Ship it"}},
+		&fakeMember{name: "skeptic", err: errors.New("model unavailable")},
+	)
+
+	_, err := team.executeParallel(context.Background(), NewUserMessage("Should we release?"), nil)
+	assert.ErrorContains(t, err, "member skeptic of team default/ensemble failed: model unavailable")
+}
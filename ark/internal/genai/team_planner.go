@@ -0,0 +1,147 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const plannerInstructions = `You manage a team with the following members:
+%s
+
+Break the request into subtasks and give each subtask to the best member with the delegate tool. Each member only sees the task you give it, so include everything it needs. Use the results to decide what to do next. When the request is complete, call terminate with the final response.`
+
+// DelegateExecutor gives a subtask to a member of a planner team and returns the member's response
+type DelegateExecutor struct {
+	Team *Team
+
+	mu    sync.Mutex
+	turns int
+}
+
+func (d *DelegateExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var arguments struct {
+		Member string `json:"member"`
+		Task   string `json:"task"`
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("failed to parse delegate arguments: %w", err)
+	}
+
+	var member TeamMember
+	for _, candidate := range d.Team.Members {
+		if candidate.GetName() == arguments.Member {
+			member = candidate
+			break
+		}
+	}
+	if member == nil {
+		// Let the manager correct the call rather than failing the team
+		return ToolResult{
+			ID:      call.ID,
+			Name:    call.Function.Name,
+			Content: fmt.Sprintf("member %s not found, choose one of %s", arguments.Member, buildParticipants(d.Team.Members)),
+		}, nil
+	}
+
+	turn, err := d.nextTurn(ctx)
+	if err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	rec := NewExecutionRecorder(d.Team.Recorder)
+	rec.ParticipantSelected(ctx, d.Team.FullName(), member.GetName(), "planner")
+
+	messages, err := d.Team.executeMember(ctx, member, NewUserMessage(arguments.Task), nil, turn)
+	if err != nil && !IsTerminateTeam(err) && !IsTeamHandoff(err) {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("member %s failed: %w", member.GetName(), err)
+	}
+
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: lastMessageContent(messages)}, nil
+}
+
+// nextTurn counts a delegation against the team's MaxTurns
+func (d *DelegateExecutor) nextTurn(ctx context.Context) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.Team.MaxTurns != nil && d.turns >= *d.Team.MaxTurns {
+		turnTracker := NewExecutionRecorder(d.Team.Recorder)
+		turnTracker.TeamTurn(ctx, "MaxTurns", d.Team.FullName(), d.Team.Strategy, d.turns)
+		return 0, fmt.Errorf("team planner MaxTurns %d reached for team %s", *d.Team.MaxTurns, d.Team.GetName())
+	}
+	turn := d.turns
+	d.turns++
+	return turn, nil
+}
+
+func GetDelegateTool(members []TeamMember) ToolDefinition {
+	names := make([]string, 0, len(members))
+	for _, member := range members {
+		names = append(names, member.GetName())
+	}
+
+	return ToolDefinition{
+		Name:        "delegate",
+		Description: "Give a subtask to a team member and wait for its response",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"member": map[string]any{
+					"type":        "string",
+					"enum":        names,
+					"description": "Name of the member to give the subtask to",
+				},
+				"task": map[string]any{
+					"type":        "string",
+					"description": "The subtask, with all the context the member needs to complete it",
+				},
+			},
+			"required": []string{"member", "task"},
+		},
+	}
+}
+
+func (t *Team) executePlanner(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
+	if t.Manager == nil {
+		return nil, fmt.Errorf("team %s uses the planner strategy without a manager", t.FullName())
+	}
+
+	turnTracker := NewExecutionRecorder(t.Recorder)
+	turnTracker.TeamTurn(ctx, "Start", t.FullName(), t.Strategy, 0)
+
+	// The manager runs with the delegate and terminate tools on top of its own, without changing the loaded agent
+	manager := *t.Manager
+	manager.Prompt = strings.TrimSpace(manager.Prompt + "\n\n" + fmt.Sprintf(plannerInstructions, buildRoles(t.Members)))
+	manager.Tools = NewToolRegistry()
+	if t.Manager.Tools != nil {
+		manager.Tools = t.Manager.Tools.clone()
+	}
+	manager.Tools.RegisterTool(GetDelegateTool(t.Members), &DelegateExecutor{Team: t})
+	manager.Tools.RegisterTool(GetTerminateTool(), &TerminateExecutor{})
+
+	messages, err := manager.Execute(ctx, userInput, history)
+	if IsTerminateTeam(err) {
+		return messages, nil
+	}
+	return messages, err
+}
+
+func loadPlannerManager(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, recorder EventEmitter) (*Agent, error) {
+	var agentCRD arkv1alpha1.Agent
+	key := types.NamespacedName{Name: crd.Spec.Planner.Manager, Namespace: crd.Namespace}
+	if err := k8sClient.Get(ctx, key, &agentCRD); err != nil {
+		return nil, fmt.Errorf("failed to get manager agent %s for team %s: %w", crd.Spec.Planner.Manager, crd.Name, err)
+	}
+	if agentCRD.Spec.ExecutionEngine != nil {
+		return nil, fmt.Errorf("manager agent %s of team %s cannot use an execution engine", agentCRD.Name, crd.Name)
+	}
+	return MakeAgent(ctx, k8sClient, &agentCRD, recorder)
+}
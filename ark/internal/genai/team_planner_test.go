@@ -0,0 +1,76 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func plannerCall(id, name string, arguments map[string]string) openai.ChatCompletionMessageToolCall {
+	encoded, _ := json.Marshal(arguments)
+	call := newToolCall(id, name)
+	call.Function.Arguments = string(encoded)
+	return call
+}
+
+func plannerTeam(provider *fakeProvider, maxTurns *int, members ...TeamMember) *Team {
+	return &Team{
+		Name:      "research",
+		Namespace: "default",
+		Strategy:  "planner",
+		Members:   members,
+		MaxTurns:  maxTurns,
+		Recorder:  &mockRecorder{},
+		Manager: &Agent{
+			Name:      "manager",
+			Namespace: "default",
+			Prompt:    "You lead a research team.",
+			Model:     &Model{Model: "planner", Type: ModelTypeOpenAI, Provider: provider},
+			Tools:     NewToolRegistry(),
+			Recorder:  &mockRecorder{},
+		},
+	}
+}
+
+func TestExecutePlanner(t *testing.T) {
+	researcher := &fakeMember{name: "researcher", responses: []string{"three sources"}}
+	writer := &fakeMember{name: "writer", responses: []string{"a summary"}}
+	provider := &fakeProvider{toolCalls: [][]openai.ChatCompletionMessageToolCall{
+		{plannerCall("1", "delegate", map[string]string{"member": "researcher", "task": "find sources"})},
+		{plannerCall("2", "delegate", map[string]string{"member": "editor", "task": "edit"})},
+		{plannerCall("3", "delegate", map[string]string{"member": "writer", "task": "summarize three sources"})},
+		{plannerCall("4", "terminate", map[string]string{"response": "Here is a summary"})},
+	}}
+	team := plannerTeam(provider, nil, researcher, writer)
+
+	messages, err := team.executePlanner(context.Background(), NewUserMessage("Research the topic"), nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, researcher.calls)
+	assert.Equal(t, 1, writer.calls)
+	assert.Equal(t, []string{"three sources", "member editor not found, choose one of researcher, writer", "a summary"}, provider.toolResults)
+	assert.Equal(t, "Here is a summary", lastMessageContent(messages))
+
+	// The loaded manager keeps its own tools
+	assert.Empty(t, team.Manager.Tools.GetToolDefinitions())
+}
+
+func TestExecutePlannerMaxTurns(t *testing.T) {
+	researcher := &fakeMember{name: "researcher", responses: []string{"more sources"}}
+	provider := &fakeProvider{toolCalls: [][]openai.ChatCompletionMessageToolCall{
+		{plannerCall("1", "delegate", map[string]string{"member": "researcher", "task": "find sources"})},
+		{plannerCall("2", "delegate", map[string]string{"member": "researcher", "task": "find more sources"})},
+	}}
+	maxTurns := 1
+	team := plannerTeam(provider, &maxTurns, researcher)
+
+	_, err := team.executePlanner(context.Background(), NewUserMessage("Research the topic"), nil)
+	assert.ErrorContains(t, err, "team planner MaxTurns 1 reached")
+	assert.Equal(t, 1, researcher.calls)
+}
@@ -0,0 +1,214 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/openai/openai-go/packages/param"
+)
+
+const (
+	scratchpadKey contextKey = "scratchpad"
+	teamMemberKey contextKey = "teamMember"
+)
+
+// Scratchpad is state shared by the members of a team. Members read and write it through the scratchpad tool, and
+// it outlives the turns of the team, so members can pass on facts without repeating the conversation.
+type Scratchpad struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func NewScratchpad() *Scratchpad {
+	return &Scratchpad{values: map[string]string{}}
+}
+
+func (s *Scratchpad) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+func (s *Scratchpad) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+func (s *Scratchpad) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Snapshot returns a copy of the values, or nil when the scratchpad is empty
+func (s *Scratchpad) Snapshot() map[string]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.values) == 0 {
+		return nil
+	}
+	return maps.Clone(s.values)
+}
+
+func WithScratchpad(ctx context.Context, scratchpad *Scratchpad) context.Context {
+	return context.WithValue(ctx, scratchpadKey, scratchpad)
+}
+
+func getScratchpad(ctx context.Context) *Scratchpad {
+	if scratchpad, ok := ctx.Value(scratchpadKey).(*Scratchpad); ok {
+		return scratchpad
+	}
+	return nil
+}
+
+// withTeamMember marks ctx as the execution of a team member, which is what allows a handoff
+func withTeamMember(ctx context.Context, isMember bool) context.Context {
+	return context.WithValue(ctx, teamMemberKey, isMember)
+}
+
+func isTeamMember(ctx context.Context) bool {
+	isMember, _ := ctx.Value(teamMemberKey).(bool)
+	return isMember
+}
+
+type ScratchpadExecutor struct{}
+
+func (e *ScratchpadExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var arguments struct {
+		Operation string `json:"operation"`
+		Key       string `json:"key"`
+		Value     string `json:"value"`
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("failed to parse scratchpad arguments: %w", err)
+	}
+
+	scratchpad := getScratchpad(ctx)
+	if scratchpad == nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Content: "no scratchpad is available to this execution"}, nil
+	}
+
+	// Mistakes in the arguments are reported to the model so it can correct the call
+	var content string
+	switch arguments.Operation {
+	case "read":
+		if arguments.Key == "" {
+			values, err := json.Marshal(scratchpad.Snapshot())
+			if err != nil {
+				return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+			}
+			content = string(values)
+		} else if value, ok := scratchpad.Get(arguments.Key); ok {
+			content = value
+		} else {
+			content = fmt.Sprintf("key %s is not set", arguments.Key)
+		}
+	case "write":
+		if arguments.Key == "" {
+			content = "key must be set to write to the scratchpad"
+			break
+		}
+		scratchpad.Set(arguments.Key, arguments.Value)
+		content = fmt.Sprintf("key %s written", arguments.Key)
+	case "delete":
+		scratchpad.Delete(arguments.Key)
+		content = fmt.Sprintf("key %s deleted", arguments.Key)
+	default:
+		content = fmt.Sprintf("unsupported operation %s, use read, write or delete", arguments.Operation)
+	}
+
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: content}, nil
+}
+
+func GetScratchpadTool() ToolDefinition {
+	return ToolDefinition{
+		Name:        "scratchpad",
+		Description: "Read and write notes shared with the other members of the team. Reading without a key returns every note.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"operation": map[string]any{
+					"type": "string",
+					"enum": []string{"read", "write", "delete"},
+				},
+				"key": map[string]any{
+					"type":        "string",
+					"description": "Name of the note",
+				},
+				"value": map[string]any{
+					"type":        "string",
+					"description": "Content of the note, for write",
+				},
+			},
+			"required": []string{"operation"},
+		},
+	}
+}
+
+// TeamHandoff ends the turn of a team member. The members that follow see the summary instead of the conversation
+// so far.
+type TeamHandoff struct {
+	Summary string
+}
+
+func (e *TeamHandoff) Error() string {
+	return "TeamHandoff"
+}
+
+func IsTeamHandoff(err error) bool {
+	if err == nil {
+		return false
+	}
+	var handoffErr *TeamHandoff
+	return errors.As(err, &handoffErr)
+}
+
+type HandoffExecutor struct{}
+
+func (e *HandoffExecutor) Execute(ctx context.Context, call ToolCall) (ToolResult, error) {
+	var arguments struct {
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, fmt.Errorf("failed to parse handoff arguments: %w", err)
+	}
+
+	if !isTeamMember(ctx) {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Content: "handoff is only available to team members, answer directly instead"}, nil
+	}
+	if arguments.Summary == "" {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Content: "summary must be set to hand off"}, nil
+	}
+
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: arguments.Summary}, &TeamHandoff{Summary: arguments.Summary}
+}
+
+func GetHandoffTool() ToolDefinition {
+	return ToolDefinition{
+		Name:        "handoff",
+		Description: "End your turn and hand off to the next member of the team. The next members see your summary instead of the conversation so far.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"summary": map[string]any{
+					"type":        "string",
+					"description": "Everything the next members need to know: the request, what was done and what is left",
+				},
+			},
+			"required": []string{"summary"},
+		},
+	}
+}
+
+// handoffMessages returns the history that replaces the conversation after a member hands off
+func handoffMessages(member TeamMember, handoff *TeamHandoff) []Message {
+	message := NewAssistantMessage(handoff.Summary)
+	message.OfAssistant.Name = param.Opt[string]{Value: member.GetName()}
+	return []Message{message}
+}
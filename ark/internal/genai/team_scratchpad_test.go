@@ -0,0 +1,77 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scratchpadCall(arguments map[string]string) ToolCall {
+	encoded, _ := json.Marshal(arguments)
+	call := ToolCall(newToolCall("1", "scratchpad"))
+	call.Function.Arguments = string(encoded)
+	return call
+}
+
+func TestScratchpadExecutor(t *testing.T) {
+	scratchpad := NewScratchpad()
+	ctx := WithScratchpad(context.Background(), scratchpad)
+	executor := &ScratchpadExecutor{}
+
+	result, err := executor.Execute(ctx, scratchpadCall(map[string]string{"operation": "write", "key": "sources", "value": "three papers"}))
+	require.NoError(t, err)
+	assert.Equal(t, "key sources written", result.Content)
+
+	result, err = executor.Execute(ctx, scratchpadCall(map[string]string{"operation": "read", "key": "sources"}))
+	require.NoError(t, err)
+	assert.Equal(t, "three papers", result.Content)
+
+	result, err = executor.Execute(ctx, scratchpadCall(map[string]string{"operation": "read"}))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"sources": "three papers"}`, result.Content)
+
+	result, err = executor.Execute(ctx, scratchpadCall(map[string]string{"operation": "read", "key": "draft"}))
+	require.NoError(t, err)
+	assert.Equal(t, "key draft is not set", result.Content)
+
+	_, err = executor.Execute(ctx, scratchpadCall(map[string]string{"operation": "delete", "key": "sources"}))
+	require.NoError(t, err)
+	assert.Nil(t, scratchpad.Snapshot())
+}
+
+func TestTeamHandoffReplacesHistory(t *testing.T) {
+	researcher := &fakeMember{name: "researcher", handoff: "Found three papers on the topic"}
+	writer := &fakeMember{name: "writer"}
+	team := &Team{
+		Name:      "research",
+		Namespace: "default",
+		Strategy:  "sequential",
+		Members:   []TeamMember{researcher, writer},
+		Recorder:  &mockRecorder{},
+	}
+
+	history := []Message{NewUserMessage("an earlier question"), NewAssistantMessage("an earlier answer")}
+	messages, err := team.Execute(context.Background(), NewUserMessage("Summarize the research"), history)
+	require.NoError(t, err)
+
+	require.Len(t, writer.history, 1)
+	assert.Equal(t, "Found three papers on the topic", writer.history[0].OfAssistant.Content.OfString.Value)
+	assert.Equal(t, "researcher", writer.history[0].OfAssistant.Name.Value)
+
+	// The team response still holds every message the members produced
+	assert.Len(t, messages, 3)
+}
+
+func TestHandoffOutsideTeam(t *testing.T) {
+	call := ToolCall(newToolCall("1", "handoff"))
+	call.Function.Arguments = `{"summary": "done"}`
+
+	result, err := (&HandoffExecutor{}).Execute(context.Background(), call)
+	require.NoError(t, err)
+	assert.Contains(t, result.Content, "only available to team members")
+}
@@ -3,9 +3,13 @@ package genai
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"text/template"
+	"unicode"
+
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 const defaultSelectorPrompt = `You are in a role play game. The following roles are available:
@@ -55,6 +59,140 @@ func buildRoles(members []TeamMember) string {
 	return strings.Join(roles, ", ")
 }
 
+// selectorTerminate is the reply with which the selector model ends the team when termination is allowed
+const selectorTerminate = "TERMINATE"
+
+const selectorTerminateInstructions = `If the conversation has fully answered the request and no role needs to play, return ` + selectorTerminate + `.`
+
+const (
+	SelectorFallbackFirst = "first"
+	SelectorFallbackNext  = "next"
+	SelectorFallbackFail  = "fail"
+)
+
+// selectorSchema constrains the selector model's reply to the names of the members, and to TERMINATE when the
+// selector may end the team
+func selectorSchema(members []TeamMember, allowTermination bool) (*runtime.RawExtension, error) {
+	names := make([]string, 0, len(members)+1)
+	for _, member := range members {
+		names = append(names, member.GetName())
+	}
+	if allowTermination {
+		names = append(names, selectorTerminate)
+	}
+
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"next": map[string]any{
+				"type":        "string",
+				"enum":        names,
+				"description": "Name of the role to play next",
+			},
+		},
+		"required":             []string{"next"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: schema}, nil
+}
+
+// parseSelectorResponse reads the selected name from the structured reply. Models that ignore the schema reply
+// with free text, which is used as is.
+func parseSelectorResponse(content string) string {
+	var reply struct {
+		Next string `json:"next"`
+	}
+	if err := json.Unmarshal([]byte(content), &reply); err == nil && reply.Next != "" {
+		return strings.TrimSpace(reply.Next)
+	}
+	return strings.TrimSpace(content)
+}
+
+// matchMember finds the member named by the selector model. An exact match is preferred, then a case-insensitive
+// one, then a fuzzy one: the only member whose name appears in the reply, or the only member whose name is
+// within two edits of it. It returns -1 when no member matches.
+func matchMember(members []TeamMember, selectedName string) (int, string) {
+	for i, member := range members {
+		if member.GetName() == selectedName {
+			return i, "exact_match"
+		}
+	}
+
+	for i, member := range members {
+		if strings.EqualFold(member.GetName(), selectedName) {
+			return i, "case_insensitive_match"
+		}
+	}
+
+	selected := normalizeMemberName(selectedName)
+	if selected == "" {
+		return -1, ""
+	}
+
+	contained := -1
+	for i, member := range members {
+		if name := normalizeMemberName(member.GetName()); name != "" && strings.Contains(selected, name) {
+			if contained >= 0 {
+				contained = -1
+				break
+			}
+			contained = i
+		}
+	}
+	if contained >= 0 {
+		return contained, "fuzzy_match"
+	}
+
+	closest, closestDistance, unique := -1, 3, false
+	for i, member := range members {
+		distance := editDistance(selected, normalizeMemberName(member.GetName()))
+		switch {
+		case distance < closestDistance:
+			closest, closestDistance, unique = i, distance, true
+		case distance == closestDistance:
+			unique = false
+		}
+	}
+	if closest >= 0 && unique {
+		return closest, "fuzzy_match"
+	}
+	return -1, ""
+}
+
+func normalizeMemberName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
 func (t *Team) selectMember(ctx context.Context, messages []Message, tmpl *template.Template, participantsList, rolesList, previousMember string) (TeamMember, int, error) {
 	history := buildHistory(messages)
 	data := SelectorTemplateData{
@@ -68,50 +206,123 @@ func (t *Team) selectMember(ctx context.Context, messages []Message, tmpl *templ
 		return nil, 0, err
 	}
 
+	prompt := buf.String()
+	allowTermination := t.Selector != nil && t.Selector.AllowTermination
+	if allowTermination {
+		prompt += "\n\n" + selectorTerminateInstructions
+	}
+
 	model, err := LoadModel(ctx, t.Client, t.Selector, t.Namespace)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	schema, err := selectorSchema(t.Members, allowTermination)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build selector schema: %w", err)
+	}
+	model.OutputSchema = schema
+	model.SchemaName = "team_selector"
+
 	selectorMessages := []Message{
-		NewSystemMessage(buf.String()),
+		NewSystemMessage(prompt),
 		NewUserMessage("Select the next participant to respond."),
 	}
 
-	response, err := model.ChatCompletion(ctx, selectorMessages, nil)
-	if err != nil {
-		return nil, 0, fmt.Errorf("selector model call failed: %w", err)
-	}
+	return t.chooseMember(ctx, model, selectorMessages, participantsList, previousMember)
+}
 
-	if len(response.Choices) == 0 {
-		return nil, 0, fmt.Errorf("selector model returned no choices")
+// chooseMember asks the selector model for the next member, asking again up to MaxRetries times when the reply
+// names no member, and then applies the fallback policy
+func (t *Team) chooseMember(ctx context.Context, model *Model, selectorMessages []Message, participantsList, previousMember string) (TeamMember, int, error) {
+	if len(t.Members) == 0 {
+		return nil, 0, fmt.Errorf("no members available")
 	}
 
-	selectedName := strings.TrimSpace(response.Choices[0].Message.Content)
+	maxRetries := 1
+	if t.Selector != nil && t.Selector.MaxRetries != nil {
+		maxRetries = *t.Selector.MaxRetries
+	}
+	allowTermination := t.Selector != nil && t.Selector.AllowTermination
 	rec := NewExecutionRecorder(t.Recorder)
-	rec.SelectorModelResponse(ctx, t.FullName(), model.Model, selectedName, participantsList)
 
-	// Find selected member
-	for i, member := range t.Members {
-		if member.GetName() == selectedName {
-			rec.ParticipantSelected(ctx, t.FullName(), selectedName, "exact_match")
-			return member, i, nil
+	for attempt := 0; ; attempt++ {
+		llmTracker := NewOperationTracker(t.Recorder, ctx, "LLMCall", model.Model, map[string]string{
+			"team":    t.FullName(),
+			"model":   model.Model,
+			"purpose": "member_selection",
+		})
+
+		response, usedModel, err := model.ChatCompletionWithFailover(ctx, selectorMessages, nil, nil, nil)
+		if err != nil {
+			llmTracker.Fail(err)
+			return nil, 0, fmt.Errorf("selector model call failed: %w", err)
 		}
+
+		if len(response.Choices) == 0 {
+			err := fmt.Errorf("selector model returned no choices")
+			llmTracker.Fail(err)
+			return nil, 0, err
+		}
+		llmTracker.CompleteWithModel("", usedModel.TokenUsage(response.Usage), usedModel.Name)
+
+		content := response.Choices[0].Message.Content
+		selectedName := parseSelectorResponse(content)
+		rec.SelectorModelResponse(ctx, t.FullName(), model.Model, selectedName, participantsList)
+
+		if allowTermination && strings.EqualFold(selectedName, selectorTerminate) {
+			// A team that ends before any member has answered has no response, so the fallback picks a member
+			if previousMember != "" {
+				return nil, 0, &TerminateTeam{}
+			}
+			break
+		}
+
+		if index, reason := matchMember(t.Members, selectedName); index >= 0 {
+			rec.ParticipantSelected(ctx, t.FullName(), t.Members[index].GetName(), reason)
+			return t.Members[index], index, nil
+		}
+
+		if attempt >= maxRetries {
+			break
+		}
+		selectorMessages = append(selectorMessages,
+			NewAssistantMessage(content),
+			NewUserMessage(fmt.Sprintf("%q is not a participant. Select one of %s.", selectedName, participantsList)),
+		)
 	}
 
-	// Fallback to first member if not found
-	if len(t.Members) > 0 {
-		fallback := t.Members[0]
-		rec.ParticipantSelected(ctx, t.FullName(), fallback.GetName(), "fallback_no_match")
+	return t.fallbackMember(ctx, previousMember)
+}
+
+// fallbackMember applies the fallback policy when the selector model names no member
+func (t *Team) fallbackMember(ctx context.Context, previousMember string) (TeamMember, int, error) {
+	policy := SelectorFallbackFirst
+	if t.Selector != nil && t.Selector.Fallback != "" {
+		policy = t.Selector.Fallback
+	}
 
+	index := 0
+	switch policy {
+	case SelectorFallbackFail:
+		return nil, 0, fmt.Errorf("selector model did not select a member of team %s", t.FullName())
+	case SelectorFallbackNext:
+		for i, member := range t.Members {
+			if member.GetName() == previousMember {
+				index = (i + 1) % len(t.Members)
+				break
+			}
+		}
+	default:
 		// Avoid repeating same member
-		if fallback.GetName() == previousMember && len(t.Members) > 1 {
-			fallback = t.Members[1]
+		if t.Members[0].GetName() == previousMember && len(t.Members) > 1 {
+			index = 1
 		}
-		return fallback, 0, nil
 	}
 
-	return nil, 0, fmt.Errorf("no members available")
+	rec := NewExecutionRecorder(t.Recorder)
+	rec.ParticipantSelected(ctx, t.FullName(), t.Members[index].GetName(), "fallback_no_match")
+	return t.Members[index], index, nil
 }
 
 func (t *Team) executeSelector(ctx context.Context, userInput Message, history []Message) ([]Message, error) {
@@ -138,6 +349,10 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 
 		nextMember, memberIndex, err := t.selectMember(ctx, messages, tmpl, participantsList, rolesList, previousMember)
 		if err != nil {
+			if IsTerminateTeam(err) {
+				turnTracker.TeamTurn(ctx, "Terminate", t.FullName(), t.Strategy, turn)
+				return newMessages, nil
+			}
 			return newMessages, err
 		}
 
@@ -0,0 +1,160 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func selectorTeam(selector *arkv1alpha1.TeamSelectorSpec) *Team {
+	return &Team{
+		Name:      "review",
+		Namespace: "default",
+		Strategy:  "selector",
+		Members: []TeamMember{
+			&fakeMember{name: "researcher", responses: []string{"sources"}},
+			&fakeMember{name: "tech-writer", responses: []string{"draft"}},
+		},
+		Selector: selector,
+		Recorder: &mockRecorder{},
+	}
+}
+
+func TestMatchMember(t *testing.T) {
+	members := selectorTeam(nil).Members
+
+	tests := []struct {
+		reply  string
+		index  int
+		reason string
+	}{
+		{"researcher", 0, "exact_match"},
+		{"Tech-Writer", 1, "case_insensitive_match"},
+		{"I choose the researcher.", 0, "fuzzy_match"},
+		{"tech writer", 1, "fuzzy_match"},
+		{"reseacher", 0, "fuzzy_match"},
+		{"editor", -1, ""},
+		{"", -1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			index, reason := matchMember(members, tt.reply)
+			assert.Equal(t, tt.index, index)
+			assert.Equal(t, tt.reason, reason)
+		})
+	}
+}
+
+func TestChooseMember(t *testing.T) {
+	selectorMessages := []Message{NewUserMessage("Select the next participant to respond.")}
+	maxRetries := func(n int) *int { return &n }
+
+	t.Run("structured reply", func(t *testing.T) {
+		team := selectorTeam(nil)
+		provider := &fakeProvider{replies: []string{`{"next": "tech-writer"}`}}
+		schema, err := selectorSchema(team.Members, false)
+		require.NoError(t, err)
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider, OutputSchema: schema}
+
+		member, index, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		require.NoError(t, err)
+		assert.Equal(t, "tech-writer", member.GetName())
+		assert.Equal(t, 1, index)
+		assert.Equal(t, 1, provider.schemaCalls)
+	})
+
+	t.Run("retries before matching", func(t *testing.T) {
+		team := selectorTeam(nil)
+		provider := &fakeProvider{replies: []string{"editor", "researcher"}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		member, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		require.NoError(t, err)
+		assert.Equal(t, "researcher", member.GetName())
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("every selector call counts towards the usage", func(t *testing.T) {
+		team := selectorTeam(nil)
+		collector := NewTokenUsageCollector(&mockRecorder{})
+		team.Recorder = collector
+		provider := &fakeProvider{replies: []string{"editor", "researcher"}, usage: testUsage}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		_, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		require.NoError(t, err)
+		assert.Equal(t, int64(30), collector.GetTokenSummary().TotalTokens)
+	})
+
+	t.Run("first fallback avoids the previous member", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{MaxRetries: maxRetries(0)})
+		provider := &fakeProvider{replies: []string{"editor"}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		member, index, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "researcher")
+		require.NoError(t, err)
+		assert.Equal(t, "tech-writer", member.GetName())
+		assert.Equal(t, 1, index)
+		assert.Equal(t, 1, provider.calls)
+	})
+
+	t.Run("next fallback", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{MaxRetries: maxRetries(0), Fallback: SelectorFallbackNext})
+		provider := &fakeProvider{replies: []string{"editor"}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		member, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "tech-writer")
+		require.NoError(t, err)
+		assert.Equal(t, "researcher", member.GetName())
+	})
+
+	t.Run("fail fallback", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{MaxRetries: maxRetries(2), Fallback: SelectorFallbackFail})
+		provider := &fakeProvider{replies: []string{"editor"}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		_, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		assert.ErrorContains(t, err, "selector model did not select a member of team default/review")
+		assert.Equal(t, 3, provider.calls)
+	})
+
+	t.Run("termination", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{AllowTermination: true})
+		provider := &fakeProvider{replies: []string{`{"next": "TERMINATE"}`}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		_, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "researcher")
+		assert.True(t, IsTerminateTeam(err))
+	})
+
+	t.Run("termination before any member answered falls back", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{AllowTermination: true})
+		provider := &fakeProvider{replies: []string{`{"next": "TERMINATE"}`}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		member, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		require.NoError(t, err)
+		assert.Equal(t, "researcher", member.GetName())
+
+		team.Selector.Fallback = SelectorFallbackFail
+		_, _, err = team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		assert.False(t, IsTerminateTeam(err))
+		assert.ErrorContains(t, err, "did not select a member")
+	})
+
+	t.Run("termination not allowed", func(t *testing.T) {
+		team := selectorTeam(&arkv1alpha1.TeamSelectorSpec{MaxRetries: maxRetries(0), Fallback: SelectorFallbackFail})
+		provider := &fakeProvider{replies: []string{"TERMINATE"}}
+		model := &Model{Model: "selector", Type: ModelTypeOpenAI, Provider: provider}
+
+		_, _, err := team.chooseMember(context.Background(), model, selectorMessages, "researcher, tech-writer", "")
+		assert.False(t, IsTerminateTeam(err))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,298 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	TokenBudgetScopeNamespace      = "namespace"
+	TokenBudgetScopeServiceAccount = "serviceAccount"
+	TokenBudgetScopeAgent          = "agent"
+
+	defaultTokenBudgetWindow = 24 * time.Hour
+)
+
+// TokenBudgetExceeded is returned when queries have used up what a TokenBudget allows
+type TokenBudgetExceeded struct {
+	Budget string
+	Reason string
+}
+
+func (e *TokenBudgetExceeded) Error() string {
+	return fmt.Sprintf("token budget %s exceeded: %s", e.Budget, e.Reason)
+}
+
+func IsTokenBudgetExceeded(err error) bool {
+	if err == nil {
+		return false
+	}
+	var budgetErr *TokenBudgetExceeded
+	return errors.As(err, &budgetErr)
+}
+
+// TokenBudgetWindow returns the rolling window a budget counts usage over
+func TokenBudgetWindow(budget *arkv1alpha1.TokenBudget) time.Duration {
+	if budget.Spec.Window == nil || budget.Spec.Window.Duration <= 0 {
+		return defaultTokenBudgetWindow
+	}
+	return budget.Spec.Window.Duration
+}
+
+// ValidateTokenBudget checks the parts of a budget the CRD schema cannot
+func ValidateTokenBudget(budget *arkv1alpha1.TokenBudget) error {
+	switch budget.Spec.Scope.Type {
+	case "", TokenBudgetScopeNamespace:
+	case TokenBudgetScopeServiceAccount, TokenBudgetScopeAgent:
+		if budget.Spec.Scope.Name == "" {
+			return fmt.Errorf("scope %s requires a name", budget.Spec.Scope.Type)
+		}
+	default:
+		return fmt.Errorf("unsupported scope type %q", budget.Spec.Scope.Type)
+	}
+
+	if budget.Spec.MaxTokens == nil && budget.Spec.MaxCost == "" {
+		return fmt.Errorf("at least one of maxTokens or maxCost must be set")
+	}
+	if budget.Spec.MaxCost != "" {
+		if _, err := strconv.ParseFloat(budget.Spec.MaxCost, 64); err != nil {
+			return fmt.Errorf("invalid maxCost %q: %w", budget.Spec.MaxCost, err)
+		}
+	}
+	return nil
+}
+
+// TokenBudgetCoversTarget reports whether usage of the given query target counts towards budget
+func TokenBudgetCoversTarget(budget *arkv1alpha1.TokenBudget, query *arkv1alpha1.Query, target arkv1alpha1.QueryTarget) bool {
+	switch budget.Spec.Scope.Type {
+	case TokenBudgetScopeAgent:
+		return target.Type == "agent" && target.Name == budget.Spec.Scope.Name
+	case TokenBudgetScopeServiceAccount:
+		return queryServiceAccount(query) == budget.Spec.Scope.Name
+	default:
+		return true
+	}
+}
+
+// TokenBudgetCoversQuery reports whether any usage of query counts towards budget. targets are the targets of the
+// query, including those its selector picks.
+func TokenBudgetCoversQuery(budget *arkv1alpha1.TokenBudget, query *arkv1alpha1.Query, targets []arkv1alpha1.QueryTarget) bool {
+	if budget.Spec.Scope.Type != TokenBudgetScopeAgent {
+		return TokenBudgetCoversTarget(budget, query, arkv1alpha1.QueryTarget{})
+	}
+	for _, target := range targets {
+		if TokenBudgetCoversTarget(budget, query, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTokenBudget returns a TokenBudgetExceeded error once usage has reached one of the budget's limits
+func CheckTokenBudget(budget *arkv1alpha1.TokenBudget, usage TokenUsage) error {
+	window := TokenBudgetWindow(budget)
+	if budget.Spec.MaxTokens != nil && usage.TotalTokens >= *budget.Spec.MaxTokens {
+		return &TokenBudgetExceeded{
+			Budget: budget.Name,
+			Reason: fmt.Sprintf("used %d of %d tokens in the last %s", usage.TotalTokens, *budget.Spec.MaxTokens, window),
+		}
+	}
+	if budget.Spec.MaxCost != "" {
+		maxCost, err := strconv.ParseFloat(budget.Spec.MaxCost, 64)
+		if err == nil && usage.Cost >= maxCost {
+			return &TokenBudgetExceeded{
+				Budget: budget.Name,
+				Reason: fmt.Sprintf("used %s of %s in the last %s", FormatCost(usage.Cost), budget.Spec.MaxCost, window),
+			}
+		}
+	}
+	return nil
+}
+
+// LoadTokenBudgets returns the budgets of a namespace. Invalid budgets are left out, their status reports why.
+func LoadTokenBudgets(ctx context.Context, k8sClient client.Client, namespace string) ([]arkv1alpha1.TokenBudget, error) {
+	var budgets arkv1alpha1.TokenBudgetList
+	if err := k8sClient.List(ctx, &budgets, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list token budgets in namespace %s: %w", namespace, err)
+	}
+
+	valid := make([]arkv1alpha1.TokenBudget, 0, len(budgets.Items))
+	for _, budget := range budgets.Items {
+		if ValidateTokenBudget(&budget) == nil {
+			valid = append(valid, budget)
+		}
+	}
+	return valid, nil
+}
+
+// TokenBudgetUsage adds up what the queries and workflows covered by budget and created within its window have
+// used. What the budget has recorded counts while they run and after they are deleted. Finished queries and workflows
+// it has no record of, such as the ones that ran before it was created, count by the usage in their status.
+func TokenBudgetUsage(ctx context.Context, k8sClient client.Client, budget *arkv1alpha1.TokenBudget, now time.Time) (TokenUsage, error) {
+	usage, err := UnrecordedTokenBudgetUsage(ctx, k8sClient, budget, now)
+	if err != nil {
+		return TokenUsage{}, err
+	}
+	usage.Add(RecordedTokenBudgetUsage(budget, now))
+	return usage, nil
+}
+
+// RecordedTokenBudgetUsage adds up the usage of the consumers in the status of budget that are within its window
+func RecordedTokenBudgetUsage(budget *arkv1alpha1.TokenBudget, now time.Time) TokenUsage {
+	var usage TokenUsage
+	for _, consumer := range ActiveTokenBudgetConsumers(budget, now) {
+		usage.Add(FromStatusTokenUsage(consumer.TokenUsage, consumer.Cost))
+	}
+	return usage
+}
+
+// UnrecordedTokenBudgetUsage adds up the usage reported in the status of the queries and workflows covered by
+// budget that are not among its consumers
+func UnrecordedTokenBudgetUsage(ctx context.Context, k8sClient client.Client, budget *arkv1alpha1.TokenBudget, now time.Time) (TokenUsage, error) {
+	var queries arkv1alpha1.QueryList
+	if err := k8sClient.List(ctx, &queries, client.InNamespace(budget.Namespace)); err != nil {
+		return TokenUsage{}, fmt.Errorf("failed to list queries in namespace %s: %w", budget.Namespace, err)
+	}
+	var workflows arkv1alpha1.WorkflowList
+	if err := k8sClient.List(ctx, &workflows, client.InNamespace(budget.Namespace)); err != nil {
+		return TokenUsage{}, fmt.Errorf("failed to list workflows in namespace %s: %w", budget.Namespace, err)
+	}
+
+	recorded := make(map[types.UID]bool, len(budget.Status.Consumers))
+	for _, consumer := range budget.Status.Consumers {
+		recorded[consumer.UID] = true
+	}
+	since := now.Add(-TokenBudgetWindow(budget))
+	var usage TokenUsage
+	for i := range queries.Items {
+		query := &queries.Items[i]
+		if query.CreationTimestamp.Time.Before(since) || recorded[query.UID] {
+			continue
+		}
+		usage.Add(queryBudgetUsage(budget, query))
+	}
+	for i := range workflows.Items {
+		workflow := &workflows.Items[i]
+		if workflow.CreationTimestamp.Time.Before(since) || recorded[workflow.UID] {
+			continue
+		}
+		usage.Add(workflowBudgetUsage(budget, workflow))
+	}
+	return usage, nil
+}
+
+// ActiveTokenBudgetConsumers returns the consumers of budget that started within its window
+func ActiveTokenBudgetConsumers(budget *arkv1alpha1.TokenBudget, now time.Time) []arkv1alpha1.TokenBudgetConsumer {
+	since := now.Add(-TokenBudgetWindow(budget))
+	var active []arkv1alpha1.TokenBudgetConsumer
+	for _, consumer := range budget.Status.Consumers {
+		if !consumer.Started.Time.Before(since) {
+			active = append(active, consumer)
+		}
+	}
+	return active
+}
+
+// RecordTokenBudgetUsage adds usage to what consumer has used of the budget and drops the consumers that left its
+// window. The budget is read again when another writer updated it first, as queries running in any controller
+// replica record their usage as they go. It returns the budget as written.
+func RecordTokenBudgetUsage(ctx context.Context, k8sClient client.Client, key types.NamespacedName, consumer arkv1alpha1.TokenBudgetConsumer, usage TokenUsage) (*arkv1alpha1.TokenBudget, error) {
+	var budget arkv1alpha1.TokenBudget
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := k8sClient.Get(ctx, key, &budget); err != nil {
+			return err
+		}
+
+		consumers := ActiveTokenBudgetConsumers(&budget, time.Now())
+		index := slices.IndexFunc(consumers, func(existing arkv1alpha1.TokenBudgetConsumer) bool {
+			return existing.UID == consumer.UID
+		})
+		if index < 0 {
+			consumers = append(consumers, consumer)
+			index = len(consumers) - 1
+		}
+		total := FromStatusTokenUsage(consumers[index].TokenUsage, consumers[index].Cost)
+		total.Add(usage)
+		consumers[index].TokenUsage = arkv1alpha1.TokenUsage{
+			PromptTokens:     total.PromptTokens,
+			CompletionTokens: total.CompletionTokens,
+			TotalTokens:      total.TotalTokens,
+		}
+		consumers[index].Cost = FormatCost(total.Cost)
+
+		budget.Status.Consumers = consumers
+		return k8sClient.Status().Update(ctx, &budget)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record usage of token budget %s: %w", key.Name, err)
+	}
+	return &budget, nil
+}
+
+// queryBudgetUsage is the part of a finished query's usage that counts towards budget
+func queryBudgetUsage(budget *arkv1alpha1.TokenBudget, query *arkv1alpha1.Query) TokenUsage {
+	if budget.Spec.Scope.Type != TokenBudgetScopeAgent {
+		if !TokenBudgetCoversQuery(budget, query, nil) {
+			return TokenUsage{}
+		}
+		return FromStatusTokenUsage(query.Status.TokenUsage, query.Status.Cost)
+	}
+
+	var usage TokenUsage
+	for _, response := range query.Status.Responses {
+		if TokenBudgetCoversTarget(budget, query, response.Target) {
+			usage.Add(FromStatusTokenUsage(response.TokenUsage, response.Cost))
+		}
+	}
+	return usage
+}
+
+// workflowBudgetUsage is the part of the usage of a workflow's finished steps that counts towards budget
+func workflowBudgetUsage(budget *arkv1alpha1.TokenBudget, workflow *arkv1alpha1.Workflow) TokenUsage {
+	// The steps run as the workflow's service account, as if each were a query target
+	query := &arkv1alpha1.Query{Spec: arkv1alpha1.QuerySpec{ServiceAccount: workflow.Spec.ServiceAccount}}
+	targets := make(map[string]arkv1alpha1.QueryTarget, len(workflow.Spec.Steps))
+	for _, step := range workflow.Spec.Steps {
+		targets[step.Name] = step.Target
+	}
+
+	var usage TokenUsage
+	for _, step := range workflow.Status.Steps {
+		if TokenBudgetCoversTarget(budget, query, targets[step.Name]) {
+			usage.Add(FromStatusTokenUsage(step.TokenUsage, step.Cost))
+		}
+	}
+	return usage
+}
+
+// FromStatusTokenUsage converts token usage and cost as reported in a resource status
+func FromStatusTokenUsage(tokens arkv1alpha1.TokenUsage, cost string) TokenUsage {
+	usage := TokenUsage{
+		PromptTokens:     tokens.PromptTokens,
+		CompletionTokens: tokens.CompletionTokens,
+		TotalTokens:      tokens.TotalTokens,
+	}
+	if cost != "" {
+		usage.Cost, _ = strconv.ParseFloat(cost, 64)
+	}
+	return usage
+}
+
+func queryServiceAccount(query *arkv1alpha1.Query) string {
+	if query.Spec.ServiceAccount == "" {
+		return "default"
+	}
+	return query.Spec.ServiceAccount
+}
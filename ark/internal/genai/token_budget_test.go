@@ -0,0 +1,189 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func budgetTestQuery(name string, age time.Duration, serviceAccount string, responses ...arkv1alpha1.Response) *arkv1alpha1.Query {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "team-a",
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+		},
+		Spec:   arkv1alpha1.QuerySpec{ServiceAccount: serviceAccount},
+		Status: arkv1alpha1.QueryStatus{Responses: responses},
+	}
+	for _, response := range responses {
+		query.Spec.Targets = append(query.Spec.Targets, response.Target)
+		query.Status.TokenUsage.TotalTokens += response.TokenUsage.TotalTokens
+	}
+	return query
+}
+
+func TestTokenBudgetUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+
+	weather := arkv1alpha1.Response{
+		Target:     arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"},
+		TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 300},
+		Cost:       "0.030000",
+	}
+	summary := arkv1alpha1.Response{
+		Target:     arkv1alpha1.QueryTarget{Type: "agent", Name: "summary"},
+		TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 200},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		budgetTestQuery("recent", time.Hour, "", weather, summary),
+		budgetTestQuery("batch", 2*time.Hour, "batch", weather),
+		budgetTestQuery("old", 48*time.Hour, "", weather),
+	).Build()
+
+	maxTokens := int64(1000)
+	budget := func(scope arkv1alpha1.TokenBudgetScope) *arkv1alpha1.TokenBudget {
+		return &arkv1alpha1.TokenBudget{
+			ObjectMeta: metav1.ObjectMeta{Name: "budget", Namespace: "team-a"},
+			Spec:       arkv1alpha1.TokenBudgetSpec{Scope: scope, MaxTokens: &maxTokens},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		scope  arkv1alpha1.TokenBudgetScope
+		tokens int64
+	}{
+		{name: "namespace", scope: arkv1alpha1.TokenBudgetScope{}, tokens: 800},
+		{name: "service account", scope: arkv1alpha1.TokenBudgetScope{Type: TokenBudgetScopeServiceAccount, Name: "default"}, tokens: 500},
+		{name: "agent", scope: arkv1alpha1.TokenBudgetScope{Type: TokenBudgetScopeAgent, Name: "weather"}, tokens: 600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, err := TokenBudgetUsage(context.Background(), k8sClient, budget(tt.scope), time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tt.tokens, usage.TotalTokens)
+		})
+	}
+
+	usage, err := TokenBudgetUsage(context.Background(), k8sClient, budget(arkv1alpha1.TokenBudgetScope{Type: TokenBudgetScopeAgent, Name: "weather"}), time.Now())
+	require.NoError(t, err)
+	assert.InDelta(t, 0.06, usage.Cost, 1e-9)
+}
+
+func TestCheckTokenBudget(t *testing.T) {
+	maxTokens := int64(1000)
+	budget := &arkv1alpha1.TokenBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily"},
+		Spec:       arkv1alpha1.TokenBudgetSpec{MaxTokens: &maxTokens, MaxCost: "5"},
+	}
+
+	assert.NoError(t, CheckTokenBudget(budget, TokenUsage{TotalTokens: 999, Cost: 4.99}))
+
+	err := CheckTokenBudget(budget, TokenUsage{TotalTokens: 1000})
+	assert.True(t, IsTokenBudgetExceeded(err))
+	assert.Contains(t, err.Error(), "used 1000 of 1000 tokens in the last 24h0m0s")
+
+	err = CheckTokenBudget(budget, TokenUsage{TotalTokens: 10, Cost: 5})
+	assert.True(t, IsTokenBudgetExceeded(err))
+
+	assert.Error(t, ValidateTokenBudget(&arkv1alpha1.TokenBudget{}))
+	assert.Error(t, ValidateTokenBudget(&arkv1alpha1.TokenBudget{Spec: arkv1alpha1.TokenBudgetSpec{
+		Scope:     arkv1alpha1.TokenBudgetScope{Type: TokenBudgetScopeAgent},
+		MaxTokens: &maxTokens,
+	}}))
+	assert.NoError(t, ValidateTokenBudget(budget))
+}
+
+func TestRecordTokenBudgetUsage(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	ctx := context.Background()
+
+	weather := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+	unrecorded := budgetTestQuery("unrecorded", time.Hour, "", arkv1alpha1.Response{Target: weather, TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 100}})
+	unrecorded.UID = "unrecorded"
+	running := budgetTestQuery("running", time.Minute, "")
+	running.UID = "running"
+	budget := &arkv1alpha1.TokenBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "budget", Namespace: "team-a"},
+		Status: arkv1alpha1.TokenBudgetStatus{Consumers: []arkv1alpha1.TokenBudgetConsumer{{
+			UID:        "expired",
+			Started:    metav1.NewTime(time.Now().Add(-48 * time.Hour)),
+			TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 1000},
+		}}},
+	}
+	k8sClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&arkv1alpha1.TokenBudget{}).
+		WithObjects(budget, unrecorded, running).
+		Build()
+	key := types.NamespacedName{Name: "budget", Namespace: "team-a"}
+
+	usage := func() int64 {
+		var latest arkv1alpha1.TokenBudget
+		require.NoError(t, k8sClient.Get(ctx, key, &latest))
+		usage, err := TokenBudgetUsage(ctx, k8sClient, &latest, time.Now())
+		require.NoError(t, err)
+		return usage.TotalTokens
+	}
+	assert.Equal(t, int64(100), usage(), "usage that left the window does not count")
+
+	consumer := arkv1alpha1.TokenBudgetConsumer{UID: running.UID, Kind: "Query", Name: running.Name, Started: running.CreationTimestamp}
+	_, err := RecordTokenBudgetUsage(ctx, k8sClient, key, consumer, TokenUsage{TotalTokens: 40, Cost: 0.01})
+	require.NoError(t, err)
+	recorded, err := RecordTokenBudgetUsage(ctx, k8sClient, key, consumer, TokenUsage{TotalTokens: 25, Cost: 0.02})
+	require.NoError(t, err)
+	require.Len(t, recorded.Status.Consumers, 1, "consumers that left the window are dropped")
+	assert.Equal(t, int64(65), recorded.Status.Consumers[0].TokenUsage.TotalTokens)
+	assert.Equal(t, "0.030000", recorded.Status.Consumers[0].Cost)
+	assert.Equal(t, int64(165), usage(), "a running query counts before its status reports usage")
+
+	// Once the query has finished its status has the usage, which must not be counted twice
+	running.Status.TokenUsage.TotalTokens = 65
+	require.NoError(t, k8sClient.Update(ctx, running))
+	assert.Equal(t, int64(165), usage())
+
+	require.NoError(t, k8sClient.Delete(ctx, running))
+	assert.Equal(t, int64(165), usage(), "deleting a query does not give its usage back")
+}
+
+func TestTokenBudgetUsageCountsWorkflows(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+
+	workflow := &arkv1alpha1.Workflow{
+		ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "team-a", CreationTimestamp: metav1.Now()},
+		Spec: arkv1alpha1.WorkflowSpec{Steps: []arkv1alpha1.WorkflowStep{
+			{Name: "search", Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}},
+			{Name: "write", Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "summary"}},
+		}},
+		Status: arkv1alpha1.WorkflowStatus{Steps: []arkv1alpha1.WorkflowStepStatus{
+			{Name: "search", Phase: "done", TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 70}},
+			{Name: "write", Phase: "running"},
+		}},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workflow).Build()
+
+	budget := &arkv1alpha1.TokenBudget{ObjectMeta: metav1.ObjectMeta{Name: "budget", Namespace: "team-a"}}
+	usage, err := TokenBudgetUsage(context.Background(), k8sClient, budget, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(70), usage.TotalTokens)
+
+	budget.Spec.Scope = arkv1alpha1.TokenBudgetScope{Type: TokenBudgetScopeAgent, Name: "summary"}
+	usage, err = TokenBudgetUsage(context.Background(), k8sClient, budget, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, usage.TotalTokens)
+}
@@ -4,13 +4,18 @@ package genai
 
 import (
 	"context"
+	"slices"
 	"sync"
 )
 
+// modelUsedMetadataKey is the completion metadata naming the model that answered a model call
+const modelUsedMetadataKey = "modelUsed"
+
 type TokenUsageCollector struct {
 	recorder    EventEmitter
 	mu          sync.RWMutex
 	tokenUsages []TokenUsage
+	models      []string
 }
 
 func NewTokenUsageCollector(recorder EventEmitter) *TokenUsageCollector {
@@ -23,11 +28,25 @@ func NewTokenUsageCollector(recorder EventEmitter) *TokenUsageCollector {
 func (c *TokenUsageCollector) EmitEvent(ctx context.Context, eventType string, data EventData) {
 	c.recorder.EmitEvent(ctx, eventType, data)
 
-	if opEvent, ok := data.(OperationEvent); ok && opEvent.TokenUsage.TotalTokens > 0 {
-		c.mu.Lock()
+	opEvent, ok := data.(OperationEvent)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if opEvent.TokenUsage.TotalTokens > 0 {
 		c.tokenUsages = append(c.tokenUsages, opEvent.TokenUsage)
-		c.mu.Unlock()
 	}
+	if model := opEvent.Metadata[modelUsedMetadataKey]; model != "" && !slices.Contains(c.models, model) {
+		c.models = append(c.models, model)
+	}
+}
+
+// GetModels returns the models that answered model calls, in the order they were first used
+func (c *TokenUsageCollector) GetModels() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.models)
 }
 
 func (c *TokenUsageCollector) GetTokenSummary() TokenUsage {
@@ -39,6 +58,7 @@ func (c *TokenUsageCollector) GetTokenSummary() TokenUsage {
 		total.PromptTokens += usage.PromptTokens
 		total.CompletionTokens += usage.CompletionTokens
 		total.TotalTokens += usage.TotalTokens
+		total.Cost += usage.Cost
 	}
 
 	return total
@@ -47,5 +67,6 @@ func (c *TokenUsageCollector) GetTokenSummary() TokenUsage {
 func (c *TokenUsageCollector) Reset() {
 	c.mu.Lock()
 	c.tokenUsages = make([]TokenUsage, 0)
+	c.models = nil
 	c.mu.Unlock()
 }
@@ -4,16 +4,20 @@ package genai
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 )
 
 type mockRecorder struct {
+	mu     sync.Mutex
 	events []EventData
 }
 
 func (m *mockRecorder) EmitEvent(ctx context.Context, eventType string, data EventData) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.events = append(m.events, data)
 }
 
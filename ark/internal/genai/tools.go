@@ -22,9 +22,10 @@ import (
 )
 
 type ToolDefinition struct {
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Parameters  map[string]any `json:"parameters"`
+	Name        string                       `json:"name"`
+	Description string                       `json:"description"`
+	Parameters  map[string]any               `json:"parameters"`
+	Annotations *arkv1alpha1.ToolAnnotations `json:"annotations,omitempty"`
 }
 
 // HTTPExecutor executes HTTP tools
@@ -195,6 +196,23 @@ func (tr *ToolRegistry) RegisterTool(def ToolDefinition, executor ToolExecutor)
 	tr.executors[def.Name] = executor
 }
 
+// clone returns a registry with the same tools, sharing the MCP client pool, that tools can be added to without
+// changing the original
+func (tr *ToolRegistry) clone() *ToolRegistry {
+	clone := &ToolRegistry{
+		tools:     make(map[string]ToolDefinition, len(tr.tools)),
+		executors: make(map[string]ToolExecutor, len(tr.executors)),
+		mcpPool:   tr.mcpPool,
+	}
+	for name, def := range tr.tools {
+		clone.tools[name] = def
+	}
+	for name, executor := range tr.executors {
+		clone.executors[name] = executor
+	}
+	return clone
+}
+
 func (tr *ToolRegistry) GetToolDefinitions() []ToolDefinition {
 	definitions := make([]ToolDefinition, 0, len(tr.tools))
 	for _, def := range tr.tools {
@@ -209,11 +227,19 @@ func (tr *ToolRegistry) GetToolType(toolName string) string {
 		return "unknown"
 	}
 
-	switch executor.(type) {
+	switch e := executor.(type) {
 	case *NoopExecutor:
 		return "builtin"
 	case *TerminateExecutor:
 		return "builtin"
+	case *DelegateExecutor:
+		return "builtin"
+	case *ScratchpadExecutor:
+		return "builtin"
+	case *HandoffExecutor:
+		return "builtin"
+	case *MemberToolExecutor:
+		return e.MemberType
 	case *HTTPExecutor:
 		return "custom"
 	case *MCPExecutor:
@@ -225,6 +251,26 @@ func (tr *ToolRegistry) GetToolType(toolName string) string {
 	}
 }
 
+// IsParallelSafe reports whether a tool is annotated as read-only or idempotent,
+// which makes it safe to run concurrently with other calls from the same turn
+func (tr *ToolRegistry) IsParallelSafe(toolName string) bool {
+	def, exists := tr.tools[toolName]
+	if !exists || def.Annotations == nil {
+		return false
+	}
+	return def.Annotations.ReadOnlyHint || def.Annotations.IdempotentHint
+}
+
+// RequiresApproval reports whether a tool is annotated as destructive, in which case each call
+// has to be approved before it runs
+func (tr *ToolRegistry) RequiresApproval(toolName string) bool {
+	def, exists := tr.tools[toolName]
+	if !exists || def.Annotations == nil {
+		return false
+	}
+	return def.Annotations.DestructiveHint
+}
+
 func (tr *ToolRegistry) ExecuteTool(ctx context.Context, call ToolCall) (ToolResult, error) {
 	executor, exists := tr.executors[call.Function.Name]
 	if !exists {
@@ -275,6 +321,7 @@ func GetNoopTool() ToolDefinition {
 	return ToolDefinition{
 		Name:        "noop",
 		Description: "A no-operation tool that does nothing and returns success",
+		Annotations: &arkv1alpha1.ToolAnnotations{ReadOnlyHint: true},
 		Parameters: map[string]any{
 			"type": "object",
 			"properties": map[string]any{
@@ -390,7 +437,7 @@ func CreateToolFromCRD(toolCRD *arkv1alpha1.Tool) ToolDefinition {
 		}
 	}
 
-	return ToolDefinition{Name: toolCRD.Name, Description: description, Parameters: parameters}
+	return ToolDefinition{Name: toolCRD.Name, Description: description, Parameters: parameters, Annotations: toolCRD.Spec.Annotations}
 }
 
 func CreateHTTPTool(toolCRD *arkv1alpha1.Tool) ToolDefinition {
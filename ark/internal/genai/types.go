@@ -2,7 +2,10 @@ package genai
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
+	"fmt"
 
 	"github.com/openai/openai-go"
 )
@@ -31,6 +34,50 @@ func ToolMessage[T string | []openai.ChatCompletionContentPartTextParam](content
 	return Message(openai.ToolMessage(content, toolCallID))
 }
 
+// messageText returns the text content of a message of any role, with the text of content parts joined
+func messageText(msg Message) string {
+	var text string
+	switch {
+	case msg.OfSystem != nil:
+		text = msg.OfSystem.Content.OfString.Value
+		for _, part := range msg.OfSystem.Content.OfArrayOfContentParts {
+			text += part.Text
+		}
+	case msg.OfDeveloper != nil:
+		text = msg.OfDeveloper.Content.OfString.Value
+		for _, part := range msg.OfDeveloper.Content.OfArrayOfContentParts {
+			text += part.Text
+		}
+	case msg.OfUser != nil:
+		text = msg.OfUser.Content.OfString.Value
+		for _, part := range msg.OfUser.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				text += part.OfText.Text
+			}
+		}
+	case msg.OfAssistant != nil:
+		text = msg.OfAssistant.Content.OfString.Value
+		for _, part := range msg.OfAssistant.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				text += part.OfText.Text
+			}
+		}
+	case msg.OfTool != nil:
+		text = msg.OfTool.Content.OfString.Value
+		for _, part := range msg.OfTool.Content.OfArrayOfContentParts {
+			text += part.Text
+		}
+	}
+	return text
+}
+
+// newToolCallID returns an ID for a tool call of a provider that does not return one
+func newToolCallID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return "call_" + hex.EncodeToString(b)
+}
+
 type TeamMember interface {
 	Execute(ctx context.Context, userInput Message, history []Message) ([]Message, error)
 	GetName() string
@@ -62,3 +109,48 @@ func IsTerminateTeam(err error) bool {
 	var terminateErr *TerminateTeam
 	return errors.As(err, &terminateErr)
 }
+
+// ExecutionLimitReached is returned with the messages produced so far when an agent stops because
+// one of its configured limits was hit
+type ExecutionLimitReached struct {
+	Agent  string
+	Reason string
+	Limit  int64
+}
+
+func (e *ExecutionLimitReached) Error() string {
+	return fmt.Sprintf("agent %s reached %s limit of %d", e.Agent, e.Reason, e.Limit)
+}
+
+func IsExecutionLimitReached(err error) bool {
+	if err == nil {
+		return false
+	}
+	var limitErr *ExecutionLimitReached
+	return errors.As(err, &limitErr)
+}
+
+// TerminationReason returns the limit that ended the execution, or an empty string if err is not an ExecutionLimitReached
+func TerminationReason(err error) string {
+	var limitErr *ExecutionLimitReached
+	if errors.As(err, &limitErr) {
+		return limitErr.Reason
+	}
+	return ""
+}
+
+// APIError is an error status returned by a model API that Ark calls over plain HTTP
+type APIError struct {
+	Source     string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s returned %d: %s", e.Source, e.StatusCode, e.Message)
+}
+
+// HTTPStatusCode matches the AWS SDK response errors, so that all providers report their status the same way
+func (e *APIError) HTTPStatusCode() int {
+	return e.StatusCode
+}
@@ -0,0 +1,145 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ValidateWorkflowSteps checks that step names are unique, that dependencies name other steps, and that the
+// dependencies do not form a cycle
+func ValidateWorkflowSteps(steps []arkv1alpha1.WorkflowStep) error {
+	indexes := make(map[string]int, len(steps))
+	for i, step := range steps {
+		if _, exists := indexes[step.Name]; exists {
+			return fmt.Errorf("step %s is defined more than once", step.Name)
+		}
+		indexes[step.Name] = i
+	}
+
+	for _, step := range steps {
+		switch step.Target.Type {
+		case "agent", "team", "model", "tool":
+		default:
+			return fmt.Errorf("step %s has unsupported target type %q", step.Name, step.Target.Type)
+		}
+		for _, dependency := range step.DependsOn {
+			if dependency == step.Name {
+				return fmt.Errorf("step %s depends on itself", step.Name)
+			}
+			if _, exists := indexes[dependency]; !exists {
+				return fmt.Errorf("step %s depends on unknown step %s", step.Name, dependency)
+			}
+		}
+	}
+
+	// Kahn's algorithm: every step can be ordered only if there is no cycle
+	remaining := make(map[string]int, len(steps))
+	dependents := make(map[string][]string, len(steps))
+	for _, step := range steps {
+		remaining[step.Name] = len(step.DependsOn)
+		for _, dependency := range step.DependsOn {
+			dependents[dependency] = append(dependents[dependency], step.Name)
+		}
+	}
+	var ready []string
+	for _, step := range steps {
+		if remaining[step.Name] == 0 {
+			ready = append(ready, step.Name)
+		}
+	}
+	ordered := 0
+	for len(ready) > 0 {
+		name := ready[0]
+		ready = ready[1:]
+		ordered++
+		for _, dependent := range dependents[name] {
+			remaining[dependent]--
+			if remaining[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if ordered != len(steps) {
+		var cyclic []string
+		for _, step := range steps {
+			if remaining[step.Name] > 0 {
+				cyclic = append(cyclic, step.Name)
+			}
+		}
+		return fmt.Errorf("steps %s have cyclic dependencies", strings.Join(cyclic, ", "))
+	}
+	return nil
+}
+
+// WorkflowOutputSteps returns the steps no other step depends on, whose outputs make up the workflow output
+func WorkflowOutputSteps(steps []arkv1alpha1.WorkflowStep) []string {
+	dependedOn := map[string]bool{}
+	for _, step := range steps {
+		for _, dependency := range step.DependsOn {
+			dependedOn[dependency] = true
+		}
+	}
+	var outputs []string
+	for _, step := range steps {
+		if !dependedOn[step.Name] {
+			outputs = append(outputs, step.Name)
+		}
+	}
+	return outputs
+}
+
+// ResolveWorkflowStepInput renders the input of a step. Steps without an input template receive the workflow
+// input if they have no dependencies, and the outputs of their dependencies otherwise.
+func ResolveWorkflowStepInput(ctx context.Context, k8sClient client.Client, workflow *arkv1alpha1.Workflow, step arkv1alpha1.WorkflowStep, outputs map[string]string) (string, error) {
+	input, err := ResolveQueryInput(ctx, k8sClient, workflow.Namespace, workflow.Spec.Input, AsQueryParameters(workflow.Spec.Parameters))
+	if err != nil {
+		return "", err
+	}
+
+	if step.Input == "" {
+		if len(step.DependsOn) == 0 {
+			return input, nil
+		}
+		dependencyOutputs := make([]string, 0, len(step.DependsOn))
+		for _, dependency := range step.DependsOn {
+			dependencyOutputs = append(dependencyOutputs, outputs[dependency])
+		}
+		return strings.Join(dependencyOutputs, "\n\n"), nil
+	}
+
+	templateData := make(map[string]any)
+	if len(workflow.Spec.Parameters) > 0 {
+		parameters, err := resolveQueryParameters(ctx, k8sClient, workflow.Namespace, AsQueryParameters(workflow.Spec.Parameters))
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve parameters: %w", err)
+		}
+		for name, value := range parameters {
+			templateData[name] = value
+		}
+	}
+	stepOutputs := make(map[string]string, len(step.DependsOn))
+	for _, dependency := range step.DependsOn {
+		stepOutputs[dependency] = outputs[dependency]
+	}
+	templateData["input"] = input
+	templateData["steps"] = stepOutputs
+
+	tmpl, err := template.New("step-input").Option("missingkey=error").Parse(step.Input)
+	if err != nil {
+		return "", fmt.Errorf("invalid template syntax in input of step %s: %w", step.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, templateData); err != nil {
+		return "", fmt.Errorf("template execution failed for step %s: %w", step.Name, err)
+	}
+	return buf.String(), nil
+}
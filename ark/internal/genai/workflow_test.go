@@ -0,0 +1,79 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func workflowStep(name string, dependsOn ...string) arkv1alpha1.WorkflowStep {
+	return arkv1alpha1.WorkflowStep{
+		Name:      name,
+		Target:    arkv1alpha1.QueryTarget{Type: "agent", Name: name},
+		DependsOn: dependsOn,
+	}
+}
+
+func TestValidateWorkflowSteps(t *testing.T) {
+	assert.NoError(t, ValidateWorkflowSteps([]arkv1alpha1.WorkflowStep{
+		workflowStep("research"),
+		workflowStep("pricing"),
+		workflowStep("summary", "research", "pricing"),
+	}))
+
+	tests := []struct {
+		name  string
+		steps []arkv1alpha1.WorkflowStep
+		err   string
+	}{
+		{"duplicate", []arkv1alpha1.WorkflowStep{workflowStep("a"), workflowStep("a")}, "more than once"},
+		{"unknown dependency", []arkv1alpha1.WorkflowStep{workflowStep("a", "b")}, "unknown step b"},
+		{"self dependency", []arkv1alpha1.WorkflowStep{workflowStep("a", "a")}, "depends on itself"},
+		{"cycle", []arkv1alpha1.WorkflowStep{workflowStep("a", "c"), workflowStep("b", "a"), workflowStep("c", "b"), workflowStep("d")}, "steps a, b, c have cyclic dependencies"},
+		{"target type", []arkv1alpha1.WorkflowStep{{Name: "a", Target: arkv1alpha1.QueryTarget{Type: "workflow", Name: "a"}}}, "unsupported target type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, ValidateWorkflowSteps(tt.steps), tt.err)
+		})
+	}
+}
+
+func TestWorkflowOutputSteps(t *testing.T) {
+	steps := []arkv1alpha1.WorkflowStep{
+		workflowStep("research"),
+		workflowStep("summary", "research"),
+		workflowStep("audit", "research"),
+	}
+	assert.Equal(t, []string{"summary", "audit"}, WorkflowOutputSteps(steps))
+}
+
+func TestResolveWorkflowStepInput(t *testing.T) {
+	workflow := &arkv1alpha1.Workflow{Spec: arkv1alpha1.WorkflowSpec{Input: "Plan a launch"}}
+	outputs := map[string]string{"research": "market notes", "pricing": "price table"}
+
+	input, err := ResolveWorkflowStepInput(context.Background(), nil, workflow, workflowStep("research"), outputs)
+	require.NoError(t, err)
+	assert.Equal(t, "Plan a launch", input)
+
+	input, err = ResolveWorkflowStepInput(context.Background(), nil, workflow, workflowStep("summary", "research", "pricing"), outputs)
+	require.NoError(t, err)
+	assert.Equal(t, "market notes\n\nprice table", input)
+
+	join := workflowStep("summary", "research", "pricing")
+	join.Input = "{{.input}}: combine {{.steps.research}} with {{.steps.pricing}}"
+	input, err = ResolveWorkflowStepInput(context.Background(), nil, workflow, join, outputs)
+	require.NoError(t, err)
+	assert.Equal(t, "Plan a launch: combine market notes with price table", input)
+
+	// Only the outputs of the steps it depends on are available to a step
+	join.Input = "{{.steps.audit}}"
+	_, err = ResolveWorkflowStepInput(context.Background(), nil, workflow, join, outputs)
+	assert.Error(t, err)
+}
@@ -168,7 +168,7 @@ func AddModelDetails(span trace.Span, modelName, modelType, provider string, pro
 	}
 }
 
-func AddLLMTokenUsage(span trace.Span, promptTokens, completionTokens, totalTokens int64) {
+func AddLLMTokenUsage(span trace.Span, promptTokens, completionTokens, totalTokens int64, cost float64) {
 	// Enhanced token usage with OpenTelemetry GenAI semantic conventions
 	span.SetAttributes(
 		// Standard token usage
@@ -186,6 +186,15 @@ func AddLLMTokenUsage(span trace.Span, promptTokens, completionTokens, totalToke
 		attribute.Int64("usage.output_tokens", completionTokens),
 		attribute.Int64("usage.total_tokens", totalTokens),
 	)
+
+	// Cost is only known for models with pricing
+	if cost > 0 {
+		span.SetAttributes(
+			attribute.Float64("llm.usage.cost", cost),
+			attribute.Float64("gen_ai.usage.cost", cost),
+			attribute.Float64("usage.cost", cost),
+		)
+	}
 }
 
 // ExtractMessageContentForTelemetry extracts content from OpenAI union message types for telemetry
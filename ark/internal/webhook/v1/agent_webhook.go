@@ -68,6 +68,9 @@ func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1al
 	}
 
 	for i, tool := range agent.Spec.Tools {
+		if tool.Type == "agent" && tool.Name == agent.Name {
+			return warnings, fmt.Errorf("tool[%d]: agent '%s' cannot use itself as a tool", i, tool.Name)
+		}
 		toolWarnings, err := v.validateTool(ctx, agent.Namespace, i, tool)
 		if err != nil {
 			return warnings, err
@@ -114,7 +117,7 @@ func (v *AgentCustomValidator) validateBuiltInTool(tool arkv1alpha1.AgentTool, h
 		return fmt.Errorf("tool[%d]: built-in tools must specify a name", index)
 	}
 	if !isValidBuiltInTool(tool.Name) {
-		return fmt.Errorf("tool[%d]: unsupported built-in tool '%s': supported built-in tools are: noop, terminate", index, tool.Name)
+		return fmt.Errorf("tool[%d]: unsupported built-in tool '%s': supported built-in tools are: noop, terminate, scratchpad, handoff", index, tool.Name)
 	}
 	return nil
 }
@@ -144,17 +147,40 @@ func (v *AgentCustomValidator) validateTool(ctx context.Context, namespace strin
 		}
 	case "custom":
 		return v.validateCustomTool(ctx, namespace, tool, hasName, index)
+	case "agent", "team":
+		if err := v.validateMemberTool(ctx, namespace, tool, hasName, index); err != nil {
+			return warnings, err
+		}
 	default:
-		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, custom, mcp", index, tool.Type)
+		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, custom, mcp, agent, team", index, tool.Type)
 	}
 
 	return warnings, nil
 }
 
+func (v *AgentCustomValidator) validateMemberTool(ctx context.Context, namespace string, tool arkv1alpha1.AgentTool, hasName bool, index int) error {
+	if !hasName {
+		return fmt.Errorf("tool[%d]: %s tools must specify a name", index, tool.Type)
+	}
+
+	var err error
+	if tool.Type == "agent" {
+		err = v.ValidateLoadAgent(ctx, tool.Name, namespace)
+	} else {
+		err = v.ValidateLoadTeam(ctx, tool.Name, namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("tool[%d]: %s", index, err)
+	}
+	return nil
+}
+
 func isValidBuiltInTool(name string) bool {
 	validBuiltInTools := map[string]bool{
-		"noop":      true,
-		"terminate": true,
+		"noop":       true,
+		"terminate":  true,
+		"scratchpad": true,
+		"handoff":    true,
 	}
 	return validBuiltInTools[name]
 }
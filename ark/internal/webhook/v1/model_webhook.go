@@ -84,6 +84,10 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 		return nil, err
 	}
 
+	if err := v.validateFallbacks(ctx, model); err != nil {
+		return nil, err
+	}
+
 	modellog.Info("Model validation complete", "name", model.GetName())
 
 	return nil, nil
@@ -97,11 +101,36 @@ func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv
 		return v.validateOpenAIConfig(ctx, model)
 	case genai.ModelTypeBedrock:
 		return v.validateBedrockConfig(ctx, model)
+	case genai.ModelTypeAnthropic:
+		return v.validateAnthropicConfig(ctx, model)
+	case genai.ModelTypeGemini:
+		return v.validateGeminiConfig(ctx, model)
+	case genai.ModelTypeLocal:
+		return v.validateLocalConfig(ctx, model)
+	case genai.ModelTypePool:
+		return v.validatePoolConfig(ctx, model)
 	default:
 		return fmt.Errorf("unsupported model type: %s", model.Spec.Type)
 	}
 }
 
+// validateFallbacks checks that the fallback models exist and that the chain does not call a model twice
+func (v *ModelValidator) validateFallbacks(ctx context.Context, model *arkv1alpha1.Model) error {
+	seen := map[string]bool{model.GetNamespace() + "/" + model.GetName(): true}
+	for i, ref := range model.Spec.Fallbacks {
+		name, namespace := genai.ResolveModelSpec(&ref, model.GetNamespace())
+		if seen[namespace+"/"+name] {
+			return fmt.Errorf("spec.fallbacks[%d]: model %s/%s is already in the fallback chain", i, namespace, name)
+		}
+		seen[namespace+"/"+name] = true
+
+		if err := v.Validator.ValidateLoadModel(ctx, name, namespace); err != nil {
+			return fmt.Errorf("spec.fallbacks[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
 func (v *ModelValidator) validateAzureConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	if model.Spec.Config.Azure == nil {
 		return fmt.Errorf("azure configuration is required for azure model type")
@@ -149,6 +178,104 @@ func (v *ModelValidator) validateOpenAIConfig(ctx context.Context, model *arkv1a
 	return nil
 }
 
+func (v *ModelValidator) validateAnthropicConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Anthropic == nil {
+		return fmt.Errorf("anthropic configuration is required for anthropic model type")
+	}
+
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Anthropic.APIKey, model.GetNamespace(), "spec.config.anthropic.apiKey"); err != nil {
+		return err
+	}
+	if model.Spec.Config.Anthropic.BaseURL != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Anthropic.BaseURL, model.GetNamespace(), "spec.config.anthropic.baseUrl"); err != nil {
+			return err
+		}
+	}
+	if model.Spec.Config.Anthropic.APIVersion != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Anthropic.APIVersion, model.GetNamespace(), "spec.config.anthropic.apiVersion"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (v *ModelValidator) validateGeminiConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Gemini == nil {
+		return fmt.Errorf("gemini configuration is required for gemini model type")
+	}
+
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Gemini.APIKey, model.GetNamespace(), "spec.config.gemini.apiKey"); err != nil {
+		return err
+	}
+	if err := v.validateValueSource(ctx, model.Spec.Config.Gemini.BaseURL, model.GetNamespace(), "spec.config.gemini.baseUrl"); err != nil {
+		return err
+	}
+	if err := v.validateValueSource(ctx, model.Spec.Config.Gemini.Project, model.GetNamespace(), "spec.config.gemini.project"); err != nil {
+		return err
+	}
+	if err := v.validateValueSource(ctx, model.Spec.Config.Gemini.Location, model.GetNamespace(), "spec.config.gemini.location"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (v *ModelValidator) validateLocalConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Local == nil {
+		return fmt.Errorf("local configuration is required for local model type")
+	}
+
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Local.BaseURL, model.GetNamespace(), "spec.config.local.baseUrl"); err != nil {
+		return err
+	}
+	if err := v.validateValueSource(ctx, model.Spec.Config.Local.APIKey, model.GetNamespace(), "spec.config.local.apiKey"); err != nil {
+		return err
+	}
+	if model.Spec.Config.Local.Timeout != nil && model.Spec.Config.Local.Timeout.Duration <= 0 {
+		return fmt.Errorf("spec.config.local.timeout must be positive")
+	}
+
+	_, err := v.Resolver.ResolveValueSource(ctx, model.Spec.Config.Local.BaseURL, model.GetNamespace())
+	if err != nil {
+		modellog.Error(err, "Failed to resolve local BaseURL", "model", model.GetName())
+		return fmt.Errorf("failed to resolve local BaseURL: %w", err)
+	}
+
+	return nil
+}
+
+// validatePoolConfig checks that the backends of a pool exist and are not pools themselves
+func (v *ModelValidator) validatePoolConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Pool == nil {
+		return fmt.Errorf("pool configuration is required for pool model type")
+	}
+
+	seen := map[string]bool{}
+	for i, backendRef := range model.Spec.Config.Pool.Backends {
+		if backendRef.Name == model.GetName() {
+			return fmt.Errorf("spec.config.pool.backends[%d]: a pool cannot be its own backend", i)
+		}
+		if seen[backendRef.Name] {
+			return fmt.Errorf("spec.config.pool.backends[%d]: model %s is already a backend of the pool", i, backendRef.Name)
+		}
+		seen[backendRef.Name] = true
+
+		var backend arkv1alpha1.Model
+		if err := v.Client.Get(ctx, client.ObjectKey{Name: backendRef.Name, Namespace: model.GetNamespace()}, &backend); err != nil {
+			return fmt.Errorf("spec.config.pool.backends[%d]: failed to get model %s: %w", i, backendRef.Name, err)
+		}
+		if backend.Spec.Type == genai.ModelTypePool {
+			return fmt.Errorf("spec.config.pool.backends[%d]: model %s is a pool, which cannot be a backend", i, backendRef.Name)
+		}
+	}
+	if model.Spec.Config.Pool.Cooldown != nil && model.Spec.Config.Pool.Cooldown.Duration < 0 {
+		return fmt.Errorf("spec.config.pool.cooldown must not be negative")
+	}
+
+	return nil
+}
+
 func (v *ModelValidator) validateBedrockConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	if model.Spec.Config.Bedrock == nil {
 		return fmt.Errorf("bedrock configuration is required for bedrock model type")
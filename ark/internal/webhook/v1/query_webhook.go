@@ -5,13 +5,17 @@ package v1
 import (
 	"context"
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
 )
 
 const (
@@ -48,7 +52,16 @@ func (v *QueryCustomValidator) ValidateCreate(ctx context.Context, obj runtime.O
 	}
 	log.V(3).Info("Validate create", "query", query.ObjectMeta)
 
-	return v.validateQuery(ctx, query)
+	warnings, err := v.validateQuery(ctx, query)
+	if err != nil {
+		return warnings, err
+	}
+
+	// Only new queries are held to budgets, so running queries can still be approved or canceled
+	if err := v.validateTokenBudgets(ctx, query); err != nil {
+		return warnings, err
+	}
+	return warnings, nil
 }
 
 func (v *QueryCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
@@ -80,10 +93,24 @@ func (v *QueryCustomValidator) validateQuery(ctx context.Context, query *arkv1al
 		return warnings, err
 	}
 
-	if err := v.ValidateParameters(ctx, query.Namespace, query.Spec.Parameters); err != nil {
+	if err := v.ValidateQueryParameters(ctx, query.Namespace, query.Spec.Parameters); err != nil {
 		return warnings, err
 	}
 
+	// The referenced queries may not exist yet, the query waits for them to be created and finish
+	for _, ref := range genai.QueryParameterRefs(query.Namespace, query.Spec.Parameters) {
+		if ref.Name == query.Name && ref.Namespace == query.Namespace {
+			return warnings, fmt.Errorf("query cannot use its own response as a parameter")
+		}
+	}
+	cycle, err := genai.QueryParameterCycle(ctx, v.Client, query)
+	if err != nil {
+		return warnings, err
+	}
+	if cycle != nil {
+		return warnings, fmt.Errorf("query parameters form a cycle, the queries would wait for each other forever: %s", genai.FormatQueryPath(query.Namespace, cycle))
+	}
+
 	if err := v.validateEvaluators(ctx, query); err != nil {
 		return warnings, err
 	}
@@ -143,3 +170,52 @@ func (v *QueryCustomValidator) validateEvaluators(ctx context.Context, query *ar
 
 	return nil
 }
+
+// validateTokenBudgets rejects a query when a TokenBudget that covers it has already been used up
+func (v *QueryCustomValidator) validateTokenBudgets(ctx context.Context, query *arkv1alpha1.Query) error {
+	budgets, err := genai.LoadTokenBudgets(ctx, v.Client, query.Namespace)
+	if err != nil {
+		return err
+	}
+
+	targets, err := v.budgetTargets(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for i := range budgets {
+		if !genai.TokenBudgetCoversQuery(&budgets[i], query, targets) {
+			continue
+		}
+		usage, err := genai.TokenBudgetUsage(ctx, v.Client, &budgets[i], now)
+		if err != nil {
+			return err
+		}
+		if err := genai.CheckTokenBudget(&budgets[i], usage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// budgetTargets returns the targets of query with the agents its selector picks, as budgets can be scoped to an agent
+func (v *QueryCustomValidator) budgetTargets(ctx context.Context, query *arkv1alpha1.Query) ([]arkv1alpha1.QueryTarget, error) {
+	targets := append([]arkv1alpha1.QueryTarget{}, query.Spec.Targets...)
+	if query.Spec.Selector == nil {
+		return targets, nil
+	}
+
+	labelSelector, err := metav1.LabelSelectorAsSelector(query.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	var agents arkv1alpha1.AgentList
+	if err := v.Client.List(ctx, &agents, &client.ListOptions{Namespace: query.Namespace, LabelSelector: labelSelector}); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+	for _, agent := range agents.Items {
+		targets = append(targets, arkv1alpha1.QueryTarget{Type: TargetTypeAgent, Name: agent.Name})
+	}
+	return targets, nil
+}
@@ -3,11 +3,20 @@
 package v1
 
 import (
+	"context"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
-	// TODO (user): Add any additional imports if needed
+	"mckinsey.com/ark/internal/genai"
 )
 
 var _ = Describe("Query Webhook", func() {
@@ -54,3 +63,167 @@ var _ = Describe("Query Webhook", func() {
 		// })
 	})
 })
+
+var _ = Describe("Query Webhook token budgets", func() {
+	var (
+		ctx       context.Context
+		validator *QueryCustomValidator
+		k8sClient client.Client
+		query     *arkv1alpha1.Query
+	)
+
+	maxTokens := int64(1000)
+	agentTarget := arkv1alpha1.QueryTarget{Type: TargetTypeAgent, Name: "weather"}
+
+	finishedQuery := func(name string, tokens int64) *arkv1alpha1.Query {
+		return &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), CreationTimestamp: metav1.Now()},
+			Spec:       arkv1alpha1.QuerySpec{Targets: []arkv1alpha1.QueryTarget{agentTarget}},
+			Status:     arkv1alpha1.QueryStatus{TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: tokens}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"}},
+			&arkv1alpha1.TokenBudget{
+				ObjectMeta: metav1.ObjectMeta{Name: "daily", Namespace: "default"},
+				Spec:       arkv1alpha1.TokenBudgetSpec{MaxTokens: &maxTokens, Window: &metav1.Duration{Duration: time.Hour}},
+			},
+		).Build()
+		validator = &QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: k8sClient}}
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "default"},
+			Spec:       arkv1alpha1.QuerySpec{Input: "weather in Paris?", Targets: []arkv1alpha1.QueryTarget{agentTarget}},
+		}
+	})
+
+	It("Should admit a query while the budget has room", func() {
+		Expect(k8sClient.Create(ctx, finishedQuery("earlier", 600))).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should reject a query once finished queries used up the budget", func() {
+		Expect(k8sClient.Create(ctx, finishedQuery("earlier", 1000))).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, query)
+		Expect(err).To(HaveOccurred())
+		Expect(genai.IsTokenBudgetExceeded(err)).To(BeTrue())
+	})
+
+	It("Should count usage recorded in the budget after its queries are gone", func() {
+		var budget arkv1alpha1.TokenBudget
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "daily", Namespace: "default"}, &budget)).To(Succeed())
+		budget.Status.Consumers = []arkv1alpha1.TokenBudgetConsumer{{
+			UID:        types.UID("uid-deleted"),
+			Kind:       "Query",
+			Name:       "deleted",
+			Started:    metav1.Now(),
+			TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 1200},
+		}}
+		Expect(k8sClient.Update(ctx, &budget)).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, query)
+		Expect(genai.IsTokenBudgetExceeded(err)).To(BeTrue())
+	})
+
+	It("Should hold queries that pick an agent through their selector to the agent's budget", func() {
+		var agent arkv1alpha1.Agent
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "weather", Namespace: "default"}, &agent)).To(Succeed())
+		agent.Labels = map[string]string{"team": "forecasts"}
+		Expect(k8sClient.Update(ctx, &agent)).To(Succeed())
+		var budget arkv1alpha1.TokenBudget
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: "daily", Namespace: "default"}, &budget)).To(Succeed())
+		budget.Spec.Scope = arkv1alpha1.TokenBudgetScope{Type: genai.TokenBudgetScopeAgent, Name: "weather"}
+		Expect(k8sClient.Update(ctx, &budget)).To(Succeed())
+		earlier := finishedQuery("earlier", 1000)
+		earlier.Status.Responses = []arkv1alpha1.Response{{Target: agentTarget, TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 1000}}}
+		Expect(k8sClient.Create(ctx, earlier)).To(Succeed())
+
+		query.Spec.Targets = nil
+		query.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "forecasts"}}
+		_, err := validator.ValidateCreate(ctx, query)
+		Expect(genai.IsTokenBudgetExceeded(err)).To(BeTrue())
+
+		query.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"team": "travel"}}
+		_, err = validator.ValidateCreate(ctx, query)
+		Expect(genai.IsTokenBudgetExceeded(err)).To(BeFalse(), "a selector that picks other agents is not held to the budget")
+	})
+
+	It("Should not hold updates to budgets", func() {
+		Expect(k8sClient.Create(ctx, finishedQuery("earlier", 1000))).To(Succeed())
+
+		_, err := validator.ValidateUpdate(ctx, query, query)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("Query Webhook query parameters", func() {
+	var (
+		ctx       context.Context
+		validator *QueryCustomValidator
+		k8sClient client.Client
+	)
+
+	newQuery := func(name string, parameters ...arkv1alpha1.QueryParameter) *arkv1alpha1.Query {
+		return &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: arkv1alpha1.QuerySpec{
+				Input:      "Summarize: {{.findings}}",
+				Parameters: parameters,
+				Targets:    []arkv1alpha1.QueryTarget{{Type: TargetTypeAgent, Name: "weather"}},
+			},
+		}
+	}
+
+	fromQuery := func(name string) arkv1alpha1.QueryParameter {
+		return arkv1alpha1.QueryParameter{
+			Name:      "findings",
+			ValueFrom: &arkv1alpha1.QueryParameterSource{QueryRef: &arkv1alpha1.QueryRef{Name: name}},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default"}},
+		).Build()
+		validator = &QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: k8sClient}}
+	})
+
+	It("Should admit a reference to a query that does not exist yet", func() {
+		_, err := validator.ValidateCreate(ctx, newQuery("summary", fromQuery("research")))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Should reject a queryRef combined with another source", func() {
+		param := fromQuery("research")
+		param.ValueFrom.SecretKeyRef = &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "findings"}, Key: "text"}
+
+		_, err := validator.ValidateCreate(ctx, newQuery("summary", param))
+		Expect(err).To(MatchError(ContainSubstring("exactly one source")))
+	})
+
+	It("Should reject a query that closes a cycle of query references", func() {
+		Expect(k8sClient.Create(ctx, newQuery("research", fromQuery("review")))).To(Succeed())
+		Expect(k8sClient.Create(ctx, newQuery("review", fromQuery("summary")))).To(Succeed())
+
+		_, err := validator.ValidateCreate(ctx, newQuery("summary", fromQuery("research")))
+		Expect(err).To(MatchError(ContainSubstring("summary -> research -> review -> summary")))
+
+		_, err = validator.ValidateCreate(ctx, newQuery("summary", fromQuery("other")))
+		Expect(err).NotTo(HaveOccurred(), "a chain that does not lead back is admitted")
+	})
+
+	It("Should reject a query that uses its own response", func() {
+		_, err := validator.ValidateCreate(ctx, newQuery("summary", fromQuery("summary")))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -136,9 +136,19 @@ func (v *TeamCustomValidator) validateStrategy(ctx context.Context, team *arkv1a
 	case "selector":
 		return v.validateSelectorModel(ctx, team)
 	case "graph":
-		return v.validateGraphStrategy(team)
+		if err := v.validateGraphStrategy(team); err != nil {
+			return err
+		}
+		if hasDescribedGraphEdges(team) {
+			return v.validateSelectorModel(ctx, team)
+		}
+		return nil
+	case "parallel":
+		return v.validateParallelStrategy(ctx, team)
+	case "planner":
+		return v.validatePlannerStrategy(ctx, team)
 	default:
-		return fmt.Errorf("unsupported strategy '%s': must be 'sequential', 'round-robin', 'selector', or 'graph'", team.Spec.Strategy)
+		return fmt.Errorf("unsupported strategy '%s': must be 'sequential', 'round-robin', 'selector', 'graph', 'parallel', or 'planner'", team.Spec.Strategy)
 	}
 }
 
@@ -169,7 +179,7 @@ func (v *TeamCustomValidator) validateGraphStrategy(team *arkv1alpha1.Team) erro
 		memberNames[member.Name] = true
 	}
 
-	transitionMap := make(map[string]bool)
+	defaultEdges := make(map[string]bool)
 	for i, edge := range team.Spec.Graph.Edges {
 		if !memberNames[edge.From] {
 			return fmt.Errorf("graph edge %d: 'from' member '%s' not found in team members", i, edge.From)
@@ -177,11 +187,128 @@ func (v *TeamCustomValidator) validateGraphStrategy(team *arkv1alpha1.Team) erro
 		if !memberNames[edge.To] {
 			return fmt.Errorf("graph edge %d: 'to' member '%s' not found in team members", i, edge.To)
 		}
-		if _, exists := transitionMap[edge.From]; exists {
-			return fmt.Errorf("member '%s' has more than one outgoing edge", edge.From)
+		if edge.Condition == nil {
+			if defaultEdges[edge.From] {
+				return fmt.Errorf("member '%s' has more than one outgoing edge without a condition", edge.From)
+			}
+			defaultEdges[edge.From] = true
 		}
-		transitionMap[edge.From] = true
+		if err := genai.ValidateTeamGraphEdge(edge); err != nil {
+			return fmt.Errorf("graph edge %d: %v", i, err)
+		}
+	}
+
+	if team.Spec.MaxTurns == nil && graphHasCycle(team.Spec.Graph.Edges) {
+		return fmt.Errorf("graph strategy with cycles requires maxTurns")
 	}
 
 	return nil
 }
+
+func (v *TeamCustomValidator) validateParallelStrategy(ctx context.Context, team *arkv1alpha1.Team) error {
+	parallel := team.Spec.Parallel
+	if parallel == nil {
+		return nil
+	}
+
+	switch parallel.Aggregation {
+	case "", genai.AggregationConcat, genai.AggregationVote:
+		return nil
+	case genai.AggregationAggregator:
+		aggregator := parallel.Aggregator
+		if aggregator == nil {
+			return fmt.Errorf("aggregator aggregation requires an aggregator")
+		}
+		var err error
+		switch aggregator.Type {
+		case MemberTypeAgent:
+			err = v.ValidateLoadAgent(ctx, aggregator.Name, team.Namespace)
+		case MemberTypeTeam:
+			if aggregator.Name == team.Name {
+				return fmt.Errorf("aggregator: team '%s' cannot reference itself", aggregator.Name)
+			}
+			err = v.ValidateLoadTeam(ctx, aggregator.Name, team.Namespace)
+		default:
+			return fmt.Errorf("aggregator has invalid type '%s': must be '%s' or '%s'", aggregator.Type, MemberTypeAgent, MemberTypeTeam)
+		}
+		if err != nil {
+			return fmt.Errorf("aggregator references %s: %v", aggregator.Type, err)
+		}
+		return nil
+	case genai.AggregationModel:
+		modelName, namespace := genai.ResolveModelSpec(parallel.Model, team.Namespace)
+		if err := v.ValidateLoadModel(ctx, modelName, namespace); err != nil {
+			return fmt.Errorf("aggregation model %s not found in namespace %s: %v", modelName, namespace, err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported aggregation '%s': must be 'concat', 'aggregator', 'model', or 'vote'", parallel.Aggregation)
+	}
+}
+
+func (v *TeamCustomValidator) validatePlannerStrategy(ctx context.Context, team *arkv1alpha1.Team) error {
+	if team.Spec.Planner == nil || team.Spec.Planner.Manager == "" {
+		return fmt.Errorf("planner strategy requires a manager agent")
+	}
+
+	var manager arkv1alpha1.Agent
+	key := types.NamespacedName{Name: team.Spec.Planner.Manager, Namespace: team.Namespace}
+	if err := v.Client.Get(ctx, key, &manager); err != nil {
+		return fmt.Errorf("failed to load manager agent '%s': %v", team.Spec.Planner.Manager, err)
+	}
+	if manager.Spec.ExecutionEngine != nil {
+		return fmt.Errorf("manager agent '%s' cannot use an execution engine", manager.Name)
+	}
+
+	return nil
+}
+
+func hasDescribedGraphEdges(team *arkv1alpha1.Team) bool {
+	if team.Spec.Graph == nil {
+		return false
+	}
+	for _, edge := range team.Spec.Graph.Edges {
+		if edge.Condition != nil && edge.Condition.Description != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func graphHasCycle(edges []arkv1alpha1.TeamGraphEdge) bool {
+	outgoing := make(map[string][]string)
+	for _, edge := range edges {
+		outgoing[edge.From] = append(outgoing[edge.From], edge.To)
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var visit func(string) bool
+	visit = func(member string) bool {
+		switch state[member] {
+		case visiting:
+			return true
+		case visited:
+			return false
+		}
+		state[member] = visiting
+		for _, next := range outgoing[member] {
+			if visit(next) {
+				return true
+			}
+		}
+		state[member] = visited
+		return false
+	}
+
+	for member := range outgoing {
+		if state[member] == unvisited && visit(member) {
+			return true
+		}
+	}
+	return false
+}
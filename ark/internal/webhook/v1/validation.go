@@ -248,6 +248,39 @@ func (v *ResourceValidator) ValidateParameters(ctx context.Context, namespace st
 	return nil
 }
 
+// ValidateQueryParameters validates the parameters of a query, which can also take the response of another query
+func (v *ResourceValidator) ValidateQueryParameters(ctx context.Context, namespace string, parameters []arkv1alpha1.QueryParameter) error {
+	for i, param := range parameters {
+		if param.ValueFrom == nil || param.ValueFrom.QueryRef == nil {
+			if err := v.validateSingleParameter(ctx, namespace, queryParameterAsParameter(param), i); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// The referenced query may not exist yet, the query waits for it to be created and finish
+		if err := v.validateParameterBasics(queryParameterAsParameter(param), i); err != nil {
+			return err
+		}
+		if param.Value != "" {
+			return fmt.Errorf("parameter[%d] '%s': cannot specify both value and valueFrom", i, param.Name)
+		}
+		source := param.ValueFrom.ValueFromSource
+		if source.ConfigMapKeyRef != nil || source.SecretKeyRef != nil || source.ServiceRef != nil {
+			return fmt.Errorf("parameter[%d] '%s': valueFrom must specify exactly one source", i, param.Name)
+		}
+	}
+	return nil
+}
+
+func queryParameterAsParameter(param arkv1alpha1.QueryParameter) arkv1alpha1.Parameter {
+	parameter := arkv1alpha1.Parameter{Name: param.Name, Value: param.Value}
+	if param.ValueFrom != nil {
+		parameter.ValueFrom = &param.ValueFrom.ValueFromSource
+	}
+	return parameter
+}
+
 // ValidatePollInterval validates that poll interval is not negative
 func ValidatePollInterval(pollInterval time.Duration) error {
 	if pollInterval < 0 {
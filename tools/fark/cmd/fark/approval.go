@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	approvalDecisionApprove = "approve"
+	approvalDecisionDeny    = "deny"
+)
+
+type ApprovalRequest struct {
+	ToolCallIDs []string `json:"toolCallIds,omitempty"`
+	Decision    string   `json:"decision"`
+	Reason      string   `json:"reason,omitempty"`
+}
+
+// submitApprovals appends decisions to spec.approvals of the query. Without tool call IDs the decision
+// applies to every call currently awaiting approval. It returns the IDs that were decided.
+func submitApprovals(config *Config, queryName, namespace string, req ApprovalRequest) ([]string, error) {
+	query, err := getExistingQuery(config, queryName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decisions name the target and agent of the call, so only calls that are pending can be decided
+	var calls []arkv1alpha1.PendingApproval
+	for _, pending := range query.Status.PendingApprovals {
+		if len(req.ToolCallIDs) == 0 || slices.Contains(req.ToolCallIDs, pending.ToolCallID) {
+			calls = append(calls, pending)
+		}
+	}
+	for _, id := range req.ToolCallIDs {
+		if !slices.ContainsFunc(calls, func(p arkv1alpha1.PendingApproval) bool { return p.ToolCallID == id }) {
+			return nil, fmt.Errorf("tool call %s is not awaiting approval", id)
+		}
+	}
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("query %s has no tool calls awaiting approval", queryName)
+	}
+
+	approvals := query.Spec.Approvals
+	var toolCallIDs []string
+	for _, call := range calls {
+		approvals = append(approvals, arkv1alpha1.ToolApproval{
+			ToolCallID: call.ToolCallID,
+			Target:     call.Target,
+			Agent:      call.Agent,
+			Decision:   req.Decision,
+			Reason:     req.Reason,
+		})
+		toolCallIDs = append(toolCallIDs, call.ToolCallID)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"approvals": approvals},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = config.DynamicClient.Resource(GetGVR(ResourceQuery)).Namespace(namespace).Patch(
+		context.TODO(),
+		queryName,
+		types.MergePatchType,
+		patch,
+		metav1.PatchOptions{},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update approvals: %v", err)
+	}
+	return toolCallIDs, nil
+}
+
+func createApprovalCommand(config *Config, decision, short string) *cobra.Command {
+	var namespace string
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   decision + " <query-name> [tool-call-id...]",
+		Short: short,
+		Long: `Record a decision for tool calls that a query is holding because the tool is annotated as destructive.
+
+When no tool call IDs are given, the decision applies to every call listed in the query's status.pendingApprovals.
+Use --reason to tell the agent why (most useful with deny).`,
+		Example: fmt.Sprintf(`  fark %[1]s my-query
+  fark %[1]s my-query call_abc123 -n production
+  fark %[1]s my-query --reason "checked with the data owner"`, decision),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			decided, err := submitApprovals(config, args[0], ns, ApprovalRequest{
+				ToolCallIDs: args[1:],
+				Decision:    decision,
+				Reason:      reason,
+			})
+			if err != nil {
+				return err
+			}
+			for _, id := range decided {
+				fmt.Fprintf(os.Stdout, "%s: %s\n", id, decision)
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return getResourceCompletions(config, "queries", namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason passed to the agent along with the decision")
+	return cmd
+}
+
+// handleQueryApproval records a decision for a query's pending tool calls (POST /approval/<query-name>)
+func handleQueryApproval(config *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		queryName := extractNameFromPath(r.URL.Path, "/approval/")
+		if queryName == "" {
+			http.Error(w, "query name is required in path", http.StatusBadRequest)
+			return
+		}
+
+		var req ApprovalRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request: invalid JSON: %v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Decision != approvalDecisionApprove && req.Decision != approvalDecisionDeny {
+			http.Error(w, "decision must be approve or deny", http.StatusBadRequest)
+			return
+		}
+
+		decided, err := submitApprovals(config, queryName, config.Namespace, req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"toolCallIds": decided, "decision": req.Decision})
+	}
+}
+
+// printPendingApprovals tells the user which tool calls are waiting and how to decide them
+func printPendingApprovals(query *arkv1alpha1.Query, seen map[string]bool) {
+	for _, pending := range query.Status.PendingApprovals {
+		if seen[pending.ToolCallID] {
+			continue
+		}
+		seen[pending.ToolCallID] = true
+		fmt.Fprintf(os.Stderr, "\r%s %s %s %s\n", colorize("approval required", "33"), pending.Tool, pending.Arguments, colorize(pending.ToolCallID, "90"))
+		fmt.Fprintf(os.Stderr, "  fark approve %s %s   |   fark deny %s %s\n", query.Name, pending.ToolCallID, query.Name, pending.ToolCallID)
+	}
+}
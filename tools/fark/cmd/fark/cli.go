@@ -48,16 +48,33 @@ func handleEvent(result *QueryResult, logger *zap.Logger, opts *OutputOptions) {
 	displayEvent(logger, result.Event, opts)
 }
 
-// handleQueryCompletion processes completed queries
-func handleQueryCompletion(result *QueryResult, id *ResourceIdentifier, opts *OutputOptions) error {
+// handleQueryCompletion processes completed queries; responses already printed while streaming are not repeated
+func handleQueryCompletion(result *QueryResult, id *ResourceIdentifier, opts *OutputOptions, printer *streamPrinter) error {
+	streamed := printer.hasContent
 	if result.Phase == "done" {
-		printQueryResults(result.Query, opts.OutputMode)
+		if streamed {
+			printer.finish(result.Query)
+		} else {
+			printQueryResults(result.Query, opts.OutputMode)
+		}
 		cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
 		return nil
 	}
 
 	if result.Phase == "error" {
-		errorMessage := getQueryErrorFromEvents(id.Config.DynamicClient, id.Name, id.Namespace, id.Config.Logger)
+		var errorMessage string
+		if failed := countFailedTargets(result.Query); failed > 0 {
+			// Targets that succeeded still have their responses
+			if streamed {
+				printer.finish(result.Query)
+				printTargetFailures(result.Query)
+			} else {
+				printQueryResults(result.Query, opts.OutputMode)
+			}
+			errorMessage = fmt.Sprintf("%d of %d targets failed", failed, len(result.Query.Status.Responses))
+		} else {
+			errorMessage = getQueryErrorFromEvents(id.Config.DynamicClient, id.Name, id.Namespace, id.Config.Logger)
+		}
 		cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
 		return fmt.Errorf("query failed: %s", errorMessage)
 	}
@@ -77,6 +94,8 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 
 	spinner.Start()
 	var queryCompletionResult *QueryResult
+	printer := &streamPrinter{}
+	seenApprovals := map[string]bool{}
 
 	for {
 		select {
@@ -84,12 +103,20 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 			if !ok {
 				// Channel closed - grace period expired, we can exit now
 				if queryCompletionResult != nil {
-					return handleQueryCompletion(queryCompletionResult, id, opts)
+					return handleQueryCompletion(queryCompletionResult, id, opts, printer)
 				}
 				return fmt.Errorf("result channel closed unexpectedly")
 			}
 
-			handleSpinnerCommands(spinner, result.SpinnerCommand)
+			// Once partial output is on screen the spinner would overwrite it
+			if !printer.started {
+				handleSpinnerCommands(spinner, result.SpinnerCommand)
+			}
+
+			if opts.Stream && opts.OutputMode == "text" && len(result.Chunks) > 0 {
+				spinner.Stop()
+				printer.print(result.Chunks, opts)
+			}
 
 			if result.Error != nil {
 				return handleResultError(&result, id)
@@ -100,6 +127,10 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 				continue
 			}
 
+			if result.Phase == "awaiting-approval" {
+				printPendingApprovals(result.Query, seenApprovals)
+			}
+
 			isQueryCompleted := result.Query != nil && result.Done
 			if isQueryCompleted && queryCompletionResult == nil {
 				// Store the completion result but continue processing events
@@ -168,8 +199,103 @@ func printQueryResults(query *arkv1alpha1.Query, outputMode string) {
 
 	// Display responses
 	for _,response := range query.Status.Responses {
+		if response.Phase == "error" {
+			continue
+		}
 		fmt.Printf("%s\n", response.Content)
+		if response.TerminationReason != "" {
+			fmt.Fprintf(os.Stderr, "%s/%s stopped early: %s limit reached\n", response.Target.Type, response.Target.Name, response.TerminationReason)
+		}
+	}
+	printTargetFailures(query)
+}
+
+func countFailedTargets(query *arkv1alpha1.Query) int {
+	failed := 0
+	for _, response := range query.Status.Responses {
+		if response.Phase == "error" {
+			failed++
+		}
+	}
+	return failed
+}
+
+// printTargetFailures reports the targets of a partially successful query that failed
+func printTargetFailures(query *arkv1alpha1.Query) {
+	for _, response := range query.Status.Responses {
+		if response.Phase == "error" {
+			fmt.Fprintf(os.Stderr, "%s %s/%s: %s\n", colorize("failed", "31"), response.Target.Type, response.Target.Name, response.Error)
+		}
+	}
+}
+
+// streamPrinter writes partial responses to stdout as they arrive
+type streamPrinter struct {
+	started    bool
+	hasContent bool
+	target     arkv1alpha1.QueryTarget
+	message    int
+	// printed is the content of the latest message of each target written so far
+	printed map[arkv1alpha1.QueryTarget]printedMessage
+}
+
+type printedMessage struct {
+	message int
+	content string
+}
+
+func (p *streamPrinter) print(chunks []StreamChunk, opts *OutputOptions) {
+	p.started = true
+	for _, chunk := range chunks {
+		if chunk.ToolCall != nil {
+			if !opts.Quiet {
+				fmt.Fprintf(os.Stderr, "%s %s\n", colorize("tool "+chunk.ToolCall.Name, "36"), chunk.ToolCall.Phase)
+			}
+			continue
+		}
+
+		if p.hasContent && (chunk.Target != p.target || chunk.Message != p.message) {
+			fmt.Println()
+		}
+		p.hasContent = true
+		p.target = chunk.Target
+		p.message = chunk.Message
+		fmt.Print(chunk.Content)
+
+		if p.printed == nil {
+			p.printed = map[arkv1alpha1.QueryTarget]printedMessage{}
+		}
+		printed := p.printed[chunk.Target]
+		if printed.message != chunk.Message {
+			printed = printedMessage{message: chunk.Message}
+		}
+		printed.content += chunk.Content
+		p.printed[chunk.Target] = printed
+	}
+}
+
+// finish writes the part of each final response that had not been streamed yet, as the last partial output
+// may not have reached the query before it completed
+func (p *streamPrinter) finish(query *arkv1alpha1.Query) {
+	for _, response := range query.Status.Responses {
+		if response.Phase == "error" {
+			continue
+		}
+		printed := p.printed[response.Target].content
+		remainder := response.Content
+		if printed != "" && strings.HasPrefix(response.Content, printed) {
+			remainder = response.Content[len(printed):]
+		}
+		if remainder == "" {
+			continue
+		}
+		if response.Target != p.target || remainder == response.Content {
+			fmt.Println()
+		}
+		p.target = response.Target
+		fmt.Print(remainder)
 	}
+	fmt.Println()
 }
 
 // displayEventAsJSON handles JSON output for events
@@ -117,6 +117,7 @@ func (cf *CommandFactory) createTargetCommand(targetType ResourceType, targetNam
 		SessionId:         f.sessionId,
 		Evaluators:        f.evaluators,
 		EvaluatorSelector: f.evaluatorSelector,
+		Stream:            f.stream,
 		ExecutionContext: ExecutionContext{
 			Config:     cf.config,
 			Namespace:  ns,
@@ -263,7 +264,7 @@ func (cf *CommandFactory) showToolParameterHelp(toolName, namespace string) erro
 }
 
 // convertParametersWithTypes converts parameter values to appropriate types based on tool schema
-func (cf *CommandFactory) convertParametersWithTypes(toolName, namespace string, params []arkv1alpha1.Parameter) (map[string]interface{}, error) {
+func (cf *CommandFactory) convertParametersWithTypes(toolName, namespace string, params []arkv1alpha1.QueryParameter) (map[string]interface{}, error) {
 	// Get tool schema
 	toolGVR := GetGVR(ResourceTool)
 	toolResource, err := cf.config.DynamicClient.Resource(toolGVR).Namespace(namespace).Get(
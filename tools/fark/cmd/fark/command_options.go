@@ -36,6 +36,7 @@ type TargetCommand struct {
 	SessionId         string
 	Evaluators        []string
 	EvaluatorSelector []string
+	Stream            bool
 	ExecutionContext
 }
 
@@ -56,6 +57,7 @@ func (c *TargetCommand) Run() error {
 	if err != nil {
 		return fmt.Errorf("failed to create query: %v", err)
 	}
+	query.Spec.Stream = c.Stream
 
 	if err := submitQuery(c.Config, query); err != nil {
 		return fmt.Errorf("failed to create query: %v", err)
@@ -79,6 +81,7 @@ func (c *TargetCommand) Run() error {
 		OutputMode: outputMode,
 		Verbose:    c.Verbose,
 		Quiet:      c.Silent,
+		Stream:     c.Stream,
 	}
 	return waitForQueryCompletion(ctx, id, outputOpts)
 }
@@ -92,6 +95,7 @@ type TriggerCommand struct {
 	SessionId         string
 	Evaluators        []string
 	EvaluatorSelector []string
+	Stream            bool
 	ExecutionContext
 }
 
@@ -131,6 +135,7 @@ func (c *TriggerCommand) Run() error {
 	if err != nil {
 		return fmt.Errorf("failed to create triggered query: %v", err)
 	}
+	newQuery.Spec.Stream = c.Stream
 
 	if err := submitQuery(c.Config, newQuery); err != nil {
 		return fmt.Errorf("failed to create triggered query: %v", err)
@@ -156,6 +161,7 @@ func (c *TriggerCommand) Run() error {
 		OutputMode: outputMode,
 		Verbose:    c.Verbose,
 		Quiet:      c.Silent,
+		Stream:     c.Stream,
 	}
 	return waitForQueryCompletion(ctx, id, outputOpts)
 }
@@ -201,7 +207,7 @@ func (c *CreateResource) Run() error {
 
 type QueryExecutionOptions struct {
 	Timeout    time.Duration
-	Parameters []arkv1alpha1.Parameter
+	Parameters []arkv1alpha1.QueryParameter
 	SessionId  string
 }
 
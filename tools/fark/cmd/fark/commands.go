@@ -83,6 +83,7 @@ When triggering a query:
 				SessionId:         f.sessionId,
 				Evaluators:        f.evaluators,
 				EvaluatorSelector: f.evaluatorSelector,
+				Stream:            f.stream,
 				ExecutionContext: ExecutionContext{
 					Config:     config,
 					Namespace:  ns,
@@ -129,6 +130,12 @@ func setupRoutes(config *Config) {
 	http.HandleFunc("/model/", handleQueryResourceWithPath(config, ResourceModel))
 	http.HandleFunc("/tool/", handleQueryResourceWithPath(config, ResourceTool))
 	http.HandleFunc("/query/", handleTriggerQueryByName(config))
+
+	// Approval decisions for tool calls held by a running query (POST only)
+	http.HandleFunc("/approval/", handleQueryApproval(config))
+
+	// Message transcripts of queries created with transcripts enabled (GET only)
+	http.HandleFunc("/transcript/", handleQueryTranscript(config))
 }
 
 func createGetCommand(config *Config) *cobra.Command {
@@ -38,9 +38,14 @@ func (ep *EventProcessor) StreamQueryEvents(ctx context.Context, w http.Response
 			continue
 		}
 
+		for _, chunk := range result.Chunks {
+			ep.writeChunkEvent(w, flusher, chunk)
+		}
+
 		if result.Query != nil {
 			ep.writeQueryEvent(w, flusher, result.Query, result.Phase)
 			if result.Done {
+				ep.writeResponseEvents(w, flusher, result.Query)
 				return
 			}
 		}
@@ -61,6 +66,48 @@ func (ep *EventProcessor) writeQueryEvent(w http.ResponseWriter, flusher http.Fl
 	ep.writeStreamEvent(w, flusher, eventData)
 }
 
+// writeResponseEvents sends one event per target, so clients can tell which targets of a partially failed query answered
+func (ep *EventProcessor) writeResponseEvents(w http.ResponseWriter, flusher http.Flusher, query *arkv1alpha1.Query) {
+	for _, response := range query.Status.Responses {
+		eventData := map[string]any{
+			"type":         "response",
+			"target":       response.Target,
+			"phase":        response.Phase,
+			"tokenUsage":   response.TokenUsage,
+			"messageCount": response.MessageCount,
+		}
+		if response.Phase == "error" {
+			eventData["error"] = response.Error
+		} else {
+			eventData["content"] = response.Content
+		}
+		if response.TerminationReason != "" {
+			eventData["terminationReason"] = response.TerminationReason
+		}
+		if response.Duration != nil {
+			eventData["duration"] = response.Duration.Duration.String()
+		}
+		ep.writeStreamEvent(w, flusher, eventData)
+	}
+}
+
+func (ep *EventProcessor) writeChunkEvent(w http.ResponseWriter, flusher http.Flusher, chunk StreamChunk) {
+	eventData := map[string]any{
+		"type":    "chunk",
+		"target":  chunk.Target,
+		"source":  chunk.Source,
+		"message": chunk.Message,
+	}
+	if chunk.Content != "" {
+		eventData["content"] = chunk.Content
+	}
+	if chunk.ToolCall != nil {
+		eventData["type"] = "tool_call"
+		eventData["toolCall"] = chunk.ToolCall
+	}
+	ep.writeStreamEvent(w, flusher, eventData)
+}
+
 func (ep *EventProcessor) writeKubernetesEvent(w http.ResponseWriter, flusher http.Flusher, eventObj *unstructured.Unstructured) {
 	eventType, _, _ := unstructured.NestedString(eventObj.Object, "type")
 	reason, _, _ := unstructured.NestedString(eventObj.Object, "reason")
@@ -19,6 +19,7 @@ type flags struct {
 	sessionId         string
 	evaluators        []string
 	evaluatorSelector []string
+	stream            bool
 }
 
 func (f *flags) addTo(cmd *cobra.Command) {
@@ -33,6 +34,7 @@ func (f *flags) addTo(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&f.sessionId, "session-id", "", "Session ID to associate with the query")
 	cmd.Flags().StringArrayVar(&f.evaluators, "evaluator", nil, "Evaluator names to assess query performance (can be used multiple times)")
 	cmd.Flags().StringArrayVar(&f.evaluatorSelector, "evaluator-selector", nil, "Label selector for evaluators in key=value format (can be used multiple times)")
+	cmd.Flags().BoolVar(&f.stream, "stream", false, "Print responses as they are generated")
 }
 
 // validate validates the flag combination and sets defaults
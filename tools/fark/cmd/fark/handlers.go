@@ -12,21 +12,21 @@ import (
 )
 
 type TargetQueryRequest struct {
-	Name              string                  `json:"name"`
-	Input             string                  `json:"input"`
-	Parameters        []arkv1alpha1.Parameter `json:"parameters,omitempty"`
-	SessionId         string                  `json:"sessionId,omitempty"`
-	Evaluators        []string                `json:"evaluators,omitempty"`
-	EvaluatorSelector []string                `json:"evaluatorSelector,omitempty"`
+	Name              string                       `json:"name"`
+	Input             string                       `json:"input"`
+	Parameters        []arkv1alpha1.QueryParameter `json:"parameters,omitempty"`
+	SessionId         string                       `json:"sessionId,omitempty"`
+	Evaluators        []string                     `json:"evaluators,omitempty"`
+	EvaluatorSelector []string                     `json:"evaluatorSelector,omitempty"`
 }
 
 type TriggerQueryRequest struct {
-	QueryName         string                  `json:"queryName"`
-	InputOverride     string                  `json:"inputOverride,omitempty"`
-	Parameters        []arkv1alpha1.Parameter `json:"parameters,omitempty"`
-	SessionId         string                  `json:"sessionId,omitempty"`
-	Evaluators        []string                `json:"evaluators,omitempty"`
-	EvaluatorSelector []string                `json:"evaluatorSelector,omitempty"`
+	QueryName         string                       `json:"queryName"`
+	InputOverride     string                       `json:"inputOverride,omitempty"`
+	Parameters        []arkv1alpha1.QueryParameter `json:"parameters,omitempty"`
+	SessionId         string                       `json:"sessionId,omitempty"`
+	Evaluators        []string                     `json:"evaluators,omitempty"`
+	EvaluatorSelector []string                     `json:"evaluatorSelector,omitempty"`
 }
 
 func parseTargetQueryRequest(r *http.Request) (*TargetQueryRequest, error) {
@@ -178,6 +178,8 @@ func handleQueryResourceWithName(config *Config, resourceType ResourceType, w ht
 		http.Error(w, fmt.Sprintf("failed to create query: %v", err), http.StatusInternalServerError)
 		return
 	}
+	// Responses are streamed back to the client, so ask the controller for partial output
+	query.Spec.Stream = true
 
 	if err := submitQuery(config, query); err != nil {
 		http.Error(w, fmt.Sprintf("failed to create query: %v", err), http.StatusInternalServerError)
@@ -234,6 +236,7 @@ func handleTriggerQueryWithName(config *Config, w http.ResponseWriter, r *http.R
 		http.Error(w, fmt.Sprintf("failed to create trigger query: %v", err), http.StatusInternalServerError)
 		return
 	}
+	newQuery.Spec.Stream = true
 
 	if err := submitQuery(config, newQuery); err != nil {
 		http.Error(w, fmt.Sprintf("failed to create triggered query: %v", err), http.StatusInternalServerError)
@@ -81,6 +81,9 @@ agentic workloads across Kubernetes namespaces.`,
 	rootCmd.AddCommand(cf.CreateTargetCommand(ResourceModel, "model [model-name] [query...]", "Query models"))
 	rootCmd.AddCommand(cf.CreateTargetCommand(ResourceTool, "tool [tool-name] [request...]", "Query tools"))
 	rootCmd.AddCommand(createQueryCommand(config))
+	rootCmd.AddCommand(createApprovalCommand(config, approvalDecisionApprove, "Approve tool calls awaiting approval"))
+	rootCmd.AddCommand(createApprovalCommand(config, approvalDecisionDeny, "Deny tool calls awaiting approval"))
+	rootCmd.AddCommand(createTranscriptCommand(config))
 
 	// Add CRUD commands
 	rootCmd.AddCommand(createGetCommand(config))
@@ -33,7 +33,7 @@ func parseEvaluatorSelector(selectorStrings []string) (*metav1.LabelSelector, er
 	}, nil
 }
 
-func createQuery(input string, targets []arkv1alpha1.QueryTarget, namespace string, params []arkv1alpha1.Parameter, sessionId string, evaluators []string, evaluatorSelectorStrings []string) (*arkv1alpha1.Query, error) {
+func createQuery(input string, targets []arkv1alpha1.QueryTarget, namespace string, params []arkv1alpha1.QueryParameter, sessionId string, evaluators []string, evaluatorSelectorStrings []string) (*arkv1alpha1.Query, error) {
 	queryName := fmt.Sprintf("query-%d", time.Now().Unix())
 
 	spec := &arkv1alpha1.QuerySpec{
@@ -150,7 +150,7 @@ func getSessionId(provided, existing string) string {
 	return existing
 }
 
-func createTriggerQuery(existingQuery *arkv1alpha1.Query, input string, params []arkv1alpha1.Parameter, sessionId string, evaluators []string, evaluatorSelectorStrings []string) (*arkv1alpha1.Query, error) {
+func createTriggerQuery(existingQuery *arkv1alpha1.Query, input string, params []arkv1alpha1.QueryParameter, sessionId string, evaluators []string, evaluatorSelectorStrings []string) (*arkv1alpha1.Query, error) {
 	queryName := fmt.Sprintf("trigger-%d", time.Now().Unix())
 
 	spec := &arkv1alpha1.QuerySpec{
@@ -23,6 +23,7 @@ type QueryResult struct {
 	Done           bool
 	Error          error
 	SpinnerCommand string // "start" or "stop"
+	Chunks         []StreamChunk
 }
 
 type QueryWatcher struct {
@@ -30,6 +31,7 @@ type QueryWatcher struct {
 	queryName string
 	namespace string
 	logger    *zap.Logger
+	partials  *partialResponseTracker
 }
 
 func NewQueryWatcher(config *Config, queryName, namespace string, logger *zap.Logger) *QueryWatcher {
@@ -38,6 +40,7 @@ func NewQueryWatcher(config *Config, queryName, namespace string, logger *zap.Lo
 		queryName: queryName,
 		namespace: namespace,
 		logger:    logger,
+		partials:  newPartialResponseTracker(),
 	}
 }
 
@@ -158,13 +161,14 @@ func (qw *QueryWatcher) processQueryEvent(event watch.Event) *QueryResult {
 	}
 
 	result := &QueryResult{
-		Query: query,
-		Phase: query.Status.Phase,
-		Done:  query.Status.Phase == "done" || query.Status.Phase == "error",
+		Query:  query,
+		Phase:  query.Status.Phase,
+		Done:   query.Status.Phase == "done" || query.Status.Phase == "error",
+		Chunks: qw.partials.Diff(query),
 	}
 
-	// Send spinner stop command if query is done or errored
-	if result.Done {
+	// Send spinner stop command if query is done, errored or waiting for the user
+	if result.Done || result.Phase == "awaiting-approval" {
 		result.SpinnerCommand = "stop"
 	} else if result.Phase == "running" && !result.IsEvent {
 		// Start spinner when query is running and it's not just an event update
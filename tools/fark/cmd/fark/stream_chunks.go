@@ -0,0 +1,67 @@
+package main
+
+import (
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// StreamChunk is the part of a target's partial response that arrived since the previous query update
+type StreamChunk struct {
+	Target   arkv1alpha1.QueryTarget       `json:"target"`
+	Source   string                        `json:"source,omitempty"`
+	Message  int                           `json:"message"`
+	Content  string                        `json:"content,omitempty"`
+	ToolCall *arkv1alpha1.ToolCallProgress `json:"toolCall,omitempty"`
+}
+
+type partialResponseState struct {
+	message       int
+	contentLength int
+	toolPhases    map[string]string
+}
+
+// partialResponseTracker turns successive snapshots of status.partialResponses into incremental chunks
+type partialResponseTracker struct {
+	states map[int]*partialResponseState
+}
+
+func newPartialResponseTracker() *partialResponseTracker {
+	return &partialResponseTracker{states: map[int]*partialResponseState{}}
+}
+
+func (t *partialResponseTracker) Diff(query *arkv1alpha1.Query) []StreamChunk {
+	var chunks []StreamChunk
+
+	for i, partial := range query.Status.PartialResponses {
+		state, ok := t.states[i]
+		if !ok || state.message != partial.Message || len(partial.Content) < state.contentLength {
+			state = &partialResponseState{message: partial.Message, toolPhases: map[string]string{}}
+			t.states[i] = state
+		}
+
+		if len(partial.Content) > state.contentLength {
+			chunks = append(chunks, StreamChunk{
+				Target:  partial.Target,
+				Source:  partial.Source,
+				Message: partial.Message,
+				Content: partial.Content[state.contentLength:],
+			})
+			state.contentLength = len(partial.Content)
+		}
+
+		for _, toolCall := range partial.ToolCalls {
+			if state.toolPhases[toolCall.ID] == toolCall.Phase {
+				continue
+			}
+			state.toolPhases[toolCall.ID] = toolCall.Phase
+			progress := toolCall
+			chunks = append(chunks, StreamChunk{
+				Target:   partial.Target,
+				Source:   partial.Source,
+				Message:  partial.Message,
+				ToolCall: &progress,
+			})
+		}
+	}
+
+	return chunks
+}
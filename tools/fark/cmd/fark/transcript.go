@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// TargetTranscript holds every message a query target produced, as stored by the controller
+type TargetTranscript struct {
+	Target   arkv1alpha1.QueryTarget `json:"target"`
+	Phase    string                  `json:"phase,omitempty"`
+	Messages []json.RawMessage       `json:"messages"`
+}
+
+type transcriptMessage struct {
+	Role       string          `json:"role"`
+	Name       string          `json:"name,omitempty"`
+	Content    json.RawMessage `json:"content,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+	ToolCalls  []struct {
+		ID       string `json:"id"`
+		Function struct {
+			Name      string `json:"name"`
+			Arguments string `json:"arguments"`
+		} `json:"function"`
+	} `json:"tool_calls,omitempty"`
+}
+
+// getQueryTranscripts loads the transcripts referenced from the query's responses. When target is set,
+// as "name" or "type/name", only that target's transcript is returned.
+func getQueryTranscripts(config *Config, queryName, namespace, target string) ([]TargetTranscript, error) {
+	query, err := getExistingQuery(config, queryName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !query.Spec.Transcript {
+		return nil, fmt.Errorf("query %s was not created with transcripts enabled (spec.transcript)", queryName)
+	}
+
+	configMaps := map[string]*unstructured.Unstructured{}
+	var transcripts []TargetTranscript
+	for _, response := range query.Status.Responses {
+		if target != "" && target != response.Target.Name && target != response.Target.Type+"/"+response.Target.Name {
+			continue
+		}
+		if response.TranscriptRef == nil {
+			continue
+		}
+
+		ref := response.TranscriptRef
+		configMap, ok := configMaps[ref.Name]
+		if !ok {
+			configMap, err = config.DynamicClient.Resource(GetGVR(ResourceConfigMap)).Namespace(namespace).Get(
+				context.TODO(),
+				ref.Name,
+				metav1.GetOptions{},
+			)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get transcript: %v", err)
+			}
+			configMaps[ref.Name] = configMap
+		}
+
+		data, _, _ := unstructured.NestedString(configMap.Object, "data", ref.Key)
+		transcript := TargetTranscript{Target: response.Target, Phase: response.Phase}
+		if err := json.Unmarshal([]byte(data), &transcript.Messages); err != nil {
+			return nil, fmt.Errorf("failed to parse transcript of %s/%s: %v", response.Target.Type, response.Target.Name, err)
+		}
+		transcripts = append(transcripts, transcript)
+	}
+
+	if len(transcripts) == 0 {
+		if target != "" {
+			return nil, fmt.Errorf("query %s has no transcript for target %s", queryName, target)
+		}
+		return nil, fmt.Errorf("query %s has no transcripts yet", queryName)
+	}
+	return transcripts, nil
+}
+
+func createTranscriptCommand(config *Config) *cobra.Command {
+	var namespace string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "transcript <query-name> [target]",
+		Short: "Show the full message transcript of a query",
+		Long: `Show every message the targets of a query produced, including tool calls, tool results and team member turns.
+
+The query must have been created with spec.transcript set to true. Limit the output to a single target
+by passing its name, or type/name when several targets share a name.`,
+		Example: `  fark transcript my-query
+  fark transcript my-query agent/weather-agent
+  fark transcript my-query --json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target := ""
+			if len(args) > 1 {
+				target = args[1]
+			}
+
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			transcripts, err := getQueryTranscripts(config, args[0], ns, target)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				jsonData, err := json.MarshalIndent(transcripts, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Println(string(jsonData))
+				return nil
+			}
+
+			for _, transcript := range transcripts {
+				printTranscript(transcript)
+			}
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return getResourceCompletions(config, "queries", namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output the raw messages as JSON")
+	return cmd
+}
+
+func printTranscript(transcript TargetTranscript) {
+	header := fmt.Sprintf("%s/%s", transcript.Target.Type, transcript.Target.Name)
+	if transcript.Phase != "" {
+		header += " (" + transcript.Phase + ")"
+	}
+	fmt.Println(colorize("== "+header+" ==", "1"))
+
+	for _, raw := range transcript.Messages {
+		var message transcriptMessage
+		if err := json.Unmarshal(raw, &message); err != nil {
+			fmt.Println(string(raw))
+			continue
+		}
+
+		role := message.Role
+		if message.Name != "" {
+			role += " " + message.Name
+		}
+		if message.ToolCallID != "" {
+			role += " " + message.ToolCallID
+		}
+
+		if content := transcriptContent(message.Content); content != "" {
+			fmt.Printf("%s: %s\n", colorize(role, "36"), content)
+		}
+		for _, toolCall := range message.ToolCalls {
+			fmt.Printf("%s: %s(%s) %s\n", colorize(role, "36"), toolCall.Function.Name, toolCall.Function.Arguments, colorize(toolCall.ID, "90"))
+		}
+	}
+	fmt.Println()
+}
+
+// transcriptContent flattens message content, which is either a string or a list of text parts
+func transcriptContent(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(raw, &parts); err == nil {
+		texts := make([]string, 0, len(parts))
+		for _, part := range parts {
+			texts = append(texts, part.Text)
+		}
+		return strings.Join(texts, "")
+	}
+	return string(raw)
+}
+
+// handleQueryTranscript returns the transcripts of a query (GET /transcript/<query-name>?target=<target>)
+func handleQueryTranscript(config *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		queryName := extractNameFromPath(r.URL.Path, "/transcript/")
+		if queryName == "" {
+			http.Error(w, "query name is required in path", http.StatusBadRequest)
+			return
+		}
+
+		transcripts, err := getQueryTranscripts(config, queryName, config.Namespace, r.URL.Query().Get("target"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		_ = writeJSONResponse(w, transcripts)
+	}
+}
@@ -25,6 +25,8 @@ const (
 	ResourceModel ResourceType = "models"
 	ResourceTool  ResourceType = "tools"
 	ResourceEvent ResourceType = "events"
+
+	ResourceConfigMap ResourceType = "configmaps"
 )
 
 var resourceGVRMap = map[ResourceType]schema.GroupVersionResource{
@@ -34,6 +36,8 @@ var resourceGVRMap = map[ResourceType]schema.GroupVersionResource{
 	ResourceModel: {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "models"},
 	ResourceTool:  {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "tools"},
 	ResourceEvent: {Group: "", Version: "v1", Resource: "events"},
+
+	ResourceConfigMap: {Group: "", Version: "v1", Resource: "configmaps"},
 }
 
 func GetGVR(resourceType ResourceType) schema.GroupVersionResource {
@@ -45,6 +49,7 @@ type OutputOptions struct {
 	OutputMode string // "text" or "json"
 	Verbose    bool   // Show detailed events and logs
 	Quiet      bool   // Suppress events and progress indicators
+	Stream     bool   // Print partial responses as they arrive
 }
 
 // AgentSpec groups agent creation and update parameters
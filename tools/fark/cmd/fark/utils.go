@@ -35,8 +35,8 @@ func readInputFile(filePath string) (string, error) {
 	return string(content), nil
 }
 
-func parseParameters(parameters []string) ([]arkv1alpha1.Parameter, error) {
-	var result []arkv1alpha1.Parameter
+func parseParameters(parameters []string) ([]arkv1alpha1.QueryParameter, error) {
+	var result []arkv1alpha1.QueryParameter
 
 	for _, param := range parameters {
 		parts := strings.SplitN(param, "=", 2)
@@ -51,7 +51,7 @@ func parseParameters(parameters []string) ([]arkv1alpha1.Parameter, error) {
 			return nil, fmt.Errorf("parameter key cannot be empty in: %s", param)
 		}
 
-		result = append(result, arkv1alpha1.Parameter{
+		result = append(result, arkv1alpha1.QueryParameter{
 			Name:  key,
 			Value: value,
 		})
@@ -94,6 +94,27 @@ func logTokenUsage(logger *zap.Logger, query *arkv1alpha1.Query, phase string) {
 			zap.Int64("prompt_tokens", query.Status.TokenUsage.PromptTokens),
 			zap.Int64("completion_tokens", query.Status.TokenUsage.CompletionTokens),
 			zap.Int64("total_tokens", query.Status.TokenUsage.TotalTokens),
+			zap.String("cost", query.Status.Cost),
 		)
 	}
+
+	for _, response := range query.Status.Responses {
+		if response.TokenUsage.TotalTokens == 0 {
+			continue
+		}
+		fields := []zap.Field{
+			zap.String("query", query.Name),
+			zap.String("target", response.Target.Type+"/"+response.Target.Name),
+			zap.String("phase", response.Phase),
+			zap.Int64("total_tokens", response.TokenUsage.TotalTokens),
+			zap.Int("messages", response.MessageCount),
+		}
+		if response.Duration != nil {
+			fields = append(fields, zap.Duration("duration", response.Duration.Duration))
+		}
+		if response.Cost != "" {
+			fields = append(fields, zap.String("cost", response.Cost))
+		}
+		logger.Info("Target tokens", fields...)
+	}
 }